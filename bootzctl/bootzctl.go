@@ -0,0 +1,110 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main implements bootzctl, a command line tool to query and manage a Bootz server
+// through its Admin service, to convert its inventory files and to manage its secret stores.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+var (
+	serverAddr = flag.String("server", "unix:///tmp/bootz.sock", "Address of a Bootz server listener with the Admin service enabled.")
+	timeout    = flag.Duration("timeout", 30*time.Second, "Timeout of each command, except for commands which stream until interrupted.")
+)
+
+// command is a bootzctl subcommand.
+type command struct {
+	name  string
+	usage string
+	help  string
+	// run executes the command with the arguments following its name.
+	run func(ctx context.Context, args []string) error
+	// stream is whether the command runs until interrupted, rather than within the timeout.
+	stream bool
+}
+
+// commands lists the available subcommands.
+var commands = []*command{
+	statusCommand,
+	watchCommand,
+	discoveredCommand,
+	approveCommand,
+	rejectCommand,
+	rmaCommand,
+	faultsCommand,
+	certsCommand,
+	revokeCommand,
+	convertCommand,
+	importCommand,
+	secretCommand,
+}
+
+// dialAdmin connects to the Admin service of the server.
+func dialAdmin(ctx context.Context) (apb.AdminClient, func(), error) {
+	conn, err := grpc.DialContext(ctx, *serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to connect to %v: %v", *serverAddr, err)
+	}
+	return apb.NewAdminClient(conn), func() { conn.Close() }, nil
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: bootzctl [flags] <command> [command flags] [args]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-10s %s\n", c.name, c.help)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if !c.stream {
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		if err := c.run(ctx, args); err != nil {
+			fmt.Fprintf(os.Stderr, "bootzctl %v: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "bootzctl: unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
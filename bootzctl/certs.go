@@ -0,0 +1,138 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const (
+	certsUsage  = "certs [-serial=<serial>] [-all] [-json]"
+	revokeUsage = "revoke <certificate serial>"
+)
+
+var certsCommand = &command{
+	name:  "certs",
+	usage: certsUsage,
+	help:  "List the TLS certificates issued to devices.",
+	run:   runCerts,
+}
+
+var revokeCommand = &command{
+	name:  "revoke",
+	usage: revokeUsage,
+	help:  "Revoke a TLS certificate issued to a device.",
+	run:   runRevoke,
+}
+
+func runCerts(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("certs", flag.ContinueOnError)
+	serial := fs.String("serial", "", "Serial of the control card or chassis to list the certificates of.")
+	all := fs.Bool("all", false, "Also list revoked and superseded certificates.")
+	asJSON := fs.Bool("json", false, "Print the certificates as JSON.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", certsUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	resp, err := client.ListCertificates(ctx, &apb.ListCertificatesRequest{SerialNumber: *serial, All: *all})
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		return printCertificates(os.Stdout, resp)
+	}
+	b, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func runRevoke(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n\nThe device gets a new certificate on its next bootstrap.\n", revokeUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one certificate serial, got %v", fs.Args())
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	c, err := client.RevokeCertificate(ctx, &apb.RevokeCertificateRequest{CertificateSerial: fs.Arg(0)})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Revoked certificate %s of %s device %s\n", c.GetCertificateSerial(), c.GetManufacturer(), c.GetSerialNumber())
+	return nil
+}
+
+// certificateState returns the state of an issued certificate: revoked, superseded, renewal-due or
+// valid.
+func certificateState(c *apb.IssuedCertificate) string {
+	switch {
+	case c.GetRevoked() != nil:
+		return "revoked"
+	case c.GetSupersededBy() != "":
+		return "superseded"
+	case c.GetRenewalDue():
+		return "renewal-due"
+	}
+	return "valid"
+}
+
+// printCertificates prints one line per certificate.
+func printCertificates(w io.Writer, resp *apb.ListCertificatesResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MANUFACTURER\tSERIAL\tCERTIFICATE\tCOMMON NAME\tIPS\tSTATE\tNOT AFTER")
+	for _, c := range resp.GetCertificates() {
+		ips := strings.Join(c.GetIpAddresses(), ",")
+		if ips == "" {
+			ips = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.GetManufacturer(), c.GetSerialNumber(), c.GetCertificateSerial(), c.GetCommonName(), ips, certificateState(c), formatTime(c.GetNotAfter()))
+	}
+	return tw.Flush()
+}
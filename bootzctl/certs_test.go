@@ -0,0 +1,59 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+func TestPrintCertificates(t *testing.T) {
+	var buf bytes.Buffer
+	err := printCertificates(&buf, &apb.ListCertificatesResponse{Certificates: []*apb.IssuedCertificate{{
+		CertificateSerial: "1a2b",
+		Manufacturer:      "Cisco",
+		SerialNumber:      "123A",
+		CommonName:        "core1.example.com",
+		IpAddresses:       []string{"192.0.2.10"},
+		RenewalDue:        true,
+	}, {
+		CertificateSerial: "3c4d",
+		Manufacturer:      "Cisco",
+		SerialNumber:      "456",
+		CommonName:        "456",
+		Revoked:           timestamppb.Now(),
+	}}})
+	if err != nil {
+		t.Fatalf("printCertificates() err = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("printCertificates() printed %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	for i, want := range [][]string{
+		{"Cisco", "123A", "1a2b", "core1.example.com", "192.0.2.10", "renewal-due", "-"},
+		{"Cisco", "456", "3c4d", "456", "-", "revoked", "-"},
+	} {
+		if got := strings.Fields(lines[i+1]); !cmp.Equal(got, want) {
+			t.Errorf("printCertificates() line %d = %v, want %v", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/openconfig/bootz/server/entitymanager"
+)
+
+const convertUsage = "convert [-from=<format>] [-to=<format>] <input> [<output>]"
+
+var convertCommand = &command{
+	name:  "convert",
+	usage: convertUsage,
+	help:  "Convert an inventory file between the prototext, JSON and YAML formats.",
+	run:   runConvert,
+}
+
+func runConvert(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	from := fs.String("from", "", "Format of the input: prototext, json or yaml. Defaults to the format given by the extension of the input.")
+	to := fs.String("to", "", "Format of the output: prototext, json or yaml. Defaults to the format given by the extension of the output, or prototext when writing to stdout.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n\nThe output is written to stdout if no output file is provided. Relative file references in the\ninventory are rewritten to refer to the same files from the directory of the output file.\n\n", convertUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("expected an input and an optional output file, got %v", fs.Args())
+	}
+	in, out := fs.Arg(0), fs.Arg(1)
+	inFormat, err := formatFlag(*from, in)
+	if err != nil {
+		return err
+	}
+	outFormat, err := formatFlag(*to, out)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		return convertInventory(os.Stdout, data, inFormat, outFormat, "", "")
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := convertInventory(f, data, inFormat, outFormat, filepath.Dir(in), filepath.Dir(out)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// formatFlag returns the format set by a flag, or else the format of the file.
+func formatFlag(flagValue, path string) (entitymanager.Format, error) {
+	if flagValue != "" {
+		return entitymanager.ParseFormat(flagValue)
+	}
+	return entitymanager.FormatOf(path), nil
+}
+
+// convertInventory writes an inventory in the output format. Relative file references are
+// rebased from fromDir to toDir, unless toDir is empty.
+func convertInventory(w io.Writer, data []byte, from, to entitymanager.Format, fromDir, toDir string) error {
+	entities, err := entitymanager.UnmarshalEntities(data, from)
+	if err != nil {
+		return fmt.Errorf("invalid %v inventory: %v", from, err)
+	}
+	if toDir != "" {
+		if err := entitymanager.RebasePaths(entities, fromDir, toDir); err != nil {
+			return err
+		}
+	}
+	out, err := entitymanager.MarshalEntities(entities, to)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
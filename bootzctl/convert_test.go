@@ -0,0 +1,80 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/openconfig/bootz/server/entitymanager"
+)
+
+func TestConvert(t *testing.T) {
+	want, err := entitymanager.LoadEntities("../testdata/inventory.prototxt")
+	if err != nil {
+		t.Fatalf("LoadEntities() err = %v", err)
+	}
+	dir := t.TempDir()
+	yml := filepath.Join(dir, "inventory.yaml")
+	js := filepath.Join(dir, "inventory.json")
+	txt := filepath.Join(dir, "inventory.txt")
+	steps := [][]string{
+		{"../testdata/inventory.prototxt", yml},
+		{yml, js},
+		{"-to=textproto", js, txt},
+	}
+	for _, args := range steps {
+		if err := runConvert(context.Background(), args); err != nil {
+			t.Fatalf("runConvert(%v) err = %v", args, err)
+		}
+	}
+	// File references are rebased to the directory of the converted inventories.
+	if err := entitymanager.RebasePaths(want, "../testdata", dir); err != nil {
+		t.Fatalf("RebasePaths() err = %v", err)
+	}
+	authz := want.GetOptions().GetGnsiGlobalConfig().GetAuthzUploadFile()
+	if _, err := os.Stat(filepath.Join(dir, authz)); err != nil {
+		t.Errorf("rebased authz_upload_file %q does not resolve from %v: %v", authz, dir, err)
+	}
+	for _, f := range []string{yml, js} {
+		got, err := entitymanager.LoadEntities(f)
+		if err != nil {
+			t.Fatalf("LoadEntities(%v) err = %v", f, err)
+		}
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("converted inventory %v differs (-want +got):\n%s", f, diff)
+		}
+	}
+	data, err := os.ReadFile(txt)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v", err)
+	}
+	got, err := entitymanager.UnmarshalEntities(data, entitymanager.FormatPrototext)
+	if err != nil {
+		t.Fatalf("UnmarshalEntities() err = %v", err)
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("converted inventory %v differs (-want +got):\n%s", txt, diff)
+	}
+
+	if err := runConvert(context.Background(), []string{"-from=toml", yml}); err == nil {
+		t.Errorf("runConvert() with an unknown format succeeded")
+	}
+}
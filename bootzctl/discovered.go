@@ -0,0 +1,197 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const (
+	discoveryStatePrefix = "DISCOVERY_STATE_"
+	discoveredUsage      = "discovered [-state=<state>[,<state>...]] [-json]"
+	approveUsage         = "approve -profile=<profile> [-name=<name>] [-manufacturer=<name>] <serial>"
+	rejectUsage          = "reject [-reason=<reason>] [-manufacturer=<name>] <serial>"
+)
+
+var discoveredCommand = &command{
+	name:  "discovered",
+	usage: discoveredUsage,
+	help:  "List the devices which requested bootstrap data but are not in the inventory.",
+	run:   runDiscovered,
+}
+
+var approveCommand = &command{
+	name:  "approve",
+	usage: approveUsage,
+	help:  "Add a discovered device to the inventory, configured from a profile.",
+	run:   runApprove,
+}
+
+var rejectCommand = &command{
+	name:  "reject",
+	usage: rejectUsage,
+	help:  "Reject a discovered device.",
+	run:   runReject,
+}
+
+func runDiscovered(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("discovered", flag.ContinueOnError)
+	states := fs.String("state", "pending", "Comma-separated list of states to list devices in: pending or rejected. Empty lists all devices.")
+	asJSON := fs.Bool("json", false, "Print the devices as JSON.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", discoveredUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+	filter, err := parseDiscoveryStates(*states)
+	if err != nil {
+		return err
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	resp, err := client.ListDiscovered(ctx, &apb.ListDiscoveredRequest{States: filter})
+	if err != nil {
+		return err
+	}
+	if !*asJSON {
+		return printDiscovered(os.Stdout, resp)
+	}
+	b, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func runApprove(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("approve", flag.ContinueOnError)
+	profile := fs.String("profile", "", "Name of the inventory profile to configure the device from.")
+	name := fs.String("name", "", "Name of the chassis in the inventory. Defaults to its serial.")
+	manufacturer := fs.String("manufacturer", "", "Manufacturer of the device, to disambiguate serials.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", approveUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one serial, got %v", fs.Args())
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	cs, err := client.ApproveDevice(ctx, &apb.ApproveDeviceRequest{
+		Manufacturer: *manufacturer,
+		SerialNumber: fs.Arg(0),
+		Profile:      *profile,
+		Name:         *name,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Approved %s chassis %s with profile %s\n", cs.GetManufacturer(), cs.GetSerialNumber(), *profile)
+	return nil
+}
+
+func runReject(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("reject", flag.ContinueOnError)
+	reason := fs.String("reason", "", "Why the device is rejected.")
+	manufacturer := fs.String("manufacturer", "", "Manufacturer of the device, to disambiguate serials.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", rejectUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one serial, got %v", fs.Args())
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	d, err := client.RejectDevice(ctx, &apb.RejectDeviceRequest{
+		Manufacturer: *manufacturer,
+		SerialNumber: fs.Arg(0),
+		Reason:       *reason,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rejected %s device %s\n", d.GetManufacturer(), fs.Arg(0))
+	return nil
+}
+
+// parseDiscoveryStates parses a comma-separated list of discovery states, e.g. "pending,rejected".
+func parseDiscoveryStates(s string) ([]apb.DiscoveryState, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var states []apb.DiscoveryState
+	for _, name := range strings.Split(s, ",") {
+		v, ok := apb.DiscoveryState_value[discoveryStatePrefix+strings.ToUpper(strings.TrimSpace(name))]
+		if !ok || v == 0 {
+			return nil, fmt.Errorf("unknown discovery state %q", name)
+		}
+		states = append(states, apb.DiscoveryState(v))
+	}
+	return states, nil
+}
+
+// printDiscovered prints one line per discovered device.
+func printDiscovered(w io.Writer, resp *apb.ListDiscoveredResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MANUFACTURER\tSERIAL\tCONTROL CARDS\tSTATE\tPEER\tNONCE\tREQUESTS\tLAST SEEN")
+	for _, d := range resp.GetDevices() {
+		var cards []string
+		for _, cc := range d.GetControlCards() {
+			cards = append(cards, cc.GetSerialNumber())
+		}
+		cardList := strings.Join(cards, ",")
+		if cardList == "" {
+			cardList = "-"
+		}
+		state := strings.ToLower(strings.TrimPrefix(d.GetState().String(), discoveryStatePrefix))
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%v\t%d\t%s\n", d.GetManufacturer(), d.GetSerialNumber(), cardList, state, d.GetPeerAddress(), d.GetNoncePresent(), d.GetRequestCount(), formatTime(d.GetLastSeen()))
+	}
+	return tw.Flush()
+}
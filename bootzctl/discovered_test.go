@@ -0,0 +1,75 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+func TestParseDiscoveryStates(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []apb.DiscoveryState
+		wantErr bool
+	}{{
+		in: "",
+	}, {
+		in:   "pending, REJECTED",
+		want: []apb.DiscoveryState{apb.DiscoveryState_DISCOVERY_STATE_PENDING, apb.DiscoveryState_DISCOVERY_STATE_REJECTED},
+	}, {
+		in:      "approved",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		got, err := parseDiscoveryStates(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseDiscoveryStates(%q) err = %v, want error %v", test.in, err, test.wantErr)
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("parseDiscoveryStates(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestPrintDiscovered(t *testing.T) {
+	var buf bytes.Buffer
+	err := printDiscovered(&buf, &apb.ListDiscoveredResponse{Devices: []*apb.DiscoveredDevice{{
+		Manufacturer: "Cisco",
+		SerialNumber: "900",
+		ControlCards: []*bpb.ControlCard{{SerialNumber: "900A"}, {SerialNumber: "900B"}},
+		State:        apb.DiscoveryState_DISCOVERY_STATE_PENDING,
+		PeerAddress:  "192.0.2.1:1234",
+		NoncePresent: true,
+		RequestCount: 3,
+	}}})
+	if err != nil {
+		t.Fatalf("printDiscovered() err = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("printDiscovered() printed %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	want := []string{"Cisco", "900", "900A,900B", "pending", "192.0.2.1:1234", "true", "3", "-"}
+	if got := strings.Fields(lines[1]); !cmp.Equal(got, want) {
+		t.Errorf("printDiscovered() line 1 = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+const (
+	faultsUsage = "faults [list] | set <serial> <fault>... | clear <serial>"
+	faultPrefix = "FAULT_"
+)
+
+var faultsCommand = &command{
+	name:  "faults",
+	usage: faultsUsage,
+	help:  "List, inject or clear the faults served to a chassis or control card. The server must run with --fault_injection.",
+	run:   runFaults,
+}
+
+func runFaults(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("faults", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", faultsUsage)
+		fmt.Fprintf(fs.Output(), "Faults: %s\n", strings.Join(faultNames(), ", "))
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	action := "list"
+	if fs.NArg() > 0 {
+		action = fs.Arg(0)
+	}
+	var req *apb.SetFaultsRequest
+	switch action {
+	case "list":
+		if fs.NArg() > 1 {
+			fs.Usage()
+			return fmt.Errorf("unexpected arguments %v", fs.Args()[1:])
+		}
+	case "set":
+		if fs.NArg() < 3 {
+			fs.Usage()
+			return fmt.Errorf("expected a serial and at least one fault, got %v", fs.Args()[1:])
+		}
+		faults, err := parseFaults(fs.Args()[2:])
+		if err != nil {
+			return err
+		}
+		req = &apb.SetFaultsRequest{SerialNumber: fs.Arg(1), Faults: faults}
+	case "clear":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return fmt.Errorf("expected a serial, got %v", fs.Args()[1:])
+		}
+		req = &apb.SetFaultsRequest{SerialNumber: fs.Arg(1)}
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", action)
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	if req == nil {
+		resp, err := client.ListFaults(ctx, &apb.ListFaultsRequest{})
+		if err != nil {
+			return err
+		}
+		return printFaults(os.Stdout, resp.GetDevices())
+	}
+	df, err := client.SetFaults(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(df.GetFaults()) == 0 {
+		fmt.Printf("Cleared faults of %s\n", df.GetSerialNumber())
+		return nil
+	}
+	fmt.Printf("Injecting %s into %s\n", formatFaults(df.GetFaults()), df.GetSerialNumber())
+	return nil
+}
+
+// parseFaults parses fault names, e.g. "wrong_nonce" or "FAULT_EXPIRED_OV".
+func parseFaults(names []string) ([]epb.Fault, error) {
+	var faults []epb.Fault
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		v, ok := epb.Fault_value[faultPrefix+strings.TrimPrefix(name, faultPrefix)]
+		if !ok || v == 0 {
+			return nil, fmt.Errorf("unknown fault %q, want one of %s", name, strings.Join(faultNames(), ", "))
+		}
+		faults = append(faults, epb.Fault(v))
+	}
+	return faults, nil
+}
+
+// faultNames returns the names accepted by parseFaults, in enum order.
+func faultNames() []string {
+	var names []string
+	for i := 1; i < len(epb.Fault_name); i++ {
+		names = append(names, faultName(epb.Fault(i)))
+	}
+	return names
+}
+
+func faultName(f epb.Fault) string {
+	return strings.ToLower(strings.TrimPrefix(f.String(), faultPrefix))
+}
+
+func formatFaults(faults []epb.Fault) string {
+	var names []string
+	for _, f := range faults {
+		names = append(names, faultName(f))
+	}
+	return strings.Join(names, ",")
+}
+
+// printFaults prints one line per device with faults.
+func printFaults(w io.Writer, devices []*apb.DeviceFaults) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERIAL\tFAULTS")
+	for _, d := range devices {
+		fmt.Fprintf(tw, "%s\t%s\n", d.GetSerialNumber(), formatFaults(d.GetFaults()))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+func TestParseFaults(t *testing.T) {
+	tests := []struct {
+		in      []string
+		want    []epb.Fault
+		wantErr bool
+	}{{
+		in: nil,
+	}, {
+		in:   []string{"wrong_nonce"},
+		want: []epb.Fault{epb.Fault_FAULT_WRONG_NONCE},
+	}, {
+		in:   []string{"FAULT_EXPIRED_OV", "Truncated_Data"},
+		want: []epb.Fault{epb.Fault_FAULT_EXPIRED_OV, epb.Fault_FAULT_TRUNCATED_DATA},
+	}, {
+		in:      []string{"unspecified"},
+		wantErr: true,
+	}, {
+		in:      []string{"wrong_nonce", "bad_cable"},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		got, err := parseFaults(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseFaults(%q) err = %v, want error %v", test.in, err, test.wantErr)
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("parseFaults(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
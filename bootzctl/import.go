@@ -0,0 +1,274 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+
+	"github.com/openconfig/bootz/server/atomicfile"
+	"github.com/openconfig/bootz/server/entitymanager"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+const importUsage = "import [-mapping=<spec> | -mapping_file=<file>] [-profile=<name>] [-inventory=<file>] [-dry_run] <csv>"
+
+var importCommand = &command{
+	name:  "import",
+	usage: importUsage,
+	help:  "Import chassis from a CSV file into an inventory file or the inventory of the server.",
+	run:   runImport,
+}
+
+func runImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	mapping := fs.String("mapping", "", "Column mapping: comma-separated <field>=<column> entries, e.g. serial_number=Serial,name={Site}-{Serial}. Defaults to the CSV header naming the fields.")
+	mappingFile := fs.String("mapping_file", "", "File with the column mapping, one <field>=<column> entry per line.")
+	profile := fs.String("profile", "", "Name of the inventory profile to configure the imported chassis from.")
+	inventory := fs.String("inventory", "", "Inventory file to import into, rather than the inventory of the server. The file is rewritten without its comments, and the original is kept as <file>.bak.")
+	dryRun := fs.Bool("dry_run", false, "Print the changes of the inventory as a diff without applying them.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n\nThe CSV file is read from stdin if it is -. Chassis which are not in the inventory are\nadded, and the fields set on the other chassis replace those of the inventory.\n\nAn inventory file is rewritten without its comments, and kept as <file>.bak. Chassis\ndefined in the files it includes are matched but not changed: their import fails.\n\n", importUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one CSV file, got %v", fs.Args())
+	}
+	cm, err := columnMapping(*mapping, *mappingFile)
+	if err != nil {
+		return err
+	}
+	in := os.Stdin
+	if name := fs.Arg(0); name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	chassis, err := entitymanager.ImportCSV(in, cm)
+	if err != nil {
+		return fmt.Errorf("invalid CSV file %s: %v", fs.Arg(0), err)
+	}
+
+	var changes []entitymanager.ImportChange
+	if *inventory != "" {
+		changes, err = importInventory(*inventory, chassis, *profile, *dryRun)
+	} else {
+		changes, err = importServer(ctx, chassis, *profile, *dryRun)
+	}
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return printImportDiff(os.Stdout, changes)
+	}
+	printImportSummary(os.Stdout, changes, false)
+	return nil
+}
+
+// columnMapping returns the mapping set by the flags, or nil if the CSV header names the fields.
+func columnMapping(spec, file string) (*entitymanager.ColumnMapping, error) {
+	switch {
+	case spec != "" && file != "":
+		return nil, errors.New("only one of -mapping and -mapping_file may be set")
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		spec = string(data)
+	case spec == "":
+		return nil, nil
+	}
+	return entitymanager.ParseColumnMapping(spec)
+}
+
+// importInventory merges the chassis into an inventory file, which is rewritten unless dryRun is
+// set. Rewriting the file drops its comments, so the original file is kept as <file>.bak.
+func importInventory(path string, chassis []*epb.Chassis, profile string, dryRun bool) ([]entitymanager.ImportChange, error) {
+	entities, changes, err := entitymanager.MergeImportFile(path, chassis, profile)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	out, err := entitymanager.MarshalEntities(entities, entitymanager.FormatOf(path))
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	orig, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path+".bak", orig, fi.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("unable to back up %s: %v", path, err)
+	}
+	return changes, atomicfile.WriteFile(path, out, fi.Mode().Perm())
+}
+
+// importServer merges the chassis into the inventory of the server.
+func importServer(ctx context.Context, chassis []*epb.Chassis, profile string, dryRun bool) ([]entitymanager.ImportChange, error) {
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+	resp, err := client.ImportChassis(ctx, &apb.ImportChassisRequest{
+		Chassis: chassis,
+		Profile: profile,
+		DryRun:  dryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var changes []entitymanager.ImportChange
+	for _, c := range resp.GetChanges() {
+		changes = append(changes, entitymanager.ImportChange{Before: c.GetBefore(), After: c.GetAfter()})
+	}
+	return changes, nil
+}
+
+// printImportDiff prints the changes of an import as a diff of the chassis, in prototext.
+func printImportDiff(w io.Writer, changes []entitymanager.ImportChange) error {
+	for _, c := range changes {
+		after, err := chassisLines(c.After)
+		if err != nil {
+			return err
+		}
+		if c.Before == nil {
+			fmt.Fprintf(w, "+ chassis %s\n", chassisLabel(c.After))
+			for _, l := range after {
+				fmt.Fprintf(w, "+   %s\n", l)
+			}
+			continue
+		}
+		before, err := chassisLines(c.Before)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "~ chassis %s\n", chassisLabel(c.After))
+		for _, l := range diffLines(before, after) {
+			fmt.Fprintf(w, "%c   %s\n", l.op, l.text)
+		}
+	}
+	printImportSummary(w, changes, true)
+	return nil
+}
+
+// printImportSummary prints the number of chassis added and updated by an import.
+func printImportSummary(w io.Writer, changes []entitymanager.ImportChange, dryRun bool) {
+	var added, updated int
+	for _, c := range changes {
+		if c.Before == nil {
+			added++
+		} else {
+			updated++
+		}
+	}
+	if dryRun {
+		fmt.Fprintf(w, "Dry run: %d chassis to add, %d to update\n", added, updated)
+		return
+	}
+	fmt.Fprintf(w, "Added %d and updated %d chassis\n", added, updated)
+}
+
+// chassisLabel names a chassis by its name and serial.
+func chassisLabel(ch *epb.Chassis) string {
+	serial := ch.GetSerialNumber()
+	if serial == "" && len(ch.GetControllerCards()) > 0 {
+		serial = ch.GetControllerCards()[0].GetSerialNumber()
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s %s)", ch.GetName(), ch.GetManufacturer(), serial))
+}
+
+// chassisLines returns the lines of the prototext encoding of a chassis.
+func chassisLines(ch *epb.Chassis) ([]string, error) {
+	b, err := prototext.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(ch)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(string(b), "\n"), "\n"), nil
+}
+
+// diffLine is a line of a diff. op is ' ' for common lines, '-' for removed lines and '+' for
+// added lines.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines returns a line diff of a and b, from their longest common subsequence.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffLine{'-', a[i]})
+			i++
+		default:
+			out = append(out, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffLine{'+', b[j]})
+	}
+	return out
+}
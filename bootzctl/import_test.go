@@ -0,0 +1,182 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/openconfig/bootz/server/entitymanager"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		desc string
+		a, b []string
+		want []diffLine
+	}{{
+		desc: "equal",
+		a:    []string{"a", "b"},
+		b:    []string{"a", "b"},
+		want: []diffLine{{' ', "a"}, {' ', "b"}},
+	}, {
+		desc: "changed",
+		a:    []string{"a", "b", "c"},
+		b:    []string{"a", "x", "c", "d"},
+		want: []diffLine{{' ', "a"}, {'-', "b"}, {'+', "x"}, {' ', "c"}, {'+', "d"}},
+	}, {
+		desc: "added",
+		b:    []string{"a"},
+		want: []diffLine{{'+', "a"}},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := diffLines(test.a, test.b)
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(diffLine{})); diff != "" {
+				t.Errorf("diffLines() differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImportInventory(t *testing.T) {
+	dir := t.TempDir()
+	inventory := filepath.Join(dir, "inventory.yaml")
+	if err := runConvert(context.Background(), []string{"../testdata/inventory.prototxt", inventory}); err != nil {
+		t.Fatalf("runConvert() err = %v", err)
+	}
+	csv := filepath.Join(dir, "chassis.csv")
+	if err := os.WriteFile(csv, []byte("Serial,Vendor,RP0,MAC\n900,Cisco,900A,00:00:5E:00:53:01\n123,Cisco,123A,00:00:5E:00:53:02\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	mapping := "-mapping=serial_number=Serial,manufacturer=Vendor,controller_cards.0.serial_number=RP0,controller_cards.0.dhcp_config.hardware_address=MAC"
+	before, err := os.ReadFile(inventory)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v", err)
+	}
+
+	var changes []entitymanager.ImportChange
+	if changes, err = importChanges(t, inventory, csv, mapping); err != nil {
+		t.Fatalf("import dry run err = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := printImportDiff(&buf, changes); err != nil {
+		t.Fatalf("printImportDiff() err = %v", err)
+	}
+	for _, want := range []string{"+ chassis 900 (Cisco 900)", "~ chassis test (Cisco 123)", "00:00:5E:00:53:02", "Dry run: 1 chassis to add, 1 to update"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("printImportDiff() = %s, want %q", buf.String(), want)
+		}
+	}
+	if err := runImport(context.Background(), []string{"-dry_run", "-inventory=" + inventory, mapping, csv}); err != nil {
+		t.Fatalf("runImport() dry run err = %v", err)
+	}
+	if after, err := os.ReadFile(inventory); err != nil || !bytes.Equal(before, after) {
+		t.Errorf("runImport() dry run changed the inventory")
+	}
+
+	if err := runImport(context.Background(), []string{"-inventory=" + inventory, "-profile=default", mapping, csv}); err != nil {
+		t.Fatalf("runImport() err = %v", err)
+	}
+	entities, err := entitymanager.LoadEntities(inventory)
+	if err != nil {
+		t.Fatalf("LoadEntities() err = %v", err)
+	}
+	var added bool
+	for _, ch := range entities.GetChassis() {
+		if ch.GetSerialNumber() == "900" {
+			added = ch.GetProfile() == "default" && ch.GetControllerCards()[0].GetDhcpConfig().GetHardwareAddress() == "00:00:5E:00:53:01"
+		}
+	}
+	if !added {
+		t.Errorf("runImport() inventory = %v, want chassis 900 configured from the default profile", entities)
+	}
+	if backup, err := os.ReadFile(inventory + ".bak"); err != nil || !bytes.Equal(before, backup) {
+		t.Errorf("runImport() backup = %q, %v, want the original inventory", backup, err)
+	}
+
+	if err := runImport(context.Background(), []string{"-inventory=" + inventory, "-profile=missing", mapping, csv}); err == nil {
+		t.Errorf("runImport() with an unknown profile succeeded")
+	}
+	if err := runImport(context.Background(), []string{"-inventory=" + inventory, mapping, "-mapping_file=" + csv, csv}); err == nil {
+		t.Errorf("runImport() with both mapping flags succeeded")
+	}
+}
+
+func TestImportIncludedInventory(t *testing.T) {
+	dir := t.TempDir()
+	inventory := filepath.Join(dir, "inventory.yaml")
+	files := map[string]string{
+		inventory:                       "includes: [site.yaml]\nchassis:\n  - serial_number: 100\n    manufacturer: Cisco\n",
+		filepath.Join(dir, "site.yaml"): "chassis:\n  - serial_number: 200\n    manufacturer: Cisco\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() err = %v", err)
+		}
+	}
+	csv := filepath.Join(dir, "chassis.csv")
+	mapping := "-mapping=serial_number=Serial,manufacturer=Vendor,part_number=PN"
+	for _, test := range []struct {
+		serial  string
+		wantErr string
+	}{
+		{serial: "100"},
+		{serial: "200", wantErr: "site.yaml"},
+	} {
+		if err := os.WriteFile(csv, []byte("Serial,Vendor,PN\n"+test.serial+",Cisco,8808\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile() err = %v", err)
+		}
+		err := runImport(context.Background(), []string{"-inventory=" + inventory, mapping, csv})
+		switch {
+		case test.wantErr == "" && err != nil:
+			t.Errorf("runImport() of chassis %s err = %v", test.serial, err)
+		case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+			t.Errorf("runImport() of chassis %s err = %v, want %q", test.serial, err, test.wantErr)
+		}
+	}
+	entities, err := entitymanager.LoadEntities(inventory)
+	if err != nil {
+		t.Fatalf("LoadEntities() err = %v", err)
+	}
+	if got := entities.GetChassis(); len(got) != 1 || got[0].GetPartNumber() != "8808" {
+		t.Errorf("runImport() inventory chassis = %v, want chassis 100 updated only", got)
+	}
+}
+
+// importChanges returns the changes of a dry run import of the CSV file into the inventory.
+func importChanges(t *testing.T, inventory, csv, mapping string) ([]entitymanager.ImportChange, error) {
+	t.Helper()
+	cm, err := columnMapping(strings.TrimPrefix(mapping, "-mapping="), "")
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(csv)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	chassis, err := entitymanager.ImportCSV(f, cm)
+	if err != nil {
+		return nil, err
+	}
+	return importInventory(inventory, chassis, "", true)
+}
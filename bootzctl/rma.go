@@ -0,0 +1,75 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const rmaUsage = "rma [-ov=<file>] [-hardware_address=<mac>] [-manufacturer=<name>] <old serial> <new serial>"
+
+var rmaCommand = &command{
+	name:  "rma",
+	usage: rmaUsage,
+	help:  "Replace a control card with its RMA replacement.",
+	run:   runRMA,
+}
+
+func runRMA(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("rma", flag.ContinueOnError)
+	ovFile := fs.String("ov", "", "Path to the ownership voucher of the replacement card. If unset, the server uses a known or generated voucher.")
+	hwAddr := fs.String("hardware_address", "", "MAC address of the management interface of the replacement card.")
+	manufacturer := fs.String("manufacturer", "", "Manufacturer of the chassis, to disambiguate serials.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", rmaUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected the old and new serials, got %v", fs.Args())
+	}
+	req := &apb.ReplaceControlCardRequest{
+		Manufacturer:    *manufacturer,
+		OldSerial:       fs.Arg(0),
+		NewSerial:       fs.Arg(1),
+		HardwareAddress: *hwAddr,
+	}
+	if *ovFile != "" {
+		ov, err := os.ReadFile(*ovFile)
+		if err != nil {
+			return err
+		}
+		req.OwnershipVoucher = ov
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	cs, err := client.ReplaceControlCard(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Replaced control card %s with %s in %s chassis %s\n", req.GetOldSerial(), req.GetNewSerial(), cs.GetManufacturer(), cs.GetSerialNumber())
+	return nil
+}
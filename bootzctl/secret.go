@@ -0,0 +1,114 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openconfig/bootz/server/secrets"
+)
+
+const secretUsage = "secret -store=<file> -key_file=<file> [-escrow] list | get <name> | set <name> | delete <name>"
+
+var secretCommand = &command{
+	name:  "secret",
+	usage: secretUsage,
+	help:  "Manage the secrets of an encrypted secret store or password escrow file.",
+	run:   runSecret,
+}
+
+func runSecret(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("secret", flag.ContinueOnError)
+	storePath := fs.String("store", "", "Path to the secret store or password escrow file. It is created by the first set.")
+	keyFile := fs.String("key_file", "", "Path to the file with the 32 byte key of the store, raw or hex or base64 encoded.")
+	escrow := fs.Bool("escrow", false, "Whether the file is a password escrow rather than a secret store.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n\nset reads the value of the secret from the first line of stdin. Escrowed passwords are named\n<manufacturer>/<serial>. This command does not connect to a server.\n\n", secretUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *storePath == "" || *keyFile == "" {
+		fs.Usage()
+		return errors.New("-store and -key_file are required")
+	}
+	key, err := secrets.LoadKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	purpose := secrets.SecretStore
+	if *escrow {
+		purpose = secrets.PasswordEscrow
+	}
+	store, err := secrets.Open(*storePath, key, purpose)
+	if err != nil {
+		return err
+	}
+	return secretAction(os.Stdout, os.Stdin, store, fs.Args())
+}
+
+// secretAction runs a secret subcommand on the store. Values to set are read from in.
+func secretAction(w io.Writer, in io.Reader, store *secrets.Store, args []string) error {
+	if len(args) == 0 {
+		return errors.New("expected one of list, get, set or delete")
+	}
+	action, args := args[0], args[1:]
+	if action == "list" {
+		if len(args) != 0 {
+			return fmt.Errorf("unexpected arguments %v", args)
+		}
+		for _, n := range store.Names() {
+			fmt.Fprintln(w, n)
+		}
+		return nil
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected one secret name, got %v", args)
+	}
+	name := args[0]
+	switch action {
+	case "get":
+		v, ok := store.Get(name)
+		if !ok {
+			return fmt.Errorf("secret %q not found", name)
+		}
+		fmt.Fprintln(w, v)
+		return nil
+	case "set":
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		v := strings.TrimRight(line, "\r\n")
+		if v == "" {
+			return errors.New("empty secret value on stdin")
+		}
+		return store.Set(name, v)
+	case "delete":
+		if _, ok := store.Get(name); !ok {
+			return fmt.Errorf("secret %q not found", name)
+		}
+		return store.Delete(name)
+	}
+	return fmt.Errorf("unknown secret action %q, want one of list, get, set or delete", action)
+}
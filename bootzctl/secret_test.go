@@ -0,0 +1,57 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/bootz/server/secrets"
+)
+
+func TestSecretAction(t *testing.T) {
+	store, err := secrets.Open(filepath.Join(t.TempDir(), "secrets.json"), bytes.Repeat([]byte{1}, secrets.KeySize), secrets.SecretStore)
+	if err != nil {
+		t.Fatalf("secrets.Open() err = %v", err)
+	}
+	tests := []struct {
+		args    []string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{args: []string{"set", "core1"}, in: "hunter2\nignored\n"},
+		{args: []string{"set", "core2"}, in: "correct horse"},
+		{args: []string{"set", "core3"}, in: "\n", wantErr: "empty secret value"},
+		{args: []string{"get", "core1"}, want: "hunter2\n"},
+		{args: []string{"list"}, want: "core1\ncore2\n"},
+		{args: []string{"delete", "core2"}},
+		{args: []string{"get", "core2"}, wantErr: `secret "core2" not found`},
+		{args: []string{"rotate", "core1"}, wantErr: "unknown secret action"},
+		{args: []string{"get"}, wantErr: "expected one secret name"},
+	}
+	for _, test := range tests {
+		var out bytes.Buffer
+		err := secretAction(&out, strings.NewReader(test.in), store, test.args)
+		if (err == nil) != (test.wantErr == "") || (err != nil && !strings.Contains(err.Error(), test.wantErr)) {
+			t.Fatalf("secretAction(%v) err = %v, want %q", test.args, err, test.wantErr)
+		}
+		if got := out.String(); got != test.want {
+			t.Errorf("secretAction(%v) = %q, want %q", test.args, got, test.want)
+		}
+	}
+}
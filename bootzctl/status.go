@@ -0,0 +1,191 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const (
+	statePrefix = "CHASSIS_STATE_"
+	statusUsage = "status [-state=<state>[,<state>...]] [-manufacturer=<name>] [-json] [serial]"
+)
+
+var statusCommand = &command{
+	name:  "status",
+	usage: statusUsage,
+	help:  "Show the bootstrap status of the chassis, or the status and history of one chassis.",
+	run:   runStatus,
+}
+
+func runStatus(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	states := fs.String("state", "", "Comma-separated list of states to list chassis in: never_seen, in_progress, failed or initialized.")
+	manufacturer := fs.String("manufacturer", "", "Manufacturer of the chassis, to disambiguate serials.")
+	asJSON := fs.Bool("json", false, "Print the status as JSON.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", statusUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter, err := parseStates(*states)
+	if err != nil {
+		return err
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	var msg proto.Message
+	switch fs.NArg() {
+	case 0:
+		resp, err := client.ListChassis(ctx, &apb.ListChassisRequest{States: filter})
+		if err != nil {
+			return err
+		}
+		msg = resp
+		if !*asJSON {
+			return printChassisList(os.Stdout, resp)
+		}
+	case 1:
+		resp, err := client.GetStatus(ctx, &apb.GetStatusRequest{Manufacturer: *manufacturer, SerialNumber: fs.Arg(0)})
+		if err != nil {
+			return err
+		}
+		msg = resp
+		if !*asJSON {
+			return printChassisStatus(os.Stdout, resp)
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("expected at most one serial, got %v", fs.Args())
+	}
+	b, err := protojson.MarshalOptions{Multiline: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// parseStates parses a comma-separated list of chassis states, e.g. "failed,in_progress".
+func parseStates(s string) ([]apb.ChassisState, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var states []apb.ChassisState
+	for _, name := range strings.Split(s, ",") {
+		v, ok := apb.ChassisState_value[statePrefix+strings.ToUpper(strings.TrimSpace(name))]
+		if !ok || v == 0 {
+			return nil, fmt.Errorf("unknown chassis state %q", name)
+		}
+		states = append(states, apb.ChassisState(v))
+	}
+	return states, nil
+}
+
+func stateName(s apb.ChassisState) string {
+	return strings.ToLower(strings.TrimPrefix(s.String(), statePrefix))
+}
+
+func formatTime(t *timestamppb.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return t.AsTime().Local().Format(time.RFC3339)
+}
+
+// printChassisList prints one line per chassis.
+func printChassisList(w io.Writer, resp *apb.ListChassisResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "MANUFACTURER\tSERIAL\tSTATE\tLAST UPDATE\tMESSAGE")
+	for _, ch := range resp.GetChassis() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ch.GetManufacturer(), ch.GetSerialNumber(), stateName(ch.GetState()), formatTime(ch.GetLastUpdate()), ch.GetStatusMessage())
+	}
+	return tw.Flush()
+}
+
+// formatArtifacts formats served artifacts as their kinds and abbreviated digests.
+func formatArtifacts(artifacts []*apb.ServedArtifact) string {
+	if len(artifacts) == 0 {
+		return "-"
+	}
+	var out []string
+	for _, a := range artifacts {
+		digest := a.GetDigest()
+		if len(digest) > 12 {
+			digest = digest[:12]
+		}
+		out = append(out, a.GetKind()+"@"+digest)
+	}
+	return strings.Join(out, ",")
+}
+
+// printChassisStatus prints the status of a chassis, its control cards and its history.
+func printChassisStatus(w io.Writer, cs *apb.ChassisStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Chassis:\t%s %s\n", cs.GetManufacturer(), cs.GetSerialNumber())
+	fmt.Fprintf(tw, "State:\t%s\n", stateName(cs.GetState()))
+	if cs.GetProfile() != "" {
+		profile := cs.GetProfile()
+		if cs.GetProvisional() {
+			profile += " (provisional)"
+		}
+		fmt.Fprintf(tw, "Profile:\t%s\n", profile)
+	}
+	fmt.Fprintf(tw, "Bootstrap status:\t%s\n", cs.GetBootstrapStatus())
+	fmt.Fprintf(tw, "Message:\t%s\n", cs.GetStatusMessage())
+	fmt.Fprintf(tw, "Last update:\t%s\n", formatTime(cs.GetLastUpdate()))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nControl cards:")
+	tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "  SERIAL\tSTATUS\tARTIFACTS")
+	for _, cc := range cs.GetControlCards() {
+		st := cc.GetStatus().String()
+		if cc.GetReplaced() {
+			st = "REPLACED"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", cc.GetSerialNumber(), st, formatArtifacts(cc.GetServedArtifacts()))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nHistory:")
+	tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TIME\tSERIAL\tCONTROL CARD STATUS\tBOOTSTRAP STATUS\tSOURCE\tMESSAGE")
+	for _, t := range cs.GetHistory() {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\n", formatTime(t.GetTimestamp()), t.GetSerialNumber(), t.GetControlCardStatus(), t.GetBootstrapStatus(), t.GetSource(), t.GetMessage())
+	}
+	return tw.Flush()
+}
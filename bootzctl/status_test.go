@@ -0,0 +1,100 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+func TestParseStates(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []apb.ChassisState
+		wantErr bool
+	}{{
+		in: "",
+	}, {
+		in:   "failed",
+		want: []apb.ChassisState{apb.ChassisState_CHASSIS_STATE_FAILED},
+	}, {
+		in:   "never_seen, IN_PROGRESS",
+		want: []apb.ChassisState{apb.ChassisState_CHASSIS_STATE_NEVER_SEEN, apb.ChassisState_CHASSIS_STATE_IN_PROGRESS},
+	}, {
+		in:      "unspecified",
+		wantErr: true,
+	}, {
+		in:      "done",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		got, err := parseStates(test.in)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseStates(%q) err = %v, want error %v", test.in, err, test.wantErr)
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("parseStates(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestPrintChassisList(t *testing.T) {
+	var buf bytes.Buffer
+	err := printChassisList(&buf, &apb.ListChassisResponse{Chassis: []*apb.ChassisStatus{
+		{Manufacturer: "Cisco", SerialNumber: "123", State: apb.ChassisState_CHASSIS_STATE_FAILED, StatusMessage: "bad image"},
+		{Manufacturer: "Cisco", SerialNumber: "456", State: apb.ChassisState_CHASSIS_STATE_NEVER_SEEN},
+	}})
+	if err != nil {
+		t.Fatalf("printChassisList() err = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("printChassisList() printed %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	want := [][]string{
+		{"Cisco", "123", "failed", "-", "bad", "image"},
+		{"Cisco", "456", "never_seen", "-"},
+	}
+	for i, w := range want {
+		if got := strings.Fields(lines[i+1]); !cmp.Equal(got, w) {
+			t.Errorf("printChassisList() line %d = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestFormatArtifacts(t *testing.T) {
+	tests := []struct {
+		in   []*apb.ServedArtifact
+		want string
+	}{{
+		want: "-",
+	}, {
+		in: []*apb.ServedArtifact{
+			{Kind: "oc_config", Path: "oc.json", Digest: "0123456789abcdef0123"},
+			{Kind: "authz", Path: "authz.prototext", Digest: "abc"},
+		},
+		want: "oc_config@0123456789ab,authz@abc",
+	}}
+	for _, test := range tests {
+		if got := formatArtifacts(test.in); got != test.want {
+			t.Errorf("formatArtifacts(%v) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
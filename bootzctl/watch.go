@@ -0,0 +1,141 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const (
+	eventTypePrefix = "EVENT_TYPE_"
+	watchUsage      = "watch [-type=<type>[,<type>...]] [-cursor=<cursor>] [-json] [serial]"
+)
+
+var watchCommand = &command{
+	name:   "watch",
+	usage:  watchUsage,
+	help:   "Stream bootstrap events, optionally of one chassis or control card.",
+	run:    runWatch,
+	stream: true,
+}
+
+func runWatch(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	types := fs.String("type", "", "Comma-separated list of event types to watch, e.g. data_served,bootstrap_failed.")
+	manufacturer := fs.String("manufacturer", "", "Only watch events of chassis from this manufacturer.")
+	cursor := fs.String("cursor", "", "Resume after the event with this cursor. If unset, only new events are streamed.")
+	asJSON := fs.Bool("json", false, "Print one JSON object per event.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: bootzctl %s\n", watchUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return fmt.Errorf("expected at most one serial, got %v", fs.Args())
+	}
+	filter, err := parseEventTypes(*types)
+	if err != nil {
+		return err
+	}
+	client, closeFn, err := dialAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	stream, err := client.WatchStatus(ctx, &apb.WatchStatusRequest{
+		Manufacturer: *manufacturer,
+		SerialNumber: fs.Arg(0),
+		Types:        filter,
+		Cursor:       *cursor,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			b, err := protojson.Marshal(e)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(b))
+			continue
+		}
+		printEvent(os.Stdout, e)
+	}
+}
+
+// parseEventTypes parses a comma-separated list of event types, e.g. "data_served,bootstrap_failed".
+func parseEventTypes(s string) ([]apb.EventType, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var types []apb.EventType
+	for _, name := range strings.Split(s, ",") {
+		v, ok := apb.EventType_value[eventTypePrefix+strings.ToUpper(strings.TrimSpace(name))]
+		if !ok || v == 0 {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		types = append(types, apb.EventType(v))
+	}
+	return types, nil
+}
+
+// printEvent prints an event on one line, starting with its cursor.
+func printEvent(w io.Writer, e *apb.Event) {
+	fields := []string{
+		e.GetCursor(),
+		formatTime(e.GetTimestamp()),
+		strings.ToLower(strings.TrimPrefix(e.GetType().String(), eventTypePrefix)),
+		e.GetManufacturer(),
+	}
+	if e.GetChassisSerial() != "" {
+		fields = append(fields, "chassis="+e.GetChassisSerial())
+	}
+	if len(e.GetControlCardSerials()) > 0 {
+		fields = append(fields, "cards="+strings.Join(e.GetControlCardSerials(), ","))
+	}
+	if e.GetSessionId() != "" {
+		fields = append(fields, "session="+e.GetSessionId())
+	}
+	if e.GetSource() != "" {
+		fields = append(fields, "source="+e.GetSource())
+	}
+	if e.GetBootstrapStatus() != 0 {
+		fields = append(fields, "status="+e.GetBootstrapStatus().String())
+	}
+	if e.GetMessage() != "" {
+		fields = append(fields, fmt.Sprintf("message=%q", e.GetMessage()))
+	}
+	fmt.Fprintln(w, strings.Join(fields, " "))
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+func TestParseEventTypes(t *testing.T) {
+	got, err := parseEventTypes("data_served, BOOTSTRAP_FAILED")
+	if err != nil {
+		t.Fatalf("parseEventTypes() err = %v", err)
+	}
+	want := []apb.EventType{apb.EventType_EVENT_TYPE_DATA_SERVED, apb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED}
+	if !cmp.Equal(got, want) {
+		t.Errorf("parseEventTypes() = %v, want %v", got, want)
+	}
+	if _, err := parseEventTypes("rebooted"); err == nil {
+		t.Errorf("parseEventTypes() of an unknown type err = nil, want error")
+	}
+}
+
+func TestPrintEvent(t *testing.T) {
+	var buf bytes.Buffer
+	printEvent(&buf, &apb.Event{
+		Cursor:             "abc-7",
+		Type:               apb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED,
+		Manufacturer:       "Cisco",
+		ChassisSerial:      "123",
+		ControlCardSerials: []string{"123A"},
+		BootstrapStatus:    bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE,
+		Message:            "bad image",
+	})
+	want := "abc-7 - bootstrap_failed Cisco chassis=123 cards=123A status=BOOTSTRAP_STATUS_FAILURE message=\"bad image\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("printEvent() = %q, want %q", got, want)
+	}
+}
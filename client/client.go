@@ -36,6 +36,7 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
@@ -58,6 +59,9 @@ control_cards {
 }
 `
 
+// The gRPC metadata key the server uses to send the bootstrap session ID, which is echoed in status reports.
+const sessionIDHeader = "bootz-session-id"
+
 // Represents a 128 bit nonce.
 const nonceLength = 16
 
@@ -66,6 +70,8 @@ var (
 	insecureBoot      = flag.Bool("insecure_boot", false, "Whether to start the emulated device in non-secure mode. This informs Bootz server to not provide ownership certificates or vouchers.")
 	port              = flag.String("port", "15006", "The port to connect to on localhost for the bootz server.")
 	chassisDescriptor = flag.String("chassis_descriptor", defaultChassisDescriptor, "A textproto formatting of the ChassisDescriptor message.")
+	idevidCert        = flag.String("idevid_cert", "", "Path to a PEM file with the IDevID certificate of the active control card. If set, it is presented to the Bootz server as the TLS client certificate.")
+	idevidKey         = flag.String("idevid_key", "", "Path to a PEM file with the private key of the IDevID.")
 	urlImageMap       = map[string]string{
 		"https://path/to/image": "../testdata/image.txt",
 	}
@@ -171,6 +177,28 @@ func generateNonce() (string, error) {
 	return base64.StdEncoding.EncodeToString(b), nil
 }
 
+// trustChainPool parses the server trust certificate chain, which is a concatenation of DER certificates from the
+// server's certificate to the trust anchor, and returns a pool with the trust anchor. The chain is verified to make
+// sure each certificate is issued by the next one.
+func trustChainPool(der []byte) (*x509.CertPool, error) {
+	chain, err := x509.ParseCertificates(der)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("empty certificate chain")
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return nil, fmt.Errorf("certificate %q is not issued by %q: %v", chain[i].Subject, chain[i+1].Subject, err)
+		}
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(chain[len(chain)-1])
+	log.Infof("Server trust chain has %d certificates, trust anchor is %q", len(chain), chain[len(chain)-1].Subject)
+	return pool, nil
+}
+
 func validateChassisDescriptor(chassis *bpb.ChassisDescriptor) {
 	if chassis.GetManufacturer() == "" || chassis.GetPartNumber() == "" {
 		log.Exitf("Chassis validation error: chassis %v does not have required fields", chassis)
@@ -239,7 +267,19 @@ func main() {
 
 	// 2. Bootstrapping Service
 	// Device initiates a TLS-secured gRPC connection with the Bootz server.
-	tlsConfig := &tls.Config{InsecureSkipVerify: !*verifyTLSCert}
+	var clientCerts []tls.Certificate
+	if *idevidCert != "" {
+		idevid, err := tls.LoadX509KeyPair(*idevidCert, *idevidKey)
+		if err != nil {
+			log.Exitf("Unable to load IDevID: %v", err)
+		}
+		clientCerts = append(clientCerts, idevid)
+		log.Infof("Presenting IDevID %v to the bootz server", *idevidCert)
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: !*verifyTLSCert,
+		Certificates:       clientCerts,
+	}
 	conn, err := grpc.Dial(bootzAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	if err != nil {
 		log.Exitf("Client unable to connect to Bootstrap Server: %v", err)
@@ -278,11 +318,16 @@ func main() {
 	// Get bootstrapping data from Bootz server
 	// TODO: Extract and parse response.
 	log.Infof("Requesting Bootstrap Data from Bootz server")
-	resp, err := c.GetBootstrapData(ctx, req)
+	var header metadata.MD
+	resp, err := c.GetBootstrapData(ctx, req, grpc.Header(&header))
 	if err != nil {
 		log.Exitf("Error calling GetBootstrapData: %v", err)
 	}
 	log.Infof("Successfully retrieved Bootstrap Data from server")
+	if ids := header.Get(sessionIDHeader); len(ids) > 0 {
+		log.Infof("Bootstrap session ID is %v", ids[0])
+		ctx = metadata.AppendToOutgoingContext(ctx, sessionIDHeader, ids[0])
+	}
 
 	// Only check OC, OV and response signature if SecureOnly is set.
 	if !*insecureBoot {
@@ -341,17 +386,15 @@ func main() {
 	if err != nil {
 		log.Exitf("unable to base64-decode trust cert")
 	}
-	trustAnchor, err := x509.ParseCertificate(trustCertDecoded)
+	trustCertPool, err := trustChainPool(trustCertDecoded)
 	if err != nil {
-		log.Exitf("unable to parse server trust certificate")
+		log.Exitf("unable to parse server trust certificate: %v", err)
 	}
 
-	trustCertPool := x509.NewCertPool()
-	trustCertPool.AddCert(trustAnchor)
-
 	tlsConfig = &tls.Config{
 		InsecureSkipVerify: false,
 		RootCAs:            trustCertPool,
+		Certificates:       clientCerts,
 	}
 	conn.Close()
 	conn, err = grpc.Dial(bootzAddress, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
@@ -0,0 +1,29 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certserial generates X.509 certificate serial numbers.
+package certserial
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// limit bounds serial numbers to 128 bits, well within the 20 octets allowed by RFC 5280.
+var limit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// Random returns a random 128 bit certificate serial number.
+func Random() (*big.Int, error) {
+	return rand.Int(rand.Reader, limit)
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certserial
+
+import "testing"
+
+func TestRandom(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		serial, err := Random()
+		if err != nil {
+			t.Fatalf("Random() err = %v", err)
+		}
+		if serial.Sign() < 0 || serial.BitLen() > 128 {
+			t.Errorf("Random() = %v, want a non-negative number of at most 128 bits", serial)
+		}
+		if seen[serial.String()] {
+			t.Errorf("Random() returned %v twice", serial)
+		}
+		seen[serial.String()] = true
+	}
+}
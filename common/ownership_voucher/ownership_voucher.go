@@ -16,18 +16,74 @@
 package ownershipvoucher
 
 import (
+	"crypto"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"go.mozilla.org/pkcs7"
-
-	artifacts "github.com/openconfig/bootz/testdata"
 )
 
+// validity is the validity of the ownership vouchers created by New.
+const validity = time.Hour * 24 * 365
+
+// OwnershipVoucher wraps Inner.
+type OwnershipVoucher struct {
+	OV Inner `json:"ietf-voucher:voucher"`
+}
+
+// Inner defines the Ownership Voucher format. See https://www.rfc-editor.org/rfc/rfc8366.html.
+type Inner struct {
+	CreatedOn                  string `json:"created-on"`
+	ExpiresOn                  string `json:"expires-on"`
+	SerialNumber               string `json:"serial-number"`
+	Assertion                  string `json:"assertion"`
+	PinnedDomainCert           []byte `json:"pinned-domain-cert"`
+	DomainCertRevocationChecks bool   `json:"domain-cert-revocation-checks"`
+}
+
+// New generates an Ownership Voucher which is signed by the vendor's CA.
+func New(serial string, pdc, vendorCACert *x509.Certificate, vendorCAPriv crypto.PrivateKey) ([]byte, error) {
+	currentTime := time.Now()
+	return NewWithValidity(serial, pdc, vendorCACert, vendorCAPriv, currentTime, currentTime.Add(validity))
+}
+
+// NewWithValidity generates an Ownership Voucher which is signed by the vendor's CA and is valid
+// between the provided times.
+func NewWithValidity(serial string, pdc, vendorCACert *x509.Certificate, vendorCAPriv crypto.PrivateKey, createdOn, expiresOn time.Time) ([]byte, error) {
+	ov := OwnershipVoucher{
+		OV: Inner{
+			CreatedOn:        createdOn.Format(time.RFC3339),
+			ExpiresOn:        expiresOn.Format(time.RFC3339),
+			SerialNumber:     serial,
+			PinnedDomainCert: pdc.Raw,
+		},
+	}
+
+	ovBytes, err := json.Marshal(ov)
+	if err != nil {
+		return nil, err
+	}
+
+	signedMessage, err := pkcs7.NewSignedData(ovBytes)
+	if err != nil {
+		return nil, err
+	}
+	signedMessage.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	signedMessage.SetEncryptionAlgorithm(pkcs7.OIDEncryptionAlgorithmRSA)
+
+	err = signedMessage.AddSigner(vendorCACert, vendorCAPriv, pkcs7.SignerInfoConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	return signedMessage.Finish()
+}
+
 // Unmarshal unmarshals the contents of an Ownership Voucher. If a certPool is provided,
 // it is used to verify the contents.
-func Unmarshal(in []byte, certPool *x509.CertPool) (*artifacts.OwnershipVoucher, error) {
+func Unmarshal(in []byte, certPool *x509.CertPool) (*OwnershipVoucher, error) {
 	if len(in) == 0 {
 		return nil, fmt.Errorf("ownership voucher is empty")
 	}
@@ -35,7 +91,7 @@ func Unmarshal(in []byte, certPool *x509.CertPool) (*artifacts.OwnershipVoucher,
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse into pkcs7 format: %v", err)
 	}
-	ov := artifacts.OwnershipVoucher{}
+	ov := OwnershipVoucher{}
 	err = json.Unmarshal(p7.Content, &ov)
 	if err != nil {
 		return nil, fmt.Errorf("failed unmarshalling ownership voucher: %v", err)
@@ -12,13 +12,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package ownershipvoucher
+package ownershipvoucher_test
 
 import (
 	"bytes"
 	"crypto/x509"
 	"testing"
 
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
 	artifacts "github.com/openconfig/bootz/testdata"
 )
 
@@ -37,7 +38,7 @@ func TestEndToEnd(t *testing.T) {
 		t.Fatalf("unable to generate Vendor CA: %v", err)
 	}
 
-	ov, err := artifacts.NewOwnershipVoucher(wantSerial, pdc, vendorca, vendorcaPrivateKey)
+	ov, err := ownershipvoucher.New(wantSerial, pdc, vendorca, vendorcaPrivateKey)
 	if err != nil {
 		t.Errorf("New err = %v, want nil", err)
 	}
@@ -45,7 +46,7 @@ func TestEndToEnd(t *testing.T) {
 	vendorCAPool := x509.NewCertPool()
 	vendorCAPool.AddCert(vendorca)
 
-	got, err := Unmarshal(ov, vendorCAPool)
+	got, err := ownershipvoucher.Unmarshal(ov, vendorCAPool)
 	if err != nil {
 		t.Errorf("VerifyAndUnmarshal err = %v, want nil", err)
 	}
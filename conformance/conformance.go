@@ -0,0 +1,507 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package conformance exercises the protocol contract of a Bootz server.
+//
+// The suite impersonates a device, calls the Bootstrap service of the server
+// under test and checks the responses against the Bootz specification. The
+// outcome of every check is collected in a machine-readable Report.
+package conformance
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ownercertificate "github.com/openconfig/bootz/common/owner_certificate"
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
+	"github.com/openconfig/bootz/common/signature"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+// Represents a 128 bit nonce.
+const nonceLength = 16
+
+// Outcome is the result of a single conformance check.
+type Outcome string
+
+const (
+	// Pass indicates the server behaved as the specification requires.
+	Pass Outcome = "PASS"
+	// Fail indicates the server violated the specification.
+	Fail Outcome = "FAIL"
+	// Skip indicates the check could not be run, e.g. because a check it depends on failed.
+	Skip Outcome = "SKIP"
+)
+
+// Config describes the device the suite impersonates.
+type Config struct {
+	// Target is the address of the server under test. It is only used for reporting.
+	Target string
+	// Descriptor describes a chassis that the server under test has in its inventory.
+	Descriptor *bpb.ChassisDescriptor
+	// ActiveSerial is the serial number of the active control card. If unset, the first
+	// control card of the descriptor is used, or the chassis serial for fixed form factor chassis.
+	ActiveSerial string
+	// SecureDescriptor describes a chassis that the server only boots in secure mode.
+	// If unset, the secure mode check is skipped.
+	SecureDescriptor *bpb.ChassisDescriptor
+	// VendorCA is used to verify the signature of ownership vouchers. If unset, vouchers are
+	// parsed without verifying who signed them.
+	VendorCA *x509.CertPool
+}
+
+// Result records the outcome of a single check.
+type Result struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Outcome     Outcome       `json:"outcome"`
+	Message     string        `json:"message,omitempty"`
+	Duration    time.Duration `json:"duration_ns"`
+}
+
+// Summary counts the results by outcome.
+type Summary struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// Report is the machine-readable outcome of a conformance run.
+type Report struct {
+	Target  string    `json:"target"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Results []*Result `json:"results"`
+	Summary Summary   `json:"summary"`
+}
+
+// Failed returns true if any check failed.
+func (r *Report) Failed() bool {
+	return r.Summary.Failed > 0
+}
+
+// JSON returns the report encoded as indented JSON.
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// errSkip is returned by a check which could not be run.
+type errSkip struct {
+	reason string
+}
+
+func (e *errSkip) Error() string {
+	return e.reason
+}
+
+func skipf(format string, args ...any) error {
+	return &errSkip{reason: fmt.Sprintf(format, args...)}
+}
+
+// env is the state shared by the checks of a single run.
+type env struct {
+	cfg    *Config
+	client bpb.BootstrapClient
+	active string
+	nonce  string
+
+	resp   *bpb.GetBootstrapDataResponse
+	signed *bpb.BootstrapDataSigned
+	ov     *ownershipVoucher
+	oc     *x509.Certificate
+}
+
+// ownershipVoucher holds the parts of a parsed ownership voucher the checks need.
+type ownershipVoucher struct {
+	serial string
+	pdc    *x509.Certificate
+}
+
+// check is a single assertion about the server under test.
+type check struct {
+	name        string
+	description string
+	run         func(context.Context, *env) error
+}
+
+// checks lists the conformance checks in the order they are run.
+// Later checks may rely on state gathered by earlier ones.
+var checks = []check{{
+	name:        "get_bootstrap_data",
+	description: "GetBootstrapData succeeds for a known chassis when a nonce is provided.",
+	run:         checkGetBootstrapData,
+}, {
+	name:        "serialized_bootstrap_data",
+	description: "serialized_bootstrap_data is populated and is a valid BootstrapDataSigned message.",
+	run:         checkSerializedBootstrapData,
+}, {
+	name:        "nonce_echo",
+	description: "The nonce in the signed bootstrap data matches the nonce of the request.",
+	run:         checkNonceEcho,
+}, {
+	name:        "ownership_voucher",
+	description: "The ownership voucher is a valid PKCS7 message issued for the active control card.",
+	run:         checkOwnershipVoucher,
+}, {
+	name:        "ownership_certificate_chain",
+	description: "The ownership certificate chains to the pinned domain certificate of the ownership voucher.",
+	run:         checkOwnershipCertificate,
+}, {
+	name:        "response_signature",
+	description: "response_signature is a valid signature over serialized_bootstrap_data by the ownership certificate.",
+	run:         checkResponseSignature,
+}, {
+	name:        "per_control_card_responses",
+	description: "There is exactly one bootstrap response for each control card, or for the chassis if it has none.",
+	run:         checkPerControlCardResponses,
+}, {
+	name:        "server_trust_cert",
+	description: "Every response carries the same server_trust_cert, a base64 encoded DER certificate or certificate chain.",
+	run:         checkServerTrustCert,
+}, {
+	name:        "secure_mode_requires_nonce",
+	description: "GetBootstrapData without a nonce is rejected for a chassis that only boots in secure mode.",
+	run:         checkSecureModeRequiresNonce,
+}, {
+	name:        "report_status_requires_states",
+	description: "ReportStatus without any control card states is rejected with InvalidArgument.",
+	run:         checkReportStatusRequiresStates,
+}, {
+	name:        "report_status_unknown_control_card",
+	description: "ReportStatus for a control card which is not part of the chassis is rejected.",
+	run:         checkReportStatusUnknownControlCard,
+}, {
+	name:        "report_status_success",
+	description: "ReportStatus succeeds when all control cards of the chassis report INITIALIZED.",
+	run:         checkReportStatusSuccess,
+}}
+
+// Run executes the conformance suite against the server reachable with the provided client.
+func Run(ctx context.Context, client bpb.BootstrapClient, cfg *Config) (*Report, error) {
+	if cfg == nil || cfg.Descriptor == nil {
+		return nil, fmt.Errorf("a chassis descriptor is required")
+	}
+	active := cfg.ActiveSerial
+	if active == "" {
+		active = cfg.Descriptor.GetSerialNumber()
+		if cc := cfg.Descriptor.GetControlCards(); len(cc) > 0 {
+			active = cc[0].GetSerialNumber()
+		}
+	}
+	nonce, err := generateNonce()
+	if err != nil {
+		return nil, err
+	}
+	e := &env{
+		cfg:    cfg,
+		client: client,
+		active: active,
+		nonce:  nonce,
+	}
+	report := &Report{
+		Target: cfg.Target,
+		Start:  time.Now(),
+	}
+	for _, c := range checks {
+		start := time.Now()
+		err := c.run(ctx, e)
+		res := &Result{
+			Name:        c.name,
+			Description: c.description,
+			Outcome:     Pass,
+			Duration:    time.Since(start),
+		}
+		var skip *errSkip
+		switch {
+		case errors.As(err, &skip):
+			res.Outcome = Skip
+			res.Message = skip.reason
+			report.Summary.Skipped++
+		case err != nil:
+			res.Outcome = Fail
+			res.Message = err.Error()
+			report.Summary.Failed++
+		default:
+			report.Summary.Passed++
+		}
+		log.Infof("Conformance check %v: %v %v", c.name, res.Outcome, res.Message)
+		report.Results = append(report.Results, res)
+	}
+	report.End = time.Now()
+	return report, nil
+}
+
+// generateNonce generates a fixed-length nonce.
+func generateNonce() (string, error) {
+	b := make([]byte, nonceLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func checkGetBootstrapData(ctx context.Context, e *env) error {
+	resp, err := e.client.GetBootstrapData(ctx, &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: e.cfg.Descriptor,
+		ControlCardState: &bpb.ControlCardState{
+			SerialNumber: e.active,
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_NOT_INITIALIZED,
+		},
+		Nonce: e.nonce,
+	})
+	if err != nil {
+		return fmt.Errorf("GetBootstrapData() err = %v, want nil", err)
+	}
+	e.resp = resp
+	return nil
+}
+
+func checkSerializedBootstrapData(ctx context.Context, e *env) error {
+	if e.resp == nil {
+		return skipf("no bootstrap data was retrieved")
+	}
+	if len(e.resp.GetSerializedBootstrapData()) == 0 {
+		return fmt.Errorf("serialized_bootstrap_data is empty")
+	}
+	signed := &bpb.BootstrapDataSigned{}
+	if err := proto.Unmarshal(e.resp.GetSerializedBootstrapData(), signed); err != nil {
+		return fmt.Errorf("serialized_bootstrap_data is not a BootstrapDataSigned message: %v", err)
+	}
+	e.signed = signed
+	return nil
+}
+
+func checkNonceEcho(ctx context.Context, e *env) error {
+	if e.signed == nil {
+		return skipf("no valid serialized bootstrap data")
+	}
+	if got := e.signed.GetNonce(); got != e.nonce {
+		return fmt.Errorf("nonce = %q, want %q", got, e.nonce)
+	}
+	return nil
+}
+
+func checkOwnershipVoucher(ctx context.Context, e *env) error {
+	if e.resp == nil {
+		return skipf("no bootstrap data was retrieved")
+	}
+	parsed, err := ownershipvoucher.Unmarshal(e.resp.GetOwnershipVoucher(), e.cfg.VendorCA)
+	if err != nil {
+		return err
+	}
+	if got := parsed.OV.SerialNumber; got != e.active {
+		return fmt.Errorf("ownership voucher serial number = %q, want %q", got, e.active)
+	}
+	if expires := parsed.OV.ExpiresOn; expires != "" {
+		t, err := time.Parse(time.RFC3339, expires)
+		if err != nil {
+			return fmt.Errorf("unable to parse ownership voucher expiry %q: %v", expires, err)
+		}
+		if time.Now().After(t) {
+			return fmt.Errorf("ownership voucher expired on %v", expires)
+		}
+	}
+	pdc, err := x509.ParseCertificate(parsed.OV.PinnedDomainCert)
+	if err != nil {
+		return fmt.Errorf("unable to parse pinned domain certificate: %v", err)
+	}
+	e.ov = &ownershipVoucher{
+		serial: parsed.OV.SerialNumber,
+		pdc:    pdc,
+	}
+	return nil
+}
+
+func checkOwnershipCertificate(ctx context.Context, e *env) error {
+	if e.ov == nil {
+		return skipf("no valid ownership voucher")
+	}
+	pdcPool := x509.NewCertPool()
+	pdcPool.AddCert(e.ov.pdc)
+	oc, err := ownercertificate.Verify(e.resp.GetOwnershipCertificate(), pdcPool)
+	if err != nil {
+		return err
+	}
+	e.oc = oc
+	return nil
+}
+
+func checkResponseSignature(ctx context.Context, e *env) error {
+	if e.oc == nil {
+		return skipf("no valid ownership certificate")
+	}
+	return signature.Verify(e.oc, e.resp.GetSerializedBootstrapData(), e.resp.GetResponseSignature())
+}
+
+func checkPerControlCardResponses(ctx context.Context, e *env) error {
+	if e.signed == nil {
+		return skipf("no valid serialized bootstrap data")
+	}
+	want := map[string]bool{}
+	for _, cc := range e.cfg.Descriptor.GetControlCards() {
+		want[cc.GetSerialNumber()] = true
+	}
+	if len(want) == 0 {
+		want[e.cfg.Descriptor.GetSerialNumber()] = true
+	}
+	got := map[string]bool{}
+	for _, r := range e.signed.GetResponses() {
+		serial := r.GetSerialNum()
+		if !want[serial] {
+			return fmt.Errorf("unexpected bootstrap response for serial %q", serial)
+		}
+		if got[serial] {
+			return fmt.Errorf("duplicate bootstrap response for serial %q", serial)
+		}
+		got[serial] = true
+	}
+	for serial := range want {
+		if !got[serial] {
+			return fmt.Errorf("missing bootstrap response for serial %q", serial)
+		}
+	}
+	return nil
+}
+
+func checkServerTrustCert(ctx context.Context, e *env) error {
+	if e.signed == nil {
+		return skipf("no valid serialized bootstrap data")
+	}
+	var want string
+	for i, r := range e.signed.GetResponses() {
+		cert := r.GetServerTrustCert()
+		if cert == "" {
+			return fmt.Errorf("response for serial %q has no server_trust_cert", r.GetSerialNum())
+		}
+		if i == 0 {
+			want = cert
+		} else if cert != want {
+			return fmt.Errorf("response for serial %q has a different server_trust_cert", r.GetSerialNum())
+		}
+	}
+	der, err := base64.StdEncoding.DecodeString(want)
+	if err != nil {
+		return fmt.Errorf("server_trust_cert is not base64 encoded: %v", err)
+	}
+	chain, err := x509.ParseCertificates(der)
+	if err != nil {
+		return fmt.Errorf("server_trust_cert is not a DER certificate chain: %v", err)
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return fmt.Errorf("server_trust_cert certificate %q is not issued by %q: %v", chain[i].Subject, chain[i+1].Subject, err)
+		}
+	}
+	return nil
+}
+
+func checkSecureModeRequiresNonce(ctx context.Context, e *env) error {
+	desc := e.cfg.SecureDescriptor
+	if desc == nil {
+		return skipf("no secure only chassis descriptor configured")
+	}
+	active := desc.GetSerialNumber()
+	if cc := desc.GetControlCards(); len(cc) > 0 {
+		active = cc[0].GetSerialNumber()
+	}
+	_, err := e.client.GetBootstrapData(ctx, &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: desc,
+		ControlCardState: &bpb.ControlCardState{
+			SerialNumber: active,
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_NOT_INITIALIZED,
+		},
+	})
+	if err == nil {
+		return fmt.Errorf("GetBootstrapData() without nonce succeeded for secure only chassis")
+	}
+	if c := status.Code(err); c != codes.InvalidArgument {
+		return fmt.Errorf("GetBootstrapData() without nonce returned code %v, want %v", c, codes.InvalidArgument)
+	}
+	return nil
+}
+
+func checkReportStatusRequiresStates(ctx context.Context, e *env) error {
+	_, err := e.client.ReportStatus(ctx, &bpb.ReportStatusRequest{
+		Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+		StatusMessage: "conformance: no states",
+	})
+	if err == nil {
+		return fmt.Errorf("ReportStatus() without states succeeded")
+	}
+	if c := status.Code(err); c != codes.InvalidArgument {
+		return fmt.Errorf("ReportStatus() without states returned code %v, want %v", c, codes.InvalidArgument)
+	}
+	return nil
+}
+
+func checkReportStatusUnknownControlCard(ctx context.Context, e *env) error {
+	if e.resp == nil {
+		return skipf("no bootstrap data was retrieved")
+	}
+	_, err := e.client.ReportStatus(ctx, &bpb.ReportStatusRequest{
+		Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+		StatusMessage: "conformance: unknown control card",
+		States: []*bpb.ControlCardState{{
+			SerialNumber: "conformance-unknown-" + e.nonce,
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED,
+		}},
+	})
+	if err == nil {
+		return fmt.Errorf("ReportStatus() for an unknown control card succeeded")
+	}
+	// The card is either not part of the chassis of the caller, or not known at all.
+	if c := status.Code(err); c != codes.PermissionDenied && c != codes.NotFound {
+		return fmt.Errorf("ReportStatus() for an unknown control card returned code %v, want %v or %v", c, codes.PermissionDenied, codes.NotFound)
+	}
+	return nil
+}
+
+func checkReportStatusSuccess(ctx context.Context, e *env) error {
+	if e.resp == nil {
+		return skipf("no bootstrap data was retrieved")
+	}
+	var states []*bpb.ControlCardState
+	for _, cc := range e.cfg.Descriptor.GetControlCards() {
+		states = append(states, &bpb.ControlCardState{
+			SerialNumber: cc.GetSerialNumber(),
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED,
+		})
+	}
+	if len(states) == 0 {
+		states = append(states, &bpb.ControlCardState{
+			SerialNumber: e.cfg.Descriptor.GetSerialNumber(),
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED,
+		})
+	}
+	_, err := e.client.ReportStatus(ctx, &bpb.ReportStatusRequest{
+		Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+		StatusMessage: "conformance: bootstrap success",
+		States:        states,
+	})
+	if err != nil {
+		return fmt.Errorf("ReportStatus() err = %v, want nil", err)
+	}
+	return nil
+}
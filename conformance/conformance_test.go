@@ -0,0 +1,132 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"flag"
+	"net"
+	"testing"
+
+	"github.com/openconfig/bootz/server/entitymanager"
+	"github.com/openconfig/bootz/server/service"
+	artifacts "github.com/openconfig/bootz/testdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+// When set, the suite runs against an external server instead of the reference implementation.
+var target = flag.String("conformance_target", "", "Address of the Bootz server under test. If unset, the reference server is started in-process.")
+
+var (
+	modularChassis = &bpb.ChassisDescriptor{
+		Manufacturer: "Cisco",
+		PartNumber:   "123",
+		ControlCards: []*bpb.ControlCard{
+			{SerialNumber: "123A", PartNumber: "123A", Slot: 1},
+			{SerialNumber: "123B", PartNumber: "123B", Slot: 2},
+		},
+	}
+	secureChassis = &bpb.ChassisDescriptor{
+		Manufacturer: "Cisco",
+		PartNumber:   "456",
+		SerialNumber: "456",
+	}
+)
+
+// startReferenceServer starts the reference Bootz server and returns its address and vendor CA.
+func startReferenceServer(t *testing.T) (string, *x509.Certificate) {
+	t.Helper()
+	sa, err := artifacts.GenerateSecurityArtifacts([]string{"123A", "123B", "456"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	em, err := entitymanager.New("../testdata/inventory_local.prototxt", sa)
+	if err != nil {
+		t.Fatalf("unable to create entity manager: %v", err)
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "456")
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{*sa.TLSKeypair},
+	})))
+	bpb.RegisterBootstrapServer(s, service.New(em))
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String(), sa.VendorCA
+}
+
+func TestConformance(t *testing.T) {
+	cfg := &Config{
+		Target:     *target,
+		Descriptor: modularChassis,
+	}
+	if cfg.Target == "" {
+		addr, vendorCA := startReferenceServer(t)
+		cfg.Target = addr
+		cfg.SecureDescriptor = secureChassis
+		cfg.VendorCA = x509.NewCertPool()
+		cfg.VendorCA.AddCert(vendorCA)
+	}
+	conn, err := grpc.Dial(cfg.Target, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	if err != nil {
+		t.Fatalf("unable to dial %v: %v", cfg.Target, err)
+	}
+	defer conn.Close()
+
+	report, err := Run(context.Background(), bpb.NewBootstrapClient(conn), cfg)
+	if err != nil {
+		t.Fatalf("Run() err = %v, want nil", err)
+	}
+	for _, r := range report.Results {
+		t.Run(r.Name, func(t *testing.T) {
+			switch r.Outcome {
+			case Fail:
+				t.Errorf("%v: %v", r.Description, r.Message)
+			case Skip:
+				t.Skip(r.Message)
+			}
+		})
+	}
+	if got, want := len(report.Results), len(checks); got != want {
+		t.Errorf("Run() returned %d results, want %d", got, want)
+	}
+
+	b, err := report.JSON()
+	if err != nil {
+		t.Fatalf("JSON() err = %v, want nil", err)
+	}
+	decoded := &Report{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("unable to decode report: %v", err)
+	}
+	if decoded.Summary != report.Summary {
+		t.Errorf("decoded report summary = %+v, want %+v", decoded.Summary, report.Summary)
+	}
+}
+
+func TestRunRequiresDescriptor(t *testing.T) {
+	if _, err := Run(context.Background(), nil, &Config{}); err == nil {
+		t.Errorf("Run() with no descriptor err = nil, want error")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main runs the Bootz conformance suite against a server.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/bootz/conformance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+// Describes a modular chassis with two control cards.
+const defaultChassisDescriptor = `
+manufacturer: 'Cisco'
+part_number: '123'
+control_cards {
+	serial_number: '123A'
+	slot: 1
+	part_number: '123A'
+}
+control_cards {
+	serial_number: '123B'
+	slot: 2
+	part_number: '123B'
+}
+`
+
+var (
+	target                  = flag.String("target", "localhost:15006", "Address of the Bootz server under test.")
+	chassisDescriptor       = flag.String("chassis_descriptor", defaultChassisDescriptor, "A textproto formatting of the ChassisDescriptor of a chassis known to the server.")
+	secureChassisDescriptor = flag.String("secure_chassis_descriptor", "", "A textproto formatting of the ChassisDescriptor of a chassis the server only boots in secure mode. If unset, the secure mode check is skipped.")
+	activeSerial            = flag.String("active_serial", "", "Serial number of the active control card. Defaults to the first control card of the descriptor.")
+	vendorCA                = flag.String("vendor_ca", "", "Path to a PEM file with the vendor CA certificates used to verify ownership vouchers.")
+	serverCA                = flag.String("server_ca", "", "Path to a PEM file with the CA certificates used to verify the server's TLS certificate. If unset, the server certificate is not verified.")
+	clientCert              = flag.String("client_cert", "", "Path to a PEM file with a client certificate, e.g. the IDevID of the active control card, to present to the server.")
+	clientKey               = flag.String("client_key", "", "Path to a PEM file with the private key of the client certificate.")
+	reportFile              = flag.String("report", "", "Path of the file to write the JSON report to. If unset, the report is written to stdout.")
+)
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+func parseDescriptor(text string) (*bpb.ChassisDescriptor, error) {
+	if text == "" {
+		return nil, nil
+	}
+	desc := &bpb.ChassisDescriptor{}
+	if err := prototext.Unmarshal([]byte(text), desc); err != nil {
+		return nil, fmt.Errorf("unable to parse chassis descriptor %q: %v", text, err)
+	}
+	return desc, nil
+}
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+
+	desc, err := parseDescriptor(*chassisDescriptor)
+	if err != nil {
+		log.Exit(err)
+	}
+	secureDesc, err := parseDescriptor(*secureChassisDescriptor)
+	if err != nil {
+		log.Exit(err)
+	}
+	vendorPool, err := loadCertPool(*vendorCA)
+	if err != nil {
+		log.Exitf("Unable to load vendor CA: %v", err)
+	}
+	serverPool, err := loadCertPool(*serverCA)
+	if err != nil {
+		log.Exitf("Unable to load server CA: %v", err)
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: serverPool == nil,
+		RootCAs:            serverPool,
+	}
+	if *clientCert != "" {
+		kp, err := tls.LoadX509KeyPair(*clientCert, *clientKey)
+		if err != nil {
+			log.Exitf("Unable to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{kp}
+	}
+	conn, err := grpc.Dial(*target, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		log.Exitf("Unable to connect to %v: %v", *target, err)
+	}
+	defer conn.Close()
+
+	report, err := conformance.Run(ctx, bpb.NewBootstrapClient(conn), &conformance.Config{
+		Target:           *target,
+		Descriptor:       desc,
+		ActiveSerial:     *activeSerial,
+		SecureDescriptor: secureDesc,
+		VendorCA:         vendorPool,
+	})
+	if err != nil {
+		log.Exitf("Unable to run conformance suite: %v", err)
+	}
+	out, err := report.JSON()
+	if err != nil {
+		log.Exitf("Unable to encode report: %v", err)
+	}
+	if *reportFile == "" {
+		fmt.Println(string(out))
+	} else if err := os.WriteFile(*reportFile, out, 0644); err != nil {
+		log.Exitf("Unable to write report: %v", err)
+	}
+	log.Infof("Conformance run finished: %d passed, %d failed, %d skipped", report.Summary.Passed, report.Summary.Failed, report.Summary.Skipped)
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
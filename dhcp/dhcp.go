@@ -92,6 +92,9 @@ type Server struct {
 var instance *Server = nil
 var lock = &sync.Mutex{}
 
+// registered is whether the plugins are registered with coredhcp, which only allows it once.
+var registered = false
+
 // Start starts the dhcp server with the given configuration.
 func Start(conf *Config) error {
 	lock.Lock()
@@ -100,28 +103,50 @@ func Start(conf *Config) error {
 	if instance != nil {
 		return fmt.Errorf("dhcp server already started")
 	}
+	return start(conf)
+}
+
+// Reload restarts the dhcp server with the given configuration, or starts it if it is not running.
+func Reload(conf *Config) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if instance != nil {
+		instance.server.Close()
+		instance.server.Wait()
+		instance = nil
+	}
+	return start(conf)
+}
 
+// start starts the dhcp server. The caller must hold lock.
+func start(conf *Config) error {
 	configFile, err := generateConfigFile(conf)
 	if err != nil {
 		return err
 	}
+	defer os.Remove(configFile)
 
 	c, err := cdconfig.Load(configFile)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %v", err)
 	}
 
-	for _, plugin := range desiredPlugins {
-		if err := cdplugins.RegisterPlugin(plugin); err != nil {
-			return fmt.Errorf("failed to register plugin '%s': %v", plugin.Name, err)
+	if !registered {
+		for _, plugin := range desiredPlugins {
+			if err := cdplugins.RegisterPlugin(plugin); err != nil {
+				return fmt.Errorf("failed to register plugin '%s': %v", plugin.Name, err)
+			}
 		}
+		registered = true
 	}
+	// Drop the leases of a previous configuration.
+	plslease.Reset()
 
 	srv, err := cdserver.Start(c)
 	if err != nil {
 		return fmt.Errorf("error starting DHCP server: %v", err)
 	}
-	os.Remove(configFile)
 
 	instance = &Server{
 		server: srv,
@@ -46,6 +46,12 @@ type ipv4Entry struct {
 var ipv4Records = map[string]*ipv4Entry{}
 var ipv6Records = map[string]net.IP{}
 
+// Reset drops the records of previous setups, before the plugin is set up with new ones.
+func Reset() {
+	ipv4Records = map[string]*ipv4Entry{}
+	ipv6Records = map[string]net.IP{}
+}
+
 func setup4(args ...string) (handler.Handler4, error) {
 	for _, r := range args {
 		if k, r, err := parseRecord4(r); err == nil {
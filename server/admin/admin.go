@@ -0,0 +1,396 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin implements the Bootz Admin service, which lets operators query and manage the server.
+package admin
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/bootz/server/certs"
+	"github.com/openconfig/bootz/server/entitymanager"
+	"github.com/openconfig/bootz/server/events"
+	"github.com/openconfig/bootz/server/faults"
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// Server implements the Admin service on top of an entity manager.
+type Server struct {
+	apb.UnimplementedAdminServer
+	em     *entitymanager.InMemoryEntityManager
+	events *events.Bus
+	faults *faults.Injector
+}
+
+// Option configures the admin server.
+type Option func(*Server)
+
+// WithFaultInjector lets operators set the faults injected by the provided injector.
+func WithFaultInjector(i *faults.Injector) Option {
+	return func(s *Server) {
+		s.faults = i
+	}
+}
+
+// New returns an admin server for the provided entity manager. Bootstrap events are watched on the provided bus.
+func New(em *entitymanager.InMemoryEntityManager, bus *events.Bus, opts ...Option) *Server {
+	s := &Server{em: em, events: bus}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetStatus returns the bootstrap status and history of the chassis with the requested serial,
+// which may also be the serial of one of its control cards.
+func (s *Server) GetStatus(ctx context.Context, req *apb.GetStatusRequest) (*apb.ChassisStatus, error) {
+	if req.GetSerialNumber() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "serial number is required")
+	}
+	for _, ch := range s.em.Snapshot().Chassis {
+		if req.GetManufacturer() != "" && ch.GetManufacturer() != req.GetManufacturer() {
+			continue
+		}
+		if ch.GetSerialNumber() == req.GetSerialNumber() {
+			return s.chassisStatus(ch), nil
+		}
+		for _, cc := range ch.GetControllerCards() {
+			if cc.GetSerialNumber() == req.GetSerialNumber() {
+				return s.chassisStatus(ch), nil
+			}
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "chassis %v not found in inventory", req.GetSerialNumber())
+}
+
+// ListChassis returns the bootstrap status of the chassis in the inventory, optionally filtered by state.
+func (s *Server) ListChassis(ctx context.Context, req *apb.ListChassisRequest) (*apb.ListChassisResponse, error) {
+	states := map[apb.ChassisState]bool{}
+	for _, st := range req.GetStates() {
+		states[st] = true
+	}
+	resp := &apb.ListChassisResponse{}
+	for _, ch := range s.em.Snapshot().Chassis {
+		cs := s.chassisStatus(ch)
+		if len(states) > 0 && !states[cs.GetState()] {
+			continue
+		}
+		resp.Chassis = append(resp.Chassis, cs)
+	}
+	return resp, nil
+}
+
+// WatchStatus streams the bootstrap events which match the request.
+func (s *Server) WatchStatus(req *apb.WatchStatusRequest, stream apb.Admin_WatchStatusServer) error {
+	if s.events == nil {
+		return status.Errorf(codes.Unimplemented, "bootstrap events are not enabled")
+	}
+	f := &events.Filter{
+		Manufacturer: req.GetManufacturer(),
+		Serial:       req.GetSerialNumber(),
+		Types:        req.GetTypes(),
+	}
+	err := s.events.Watch(stream.Context(), req.GetCursor(), f, stream.Send)
+	if stream.Context().Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// ListDiscovered returns the devices which requested bootstrap data but are not in the inventory,
+// optionally filtered by state.
+func (s *Server) ListDiscovered(ctx context.Context, req *apb.ListDiscoveredRequest) (*apb.ListDiscoveredResponse, error) {
+	states := map[apb.DiscoveryState]bool{}
+	for _, st := range req.GetStates() {
+		states[st] = true
+	}
+	resp := &apb.ListDiscoveredResponse{}
+	for _, d := range s.em.Discovered() {
+		dd := discoveredDevice(d)
+		if len(states) > 0 && !states[dd.GetState()] {
+			continue
+		}
+		resp.Devices = append(resp.Devices, dd)
+	}
+	return resp, nil
+}
+
+// ApproveDevice adds a discovered device to the inventory with the requested profile.
+func (s *Server) ApproveDevice(ctx context.Context, req *apb.ApproveDeviceRequest) (*apb.ChassisStatus, error) {
+	if req.GetSerialNumber() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "serial number is required")
+	}
+	ch, err := s.em.ApproveDevice(&service.EntityLookup{Manufacturer: req.GetManufacturer(), SerialNumber: req.GetSerialNumber()}, req.GetProfile(), req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return s.chassisStatus(ch), nil
+}
+
+// RejectDevice marks a discovered device as rejected.
+func (s *Server) RejectDevice(ctx context.Context, req *apb.RejectDeviceRequest) (*apb.DiscoveredDevice, error) {
+	if req.GetSerialNumber() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "serial number is required")
+	}
+	d, err := s.em.RejectDevice(&service.EntityLookup{Manufacturer: req.GetManufacturer(), SerialNumber: req.GetSerialNumber()}, req.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	return discoveredDevice(d), nil
+}
+
+// ReplaceControlCard swaps a control card of a chassis for its RMA replacement.
+func (s *Server) ReplaceControlCard(ctx context.Context, req *apb.ReplaceControlCardRequest) (*apb.ChassisStatus, error) {
+	ch, err := s.em.ReplaceControlCard(ctx, &entitymanager.ControlCardReplacement{
+		Manufacturer:     req.GetManufacturer(),
+		OldSerial:        req.GetOldSerial(),
+		NewSerial:        req.GetNewSerial(),
+		OwnershipVoucher: req.GetOwnershipVoucher(),
+		HardwareAddress:  req.GetHardwareAddress(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.chassisStatus(ch), nil
+}
+
+// ImportChassis merges chassis into the inventory, or only returns the changes of a dry run.
+func (s *Server) ImportChassis(ctx context.Context, req *apb.ImportChassisRequest) (*apb.ImportChassisResponse, error) {
+	if len(req.GetChassis()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no chassis to import")
+	}
+	changes, err := s.em.ImportChassis(req.GetChassis(), req.GetProfile(), req.GetDryRun())
+	if err != nil {
+		return nil, err
+	}
+	resp := &apb.ImportChassisResponse{}
+	for _, c := range changes {
+		resp.Changes = append(resp.Changes, &apb.ChassisChange{Before: c.Before, After: c.After})
+	}
+	return resp, nil
+}
+
+// ListCertificates returns the certificates issued to devices, optionally filtered by device. Only
+// current certificates are returned unless all are requested.
+func (s *Server) ListCertificates(ctx context.Context, req *apb.ListCertificatesRequest) (*apb.ListCertificatesResponse, error) {
+	issuer := s.em.CertIssuer()
+	if issuer == nil {
+		return nil, status.Errorf(codes.Unimplemented, "certificate issuance is not enabled")
+	}
+	resp := &apb.ListCertificatesResponse{}
+	for _, r := range issuer.Records() {
+		if req.GetSerialNumber() != "" && r.Serial != req.GetSerialNumber() {
+			continue
+		}
+		if !req.GetAll() && !r.Current() {
+			continue
+		}
+		resp.Certificates = append(resp.Certificates, issuedCertificate(issuer, r))
+	}
+	return resp, nil
+}
+
+// RevokeCertificate revokes a certificate issued to a device.
+func (s *Server) RevokeCertificate(ctx context.Context, req *apb.RevokeCertificateRequest) (*apb.IssuedCertificate, error) {
+	issuer := s.em.CertIssuer()
+	if issuer == nil {
+		return nil, status.Errorf(codes.Unimplemented, "certificate issuance is not enabled")
+	}
+	if req.GetCertificateSerial() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "certificate serial is required")
+	}
+	r, err := issuer.Revoke(strings.ToLower(req.GetCertificateSerial()))
+	if errors.Is(err, certs.ErrUnknownCertificate) {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to revoke certificate %v: %v", req.GetCertificateSerial(), err)
+	}
+	return issuedCertificate(issuer, r), nil
+}
+
+// SetFaults replaces the faults injected for a chassis or control card.
+func (s *Server) SetFaults(ctx context.Context, req *apb.SetFaultsRequest) (*apb.DeviceFaults, error) {
+	if s.faults == nil {
+		return nil, status.Errorf(codes.Unimplemented, "fault injection is not enabled")
+	}
+	if req.GetSerialNumber() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "serial number is required")
+	}
+	for _, f := range req.GetFaults() {
+		if f == epb.Fault_FAULT_UNSPECIFIED {
+			return nil, status.Errorf(codes.InvalidArgument, "fault %v is not a fault", f)
+		}
+	}
+	s.faults.SetFaults(req.GetSerialNumber(), req.GetFaults()...)
+	return &apb.DeviceFaults{SerialNumber: req.GetSerialNumber(), Faults: s.faults.Faults(req.GetSerialNumber())}, nil
+}
+
+// ListFaults returns the faults injected for each chassis and control card, by serial.
+func (s *Server) ListFaults(ctx context.Context, req *apb.ListFaultsRequest) (*apb.ListFaultsResponse, error) {
+	if s.faults == nil {
+		return nil, status.Errorf(codes.Unimplemented, "fault injection is not enabled")
+	}
+	all := s.faults.AllFaults()
+	serials := make([]string, 0, len(all))
+	for serial := range all {
+		serials = append(serials, serial)
+	}
+	sort.Strings(serials)
+	resp := &apb.ListFaultsResponse{}
+	for _, serial := range serials {
+		resp.Devices = append(resp.Devices, &apb.DeviceFaults{SerialNumber: serial, Faults: all[serial]})
+	}
+	return resp, nil
+}
+
+func issuedCertificate(issuer *certs.Issuer, r certs.Record) *apb.IssuedCertificate {
+	c := &apb.IssuedCertificate{
+		CertificateSerial: r.SerialNumber,
+		Manufacturer:      r.Manufacturer,
+		SerialNumber:      r.Serial,
+		CommonName:        r.CommonName,
+		DnsNames:          r.DNSNames,
+		IpAddresses:       r.IPAddresses,
+		NotBefore:         timestamppb.New(r.NotBefore),
+		NotAfter:          timestamppb.New(r.NotAfter),
+		SupersededBy:      r.SupersededBy,
+		RenewalDue:        issuer.RenewalDue(r),
+	}
+	if !r.Revoked.IsZero() {
+		c.Revoked = timestamppb.New(r.Revoked)
+	}
+	return c
+}
+
+func discoveredDevice(d *entitymanager.DiscoveredDevice) *apb.DiscoveredDevice {
+	out := &apb.DiscoveredDevice{
+		Manufacturer: d.Manufacturer,
+		SerialNumber: d.SerialNumber,
+		PartNumber:   d.PartNumber,
+		ControlCards: d.ControlCards,
+		ActiveSerial: d.ActiveSerial,
+		PeerAddress:  d.Peer,
+		NoncePresent: d.NoncePresent,
+		FirstSeen:    timestamppb.New(d.FirstSeen),
+		LastSeen:     timestamppb.New(d.LastSeen),
+		RequestCount: d.Requests,
+		State:        apb.DiscoveryState_DISCOVERY_STATE_PENDING,
+		RejectReason: d.RejectReason,
+	}
+	if d.Rejected {
+		out.State = apb.DiscoveryState_DISCOVERY_STATE_REJECTED
+	}
+	return out
+}
+
+// chassisStatus returns the status of an inventory chassis.
+func (s *Server) chassisStatus(ch *epb.Chassis) *apb.ChassisStatus {
+	out := &apb.ChassisStatus{
+		Manufacturer: ch.GetManufacturer(),
+		SerialNumber: ch.GetSerialNumber(),
+		State:        apb.ChassisState_CHASSIS_STATE_NEVER_SEEN,
+		Provisional:  ch.GetProvisional(),
+		Profile:      ch.GetProfile(),
+	}
+	lookup := &service.EntityLookup{Manufacturer: ch.GetManufacturer(), SerialNumber: ch.GetSerialNumber()}
+	cs, err := s.em.GetStatus(lookup)
+	if err != nil {
+		// Rejected requests of a chassis which was never seen are still listed.
+		out.History = transitions(s.em.AuditHistory(lookup))
+		for _, cc := range ch.GetControllerCards() {
+			out.ControlCards = append(out.ControlCards, &apb.ControlCardStatus{SerialNumber: cc.GetSerialNumber()})
+		}
+		return out
+	}
+	out.State = State(cs)
+	out.BootstrapStatus = cs.Status
+	out.StatusMessage = cs.StatusMessage
+	out.LastUpdate = timestamppb.New(cs.Updated)
+	out.History = transitions(cs.History)
+
+	// Include the control cards of the inventory and any other reported serial, e.g. of a fixed
+	// chassis or of a replaced control card.
+	current := map[string]bool{ch.GetSerialNumber(): true}
+	serials := map[string]bool{}
+	for _, cc := range ch.GetControllerCards() {
+		serials[cc.GetSerialNumber()] = true
+		current[cc.GetSerialNumber()] = true
+	}
+	for serial := range cs.ControlCardHistory {
+		serials[serial] = true
+	}
+	var sorted []string
+	for serial := range serials {
+		sorted = append(sorted, serial)
+	}
+	sort.Strings(sorted)
+	for _, serial := range sorted {
+		out.ControlCards = append(out.ControlCards, &apb.ControlCardStatus{
+			SerialNumber:    serial,
+			Status:          cs.ControlCards[serial],
+			History:         transitions(cs.ControlCardHistory[serial]),
+			ServedArtifacts: servedArtifacts(cs.ServedArtifacts[serial]),
+			Replaced:        !current[serial],
+		})
+	}
+	return out
+}
+
+// State derives the bootstrap state of a chassis from its status.
+func State(cs *entitymanager.ChassisStatus) apb.ChassisState {
+	switch {
+	case cs == nil:
+		return apb.ChassisState_CHASSIS_STATE_NEVER_SEEN
+	case cs.Status == bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS:
+		return apb.ChassisState_CHASSIS_STATE_INITIALIZED
+	case cs.Status == bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE:
+		return apb.ChassisState_CHASSIS_STATE_FAILED
+	default:
+		return apb.ChassisState_CHASSIS_STATE_IN_PROGRESS
+	}
+}
+
+func transitions(h []entitymanager.StatusTransition) []*apb.StatusTransition {
+	var out []*apb.StatusTransition
+	for _, t := range h {
+		out = append(out, &apb.StatusTransition{
+			SerialNumber:      t.Serial,
+			ControlCardStatus: t.ControlCardStatus,
+			BootstrapStatus:   t.BootstrapStatus,
+			Message:           t.Message,
+			Timestamp:         timestamppb.New(t.Time),
+			Source:            t.Source,
+		})
+	}
+	return out
+}
+
+func servedArtifacts(versions []entitymanager.ArtifactVersion) []*apb.ServedArtifact {
+	var out []*apb.ServedArtifact
+	for _, v := range versions {
+		out = append(out, &apb.ServedArtifact{Kind: v.Kind, Path: v.Path, Digest: v.Digest})
+	}
+	return out
+}
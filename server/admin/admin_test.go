@@ -0,0 +1,532 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/bootz/server/certs"
+	"github.com/openconfig/bootz/server/entitymanager"
+	"github.com/openconfig/bootz/server/events"
+	"github.com/openconfig/bootz/server/faults"
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+func newEntityManager(t *testing.T, opts ...entitymanager.Option) *entitymanager.InMemoryEntityManager {
+	t.Helper()
+	em, err := entitymanager.New("../../testdata/inventory.prototxt", &service.SecurityArtifacts{TrustAnchor: &x509.Certificate{}}, opts...)
+	if err != nil {
+		t.Fatalf("unable to create entity manager: %v", err)
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "456")
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "789")
+	return em
+}
+
+func report(ctx context.Context, t *testing.T, em *entitymanager.InMemoryEntityManager, serial string, st bpb.ReportStatusRequest_BootstrapStatus, cc bpb.ControlCardState_ControlCardStatus) {
+	t.Helper()
+	err := em.SetStatus(ctx, &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial}, serial, &bpb.ReportStatusRequest{
+		Status:        st,
+		StatusMessage: st.String(),
+		States:        []*bpb.ControlCardState{{SerialNumber: serial, Status: cc}},
+	})
+	if err != nil {
+		t.Fatalf("SetStatus() err = %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}})
+	em := newEntityManager(t, entitymanager.WithHistorySize(2))
+	lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+	for _, serial := range []string{"123A", "123B"} {
+		if _, err := em.GetBootstrapData(ctx, lookup, &bpb.ControlCard{SerialNumber: serial}); err != nil {
+			t.Fatalf("GetBootstrapData() err = %v", err)
+		}
+	}
+	report(ctx, t, em, "123A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS, bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED)
+	report(ctx, t, em, "456", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE, bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED)
+	// A rejected request does not make a chassis seen.
+	em.Audit(ctx, &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "789"}, "789", "nonce 01234567 rejected")
+
+	s := New(em, nil)
+	got, err := s.GetStatus(ctx, &apb.GetStatusRequest{SerialNumber: "123A"})
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if got.GetState() != apb.ChassisState_CHASSIS_STATE_INITIALIZED || got.GetSerialNumber() != "123" {
+		t.Errorf("GetStatus() = %v chassis %v, want %v chassis 123", got.GetState(), got.GetSerialNumber(), apb.ChassisState_CHASSIS_STATE_INITIALIZED)
+	}
+	// The chassis history is bounded to the last two transitions.
+	var history []string
+	for _, h := range got.GetHistory() {
+		history = append(history, h.GetSerialNumber()+":"+h.GetMessage())
+		if h.GetSource() != "192.0.2.1:1234" {
+			t.Errorf("transition source = %q, want %q", h.GetSource(), "192.0.2.1:1234")
+		}
+	}
+	if want := []string{"123B:bootstrap data served", "123A:BOOTSTRAP_STATUS_SUCCESS"}; !cmp.Equal(history, want) {
+		t.Errorf("GetStatus() history = %v, want %v", history, want)
+	}
+	cards := map[string]int{}
+	for _, cc := range got.GetControlCards() {
+		cards[cc.GetSerialNumber()] = len(cc.GetHistory())
+	}
+	if want := map[string]int{"123A": 2, "123B": 1}; !cmp.Equal(cards, want) {
+		t.Errorf("GetStatus() control card history lengths = %v, want %v", cards, want)
+	}
+
+	got, err = s.GetStatus(ctx, &apb.GetStatusRequest{SerialNumber: "789"})
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if got.GetState() != apb.ChassisState_CHASSIS_STATE_NEVER_SEEN || len(got.GetHistory()) != 1 {
+		t.Errorf("GetStatus() of an audited chassis = %v with %d transitions, want %v with the audited decision", got.GetState(), len(got.GetHistory()), apb.ChassisState_CHASSIS_STATE_NEVER_SEEN)
+	}
+
+	if _, err := s.GetStatus(ctx, &apb.GetStatusRequest{SerialNumber: "000"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetStatus() of unknown chassis err = %v, want NotFound", err)
+	}
+
+	tests := []struct {
+		desc   string
+		states []apb.ChassisState
+		want   []string
+	}{{
+		desc: "All chassis",
+		want: []string{"123", "456", "789"},
+	}, {
+		desc:   "Never seen",
+		states: []apb.ChassisState{apb.ChassisState_CHASSIS_STATE_NEVER_SEEN},
+		want:   []string{"789"},
+	}, {
+		desc:   "Failed or initialized",
+		states: []apb.ChassisState{apb.ChassisState_CHASSIS_STATE_FAILED, apb.ChassisState_CHASSIS_STATE_INITIALIZED},
+		want:   []string{"123", "456"},
+	}, {
+		desc:   "In progress",
+		states: []apb.ChassisState{apb.ChassisState_CHASSIS_STATE_IN_PROGRESS},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			resp, err := s.ListChassis(ctx, &apb.ListChassisRequest{States: test.states})
+			if err != nil {
+				t.Fatalf("ListChassis() err = %v", err)
+			}
+			var got []string
+			for _, ch := range resp.GetChassis() {
+				got = append(got, ch.GetSerialNumber())
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Errorf("ListChassis(%v) = %v, want %v", test.states, got, test.want)
+			}
+		})
+	}
+}
+
+func TestWatchStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	bus := events.NewBus(events.DefaultBufferSize)
+	em := newEntityManager(t, entitymanager.WithEvents(bus))
+	gs := grpc.NewServer()
+	apb.RegisterAdminServer(gs, New(em, bus))
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go gs.Serve(lis)
+	defer gs.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unable to dial: %v", err)
+	}
+	defer conn.Close()
+	client := apb.NewAdminClient(conn)
+
+	cursor := bus.Cursor()
+	report(ctx, t, em, "456", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE, bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED)
+	report(ctx, t, em, "123A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS, bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED)
+
+	stream, err := client.WatchStatus(ctx, &apb.WatchStatusRequest{SerialNumber: "123A", Cursor: cursor})
+	if err != nil {
+		t.Fatalf("WatchStatus() err = %v", err)
+	}
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() err = %v", err)
+	}
+	if e.GetType() != apb.EventType_EVENT_TYPE_STATUS_REPORTED || e.GetChassisSerial() != "123" {
+		t.Errorf("Recv() = %v event for chassis %v, want %v for chassis 123", e.GetType(), e.GetChassisSerial(), apb.EventType_EVENT_TYPE_STATUS_REPORTED)
+	}
+
+	// A watcher resuming from the cursor of the failure receives the events after it.
+	stream, err = client.WatchStatus(ctx, &apb.WatchStatusRequest{Types: []apb.EventType{apb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED}, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("WatchStatus() err = %v", err)
+	}
+	e, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv() err = %v", err)
+	}
+	if e.GetChassisSerial() != "456" {
+		t.Errorf("Recv() = event for chassis %v, want chassis 456", e.GetChassisSerial())
+	}
+
+	stream, err = client.WatchStatus(ctx, &apb.WatchStatusRequest{Cursor: "unknown-1"})
+	if err != nil {
+		t.Fatalf("WatchStatus() err = %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.OutOfRange {
+		t.Errorf("Recv() with a stale cursor err = %v, want OutOfRange", err)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.9"), Port: 1234}})
+	em := newEntityManager(t)
+	svc := service.New(em, service.WithDiscoverer(em))
+	s := New(em, nil)
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			SerialNumber: "900",
+			PartNumber:   "PN-900",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "900A", PartNumber: "PN1"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "900A"},
+	}
+	if _, err := svc.GetBootstrapData(ctx, req); err == nil {
+		t.Fatalf("GetBootstrapData() of an unknown chassis succeeded")
+	}
+
+	list, err := s.ListDiscovered(ctx, &apb.ListDiscoveredRequest{States: []apb.DiscoveryState{apb.DiscoveryState_DISCOVERY_STATE_PENDING}})
+	if err != nil {
+		t.Fatalf("ListDiscovered() err = %v", err)
+	}
+	if len(list.GetDevices()) != 1 {
+		t.Fatalf("ListDiscovered() = %v, want 1 device", list)
+	}
+	d := list.GetDevices()[0]
+	if d.GetSerialNumber() != "900" || d.GetPeerAddress() != "192.0.2.9:1234" || d.GetNoncePresent() || d.GetActiveSerial() != "900A" || len(d.GetControlCards()) != 1 {
+		t.Errorf("ListDiscovered() device = %v, want chassis 900 from 192.0.2.9 without nonce", d)
+	}
+
+	if _, err := s.ApproveDevice(ctx, &apb.ApproveDeviceRequest{SerialNumber: "900A", Profile: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("ApproveDevice() with unknown profile err = %v, want NotFound", err)
+	}
+	cs, err := s.ApproveDevice(ctx, &apb.ApproveDeviceRequest{SerialNumber: "900A", Profile: "default"})
+	if err != nil {
+		t.Fatalf("ApproveDevice() err = %v", err)
+	}
+	if cs.GetSerialNumber() != "900" || cs.GetState() != apb.ChassisState_CHASSIS_STATE_NEVER_SEEN {
+		t.Errorf("ApproveDevice() = %v, want never seen chassis 900", cs)
+	}
+	if _, err := svc.GetBootstrapData(ctx, req); err != nil {
+		t.Errorf("GetBootstrapData() of an approved chassis err = %v", err)
+	}
+	list, err = s.ListDiscovered(ctx, &apb.ListDiscoveredRequest{})
+	if err != nil || len(list.GetDevices()) != 0 {
+		t.Errorf("ListDiscovered() after approval = %v, %v, want no devices", list, err)
+	}
+
+	// Rejected devices keep failing and are listed as rejected.
+	req.ChassisDescriptor.SerialNumber = "901"
+	req.ChassisDescriptor.ControlCards[0].SerialNumber = "901A"
+	req.ControlCardState.SerialNumber = "901A"
+	svc.GetBootstrapData(ctx, req)
+	if _, err := s.RejectDevice(ctx, &apb.RejectDeviceRequest{SerialNumber: "901", Reason: "unknown vendor"}); err != nil {
+		t.Fatalf("RejectDevice() err = %v", err)
+	}
+	if _, err := svc.GetBootstrapData(ctx, req); err == nil {
+		t.Errorf("GetBootstrapData() of a rejected chassis succeeded")
+	}
+	list, err = s.ListDiscovered(ctx, &apb.ListDiscoveredRequest{States: []apb.DiscoveryState{apb.DiscoveryState_DISCOVERY_STATE_REJECTED}})
+	if err != nil {
+		t.Fatalf("ListDiscovered() err = %v", err)
+	}
+	if len(list.GetDevices()) != 1 || list.GetDevices()[0].GetRejectReason() != "unknown vendor" || list.GetDevices()[0].GetRequestCount() != 2 {
+		t.Errorf("ListDiscovered(rejected) = %v, want chassis 901 rejected after 2 requests", list)
+	}
+}
+
+func TestFallbackProfile(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.9"), Port: 1234}})
+	em := newEntityManager(t)
+	svc := service.New(em, service.WithDiscoverer(em))
+	s := New(em, nil)
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			SerialNumber: "SPARE1",
+			PartNumber:   "8201-SYS",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "SPARE1A", PartNumber: "8201-RP"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "SPARE1A"},
+	}
+	resp, err := svc.GetBootstrapData(ctx, req)
+	if err != nil {
+		t.Fatalf("GetBootstrapData() of a spare err = %v", err)
+	}
+	if got := resp.GetSignedResponse().GetResponses()[0].GetIntendedImage().GetVersion(); got != "0.9" {
+		t.Errorf("GetBootstrapData() image version = %q, want the baseline 0.9", got)
+	}
+	cs, err := s.GetStatus(ctx, &apb.GetStatusRequest{SerialNumber: "SPARE1A"})
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if !cs.GetProvisional() || cs.GetProfile() != "spares" || cs.GetState() != apb.ChassisState_CHASSIS_STATE_IN_PROGRESS {
+		t.Errorf("GetStatus() = %v, want provisional chassis in progress with the spares profile", cs)
+	}
+}
+
+func TestReplaceControlCard(t *testing.T) {
+	ctx := context.Background()
+	em := newEntityManager(t, entitymanager.WithOVGenerator(func(serial string) ([]byte, error) {
+		return []byte("ov-" + serial), nil
+	}))
+	s := New(em, nil)
+	if _, err := s.ReplaceControlCard(ctx, &apb.ReplaceControlCardRequest{OldSerial: "123B"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ReplaceControlCard() without a new serial err = %v, want InvalidArgument", err)
+	}
+	cs, err := s.ReplaceControlCard(ctx, &apb.ReplaceControlCardRequest{OldSerial: "123B", NewSerial: "123C"})
+	if err != nil {
+		t.Fatalf("ReplaceControlCard() err = %v", err)
+	}
+	// The replaced card is kept with its history.
+	var serials []string
+	for _, cc := range cs.GetControlCards() {
+		serial := cc.GetSerialNumber()
+		if cc.GetReplaced() {
+			serial += " (replaced)"
+		}
+		serials = append(serials, serial)
+	}
+	if want := []string{"123A", "123B (replaced)", "123C"}; cs.GetSerialNumber() != "123" || !cmp.Equal(serials, want) {
+		t.Errorf("ReplaceControlCard() = chassis %v with cards %v, want chassis 123 with cards %v", cs.GetSerialNumber(), serials, want)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	ctx := context.Background()
+	em := newEntityManager(t)
+	if _, err := New(em, nil).ListFaults(ctx, &apb.ListFaultsRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("ListFaults() without an injector err = %v, want Unimplemented", err)
+	}
+	s := New(em, nil, WithFaultInjector(faults.New(em, nil)))
+	if _, err := s.SetFaults(ctx, &apb.SetFaultsRequest{Faults: []epb.Fault{epb.Fault_FAULT_WRONG_NONCE}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetFaults() without a serial err = %v, want InvalidArgument", err)
+	}
+	if _, err := s.SetFaults(ctx, &apb.SetFaultsRequest{SerialNumber: "123A", Faults: []epb.Fault{epb.Fault_FAULT_UNSPECIFIED}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetFaults() with an unspecified fault err = %v, want InvalidArgument", err)
+	}
+	for _, req := range []*apb.SetFaultsRequest{
+		{SerialNumber: "456", Faults: []epb.Fault{epb.Fault_FAULT_EXPIRED_OV}},
+		{SerialNumber: "123A", Faults: []epb.Fault{epb.Fault_FAULT_WRONG_NONCE, epb.Fault_FAULT_TRUNCATED_DATA}},
+	} {
+		df, err := s.SetFaults(ctx, req)
+		if err != nil {
+			t.Fatalf("SetFaults(%v) err = %v", req, err)
+		}
+		if !cmp.Equal(df.GetFaults(), req.GetFaults()) {
+			t.Errorf("SetFaults(%v) = %v, want %v", req, df.GetFaults(), req.GetFaults())
+		}
+	}
+	if _, err := s.SetFaults(ctx, &apb.SetFaultsRequest{SerialNumber: "456"}); err != nil {
+		t.Fatalf("SetFaults() clearing 456 err = %v", err)
+	}
+	resp, err := s.ListFaults(ctx, &apb.ListFaultsRequest{})
+	if err != nil {
+		t.Fatalf("ListFaults() err = %v", err)
+	}
+	var got []string
+	for _, d := range resp.GetDevices() {
+		for _, f := range d.GetFaults() {
+			got = append(got, d.GetSerialNumber()+" "+f.String())
+		}
+	}
+	if want := []string{"123A FAULT_WRONG_NONCE", "123A FAULT_TRUNCATED_DATA"}; !cmp.Equal(got, want) {
+		t.Errorf("ListFaults() = %v, want %v", got, want)
+	}
+}
+
+func TestImportChassis(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.9"), Port: 1234}})
+	em := newEntityManager(t)
+	svc := service.New(em, service.WithDiscoverer(em))
+	s := New(em, nil)
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			SerialNumber: "900",
+			PartNumber:   "PN-900",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "900A", PartNumber: "PN1"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "900A"},
+	}
+	svc.GetBootstrapData(ctx, req)
+	if list, err := s.ListDiscovered(ctx, &apb.ListDiscoveredRequest{}); err != nil || len(list.GetDevices()) != 1 {
+		t.Fatalf("ListDiscovered() = %v, %v, want chassis 900", list, err)
+	}
+
+	if _, err := s.ImportChassis(ctx, &apb.ImportChassisRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ImportChassis() without chassis err = %v, want InvalidArgument", err)
+	}
+	imported := []*epb.Chassis{
+		{Manufacturer: "Cisco", SerialNumber: "900", ControllerCards: []*epb.ControlCard{{SerialNumber: "900A"}}},
+		{Manufacturer: "Cisco", SerialNumber: "456", PartNumber: "PN-456"},
+	}
+	if _, err := s.ImportChassis(ctx, &apb.ImportChassisRequest{Chassis: imported, Profile: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("ImportChassis() with unknown profile err = %v, want NotFound", err)
+	}
+	dryRun, err := s.ImportChassis(ctx, &apb.ImportChassisRequest{Chassis: imported, Profile: "default", DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportChassis() dry run err = %v", err)
+	}
+	if got := len(dryRun.GetChanges()); got != 2 || dryRun.GetChanges()[0].GetBefore() != nil || dryRun.GetChanges()[1].GetBefore().GetSerialNumber() != "456" {
+		t.Errorf("ImportChassis() dry run = %v, want chassis 900 added and chassis 456 updated", dryRun)
+	}
+	if _, err := svc.GetBootstrapData(ctx, req); err == nil {
+		t.Errorf("GetBootstrapData() after a dry run succeeded")
+	}
+
+	resp, err := s.ImportChassis(ctx, &apb.ImportChassisRequest{Chassis: imported, Profile: "default"})
+	if err != nil {
+		t.Fatalf("ImportChassis() err = %v", err)
+	}
+	if len(resp.GetChanges()) != 2 {
+		t.Errorf("ImportChassis() = %v, want 2 changes", resp)
+	}
+	got, err := svc.GetBootstrapData(ctx, req)
+	if err != nil {
+		t.Fatalf("GetBootstrapData() of an imported chassis err = %v", err)
+	}
+	if v := got.GetSignedResponse().GetResponses()[0].GetIntendedImage().GetVersion(); v != "1.0" {
+		t.Errorf("GetBootstrapData() image version = %q, want 1.0 from the default profile", v)
+	}
+	list, err := s.ListDiscovered(ctx, &apb.ListDiscoveredRequest{})
+	if err != nil || len(list.GetDevices()) != 0 {
+		t.Errorf("ListDiscovered() after import = %v, %v, want no devices", list, err)
+	}
+	cs, err := s.GetStatus(ctx, &apb.GetStatusRequest{SerialNumber: "456"})
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if cs.GetProfile() != "default" {
+		t.Errorf("GetStatus() of updated chassis 456 = %v, want profile default", cs)
+	}
+}
+
+// bootstrap returns the bootstrap data of control card 123A served by the bootz service, to a
+// device presenting its IDevID if idevid is set.
+func bootstrap(t *testing.T, svc *service.Service, idevid bool) *bpb.BootstrapDataResponse {
+	t.Helper()
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234}}
+	if idevid {
+		p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{SerialNumber: "123A"}}}},
+		}}
+	}
+	resp, err := svc.GetBootstrapData(peer.NewContext(context.Background(), p), &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			PartNumber:   "123",
+			SerialNumber: "123",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "123A", PartNumber: "123A"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "123A"},
+	})
+	if err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	return resp.GetSignedResponse().GetResponses()[0]
+}
+
+func TestCertificates(t *testing.T) {
+	ctx := context.Background()
+	if _, err := New(newEntityManager(t), nil).ListCertificates(ctx, &apb.ListCertificatesRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("ListCertificates() without an issuer err = %v, want Unimplemented", err)
+	}
+	issuer, err := certs.Open(t.TempDir(), certs.DefaultValidity, certs.DefaultRenewBefore)
+	if err != nil {
+		t.Fatalf("certs.Open() err = %v", err)
+	}
+	em := newEntityManager(t, entitymanager.WithCertIssuer(issuer))
+	s := New(em, nil)
+	svc := service.New(em, service.WithCommitter(em))
+	resp := bootstrap(t, svc, true)
+	if got := len(resp.GetCertificates().GetEntities()); got != 2 {
+		t.Errorf("GetBootstrapData() certificates have %d entities, want the certificate chain and the trust bundle", got)
+	}
+
+	list, err := s.ListCertificates(ctx, &apb.ListCertificatesRequest{SerialNumber: "123A"})
+	if err != nil {
+		t.Fatalf("ListCertificates() err = %v", err)
+	}
+	if len(list.GetCertificates()) != 1 || list.GetCertificates()[0].GetCommonName() != "test" || list.GetCertificates()[0].GetRenewalDue() {
+		t.Fatalf("ListCertificates() = %v, want the certificate of 123A", list)
+	}
+	serial := list.GetCertificates()[0].GetCertificateSerial()
+	if list, err := s.ListCertificates(ctx, &apb.ListCertificatesRequest{SerialNumber: "123B"}); err != nil || len(list.GetCertificates()) != 0 {
+		t.Errorf("ListCertificates() of 123B = %v, %v, want no certificates", list, err)
+	}
+
+	// Requests which are not authenticated get no certificate, and leave the device's one current.
+	if got := bootstrap(t, svc, false).GetCertificates(); got != nil {
+		t.Errorf("GetBootstrapData() without an IDevID certificates = %v, want none", got)
+	}
+	if list, err := s.ListCertificates(ctx, &apb.ListCertificatesRequest{SerialNumber: "123A"}); err != nil || len(list.GetCertificates()) != 1 || list.GetCertificates()[0].GetCertificateSerial() != serial {
+		t.Errorf("ListCertificates() after an unauthenticated request = %v, %v, want the certificate %v", list, err, serial)
+	}
+
+	if _, err := s.RevokeCertificate(ctx, &apb.RevokeCertificateRequest{CertificateSerial: "abc"}); status.Code(err) != codes.NotFound {
+		t.Errorf("RevokeCertificate() of an unknown certificate err = %v, want NotFound", err)
+	}
+	revoked, err := s.RevokeCertificate(ctx, &apb.RevokeCertificateRequest{CertificateSerial: serial})
+	if err != nil {
+		t.Fatalf("RevokeCertificate() err = %v", err)
+	}
+	if revoked.GetRevoked() == nil {
+		t.Errorf("RevokeCertificate() = %v, want a revocation time", revoked)
+	}
+	if list, err := s.ListCertificates(ctx, &apb.ListCertificatesRequest{}); err != nil || len(list.GetCertificates()) != 0 {
+		t.Errorf("ListCertificates() after revocation = %v, %v, want no current certificates", list, err)
+	}
+	if list, err := s.ListCertificates(ctx, &apb.ListCertificatesRequest{All: true}); err != nil || len(list.GetCertificates()) != 1 {
+		t.Errorf("ListCertificates() of all certificates = %v, %v, want the revoked certificate", list, err)
+	}
+
+	resp = bootstrap(t, svc, true)
+	if got := resp.GetCertificates().GetEntities(); len(got) != 3 || got[0].GetVersion() == serial || got[2].GetCertificateRevocationListBundle() == nil {
+		t.Errorf("GetBootstrapData() after revocation = %v, want a new certificate and a CRL", got)
+	}
+}
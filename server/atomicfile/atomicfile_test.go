@@ -0,0 +1,58 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	for _, data := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("WriteFile(%q) err = %v", data, err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile() err = %v", err)
+		}
+		if string(got) != data {
+			t.Errorf("ReadFile() = %q, want %q", got, data)
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() err = %v", err)
+	}
+	if got := fi.Mode().Perm(); got != 0o600 {
+		t.Errorf("WriteFile() mode = %v, want %v", got, os.FileMode(0o600))
+	}
+	// No temporary file is left behind.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() err = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("ReadDir() = %d entries, want 1", len(entries))
+	}
+
+	// Writing into a missing directory fails.
+	if err := WriteFile(filepath.Join(dir, "missing", "state.json"), []byte("third"), 0o600); err == nil {
+		t.Errorf("WriteFile() into a missing directory err = nil, want error")
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	cpb "github.com/openconfig/gnsi/certz"
+)
+
+// parseUpload returns the device certificate, the trust bundle and the CRL of an upload request.
+func parseUpload(t *testing.T, req *cpb.UploadRequest) (*x509.Certificate, *x509.CertPool, *x509.RevocationList) {
+	t.Helper()
+	var leaf *x509.Certificate
+	roots := x509.NewCertPool()
+	var crl *x509.RevocationList
+	for _, e := range req.GetEntities() {
+		switch {
+		case e.GetCertificateChain() != nil:
+			c := e.GetCertificateChain().GetCertificate()
+			pair, err := tls.X509KeyPair(c.GetCertificate(), c.GetPrivateKey())
+			if err != nil {
+				t.Fatalf("X509KeyPair() err = %v", err)
+			}
+			if leaf, err = x509.ParseCertificate(pair.Certificate[0]); err != nil {
+				t.Fatalf("ParseCertificate() err = %v", err)
+			}
+			if e.GetCertificateChain().GetParent().GetCertificate() == nil {
+				t.Errorf("certificate chain has no parent")
+			}
+		case e.GetTrustBundle() != nil:
+			if !roots.AppendCertsFromPEM(e.GetTrustBundle().GetCertificate().GetCertificate()) {
+				t.Fatalf("trust bundle holds no certificate")
+			}
+		case e.GetCertificateRevocationListBundle() != nil:
+			block, _ := pem.Decode(e.GetCertificateRevocationListBundle().GetCertificateRevocationLists()[0].GetCertificateRevocationList())
+			var err error
+			if crl, err = x509.ParseRevocationList(block.Bytes); err != nil {
+				t.Fatalf("ParseRevocationList() err = %v", err)
+			}
+		}
+	}
+	if leaf == nil {
+		t.Fatalf("upload request holds no certificate chain")
+	}
+	return leaf, roots, crl
+}
+
+// issue returns the upload request of the device, and commits its certificate.
+func issue(t *testing.T, i *Issuer, id Identity) (*cpb.UploadRequest, Record, error) {
+	t.Helper()
+	req, r, err := i.UploadRequest(id)
+	if err != nil {
+		return nil, Record{}, err
+	}
+	if err := i.Commit(r.SerialNumber); err != nil {
+		t.Fatalf("Commit(%v) err = %v", r.SerialNumber, err)
+	}
+	return req, r, nil
+}
+
+func TestIssuer(t *testing.T) {
+	dir := t.TempDir()
+	i, err := Open(dir, DefaultValidity, DefaultRenewBefore)
+	if err != nil {
+		t.Fatalf("Open() err = %v", err)
+	}
+	id := Identity{Manufacturer: "Cisco", Serial: "123A", Hostname: "core1.example.com", IPs: []net.IP{net.ParseIP("192.0.2.10")}}
+	// Certificates are recorded once committed, and only the last one minted can be.
+	_, stale, err := i.UploadRequest(id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if got := len(i.Records()); got != 0 {
+		t.Errorf("Records() before Commit() has %d certificates, want 0", got)
+	}
+	req, r, err := issue(t, i, id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if err := i.Commit(stale.SerialNumber); !errors.Is(err, ErrUnknownCertificate) {
+		t.Errorf("Commit() of a replaced pending certificate err = %v, want %v", err, ErrUnknownCertificate)
+	}
+	leaf, roots, crl := parseUpload(t, req)
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "core1.example.com"}); err != nil {
+		t.Errorf("Verify() of the device certificate err = %v", err)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, DNSName: "192.0.2.10"}); err != nil {
+		t.Errorf("Verify() of the management IP err = %v", err)
+	}
+	if crl != nil {
+		t.Errorf("upload request holds a CRL before any revocation")
+	}
+	want := Record{
+		SerialNumber: leaf.SerialNumber.Text(16),
+		Manufacturer: "Cisco",
+		Serial:       "123A",
+		CommonName:   "core1.example.com",
+		DNSNames:     []string{"core1.example.com"},
+		IPAddresses:  []string{"192.0.2.10"},
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+	}
+	if diff := cmp.Diff(want, r); diff != "" {
+		t.Errorf("UploadRequest() record differs (-want +got):\n%s", diff)
+	}
+
+	if _, again, err := issue(t, i, id); err != nil || again.SerialNumber != r.SerialNumber {
+		t.Errorf("UploadRequest() again = %v, %v, want the certificate %v", again.SerialNumber, err, r.SerialNumber)
+	}
+
+	// A new management IP gets a new certificate, which supersedes the first.
+	id.IPs = []net.IP{net.ParseIP("192.0.2.11")}
+	_, moved, err := issue(t, i, id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if moved.SerialNumber == r.SerialNumber {
+		t.Errorf("UploadRequest() after an IP change reused the certificate %v", r.SerialNumber)
+	}
+	records := i.Records()
+	if len(records) != 2 || records[0].SupersededBy != moved.SerialNumber || !records[1].Current() {
+		t.Errorf("Records() = %+v, want the first certificate superseded by %v", records, moved.SerialNumber)
+	}
+
+	// Certificates are renewed when they expire within the renewal time.
+	i.now = func() time.Time { return time.Now().Add(DefaultValidity - DefaultRenewBefore + time.Hour) }
+	if !i.RenewalDue(moved) {
+		t.Errorf("RenewalDue(%v) = false, want true", moved.SerialNumber)
+	}
+	_, renewed, err := issue(t, i, id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if renewed.SerialNumber == moved.SerialNumber {
+		t.Errorf("UploadRequest() of a certificate due for renewal reused it")
+	}
+	i.now = time.Now
+
+	if _, err := i.Revoke(renewed.SerialNumber); err != nil {
+		t.Fatalf("Revoke() err = %v", err)
+	}
+	if _, err := i.Revoke("abc"); !errors.Is(err, ErrUnknownCertificate) {
+		t.Errorf("Revoke() of an unknown certificate err = %v, want %v", err, ErrUnknownCertificate)
+	}
+	req, reissued, err := issue(t, i, id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if reissued.SerialNumber == renewed.SerialNumber {
+		t.Errorf("UploadRequest() reused the revoked certificate")
+	}
+	_, _, crl = parseUpload(t, req)
+	if crl == nil || len(crl.RevokedCertificateEntries) != 1 || crl.RevokedCertificateEntries[0].SerialNumber.Text(16) != renewed.SerialNumber {
+		t.Errorf("CRL = %+v, want the revoked certificate %v", crl, renewed.SerialNumber)
+	}
+
+	// The CA and records survive a restart, but keys do not.
+	i, err = Open(dir, DefaultValidity, DefaultRenewBefore)
+	if err != nil {
+		t.Fatalf("Open() again err = %v", err)
+	}
+	if got := len(i.Records()); got != 4 {
+		t.Errorf("Records() after a restart has %d certificates, want 4", got)
+	}
+	req, restarted, err := issue(t, i, id)
+	if err != nil {
+		t.Fatalf("UploadRequest() err = %v", err)
+	}
+	if restarted.SerialNumber == reissued.SerialNumber {
+		t.Errorf("UploadRequest() after a restart reused the certificate of an unknown key")
+	}
+	if leaf, _, _ := parseUpload(t, req); leaf.CheckSignatureFrom(i.ca) != nil {
+		t.Errorf("certificate after a restart is not signed by the CA")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := Open(t.TempDir(), time.Hour, 2*time.Hour); err == nil || !strings.Contains(err.Error(), "invalid renewal time") {
+		t.Errorf("Open() with a renewal time over the validity err = %v, want invalid renewal time", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, caCertFile), []byte("not a certificate"), 0o644); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	if _, err := Open(dir, DefaultValidity, DefaultRenewBefore); err == nil {
+		t.Errorf("Open() with a CA certificate and no key succeeded")
+	}
+
+	// An existing CA is used as is.
+	other := t.TempDir()
+	if _, err := Open(other, DefaultValidity, DefaultRenewBefore); err != nil {
+		t.Fatalf("Open() err = %v", err)
+	}
+	for _, f := range []string{caCertFile, caKeyFile} {
+		data, err := os.ReadFile(filepath.Join(other, f))
+		if err != nil {
+			t.Fatalf("ReadFile() err = %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), data, 0o600); err != nil {
+			t.Fatalf("WriteFile() err = %v", err)
+		}
+	}
+	i, err := Open(dir, DefaultValidity, DefaultRenewBefore)
+	if err != nil {
+		t.Fatalf("Open() of an existing CA err = %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(other, caCertFile))
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v", err)
+	}
+	if got := i.TrustBundle(); string(got) != string(want) {
+		t.Errorf("TrustBundle() = %s, want the existing CA %s", got, want)
+	}
+}
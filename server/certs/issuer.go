@@ -0,0 +1,473 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/openconfig/bootz/common/certserial"
+	"github.com/openconfig/bootz/server/atomicfile"
+
+	log "github.com/golang/glog"
+	cpb "github.com/openconfig/gnsi/certz"
+)
+
+const (
+	// DefaultValidity is the default validity of issued certificates.
+	DefaultValidity = 90 * 24 * time.Hour
+	// DefaultRenewBefore is the default time before their expiry certificates are renewed.
+	DefaultRenewBefore = 30 * 24 * time.Hour
+	// crlValidity is the time after which devices expect a new CRL.
+	crlValidity = 7 * 24 * time.Hour
+)
+
+// Files of the issuer directory.
+const (
+	caCertFile  = "ca.pem"
+	caKeyFile   = "ca.key"
+	recordsFile = "issued.json"
+)
+
+// ErrUnknownCertificate is returned when revoking a certificate the issuer did not issue.
+var ErrUnknownCertificate = errors.New("unknown certificate")
+
+// Identity is the identity a device certificate is issued for.
+type Identity struct {
+	Manufacturer string
+	// Serial identifies the device the certificate is issued to: a control card, or a fixed form
+	// factor chassis.
+	Serial string
+	// Hostname is the DNS name of the device, if known. It is the common name of the certificate,
+	// which defaults to the serial.
+	Hostname string
+	// IPs are the management addresses of the device.
+	IPs []net.IP
+}
+
+// Record is a certificate issued to a device.
+type Record struct {
+	// SerialNumber is the hex encoded serial number of the certificate.
+	SerialNumber string    `json:"serial_number"`
+	Manufacturer string    `json:"manufacturer"`
+	Serial       string    `json:"serial"`
+	CommonName   string    `json:"common_name"`
+	DNSNames     []string  `json:"dns_names,omitempty"`
+	IPAddresses  []string  `json:"ip_addresses,omitempty"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	// Revoked is when the certificate was revoked, zero if it was not.
+	Revoked time.Time `json:"revoked"`
+	// SupersededBy is the serial number of the certificate which replaced this one, if any.
+	SupersededBy string `json:"superseded_by,omitempty"`
+}
+
+// Current reports whether the certificate is the latest certificate of its device and was not
+// revoked.
+func (r Record) Current() bool {
+	return r.Revoked.IsZero() && r.SupersededBy == ""
+}
+
+// issued is a certificate issued since the issuer was opened, along with its key. Keys are never
+// written to disk, so devices get new certificates after a restart.
+type issued struct {
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// device identifies a device by its manufacturer and serial.
+type device struct {
+	manufacturer string
+	serial       string
+}
+
+// pending is a certificate minted for a device but not committed yet, along with the current
+// certificates it supersedes once committed.
+type pending struct {
+	record  *Record
+	cert    *issued
+	current []*Record
+}
+
+// Issuer is an issuing CA which mints device certificates, and records them in its directory. It
+// is safe for concurrent use.
+type Issuer struct {
+	dir         string
+	validity    time.Duration
+	renewBefore time.Duration
+	ca          *x509.Certificate
+	caPEM       []byte
+	key         crypto.Signer
+	// now returns the current time, and is replaced by tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	records []*Record
+	// issued holds the certificates and keys issued since the issuer was opened, by serial number.
+	issued map[string]*issued
+	// pending holds the last certificate minted for each device and not committed yet, by
+	// manufacturer and serial of the device.
+	pending map[device]*pending
+	// crlPEM is the last CRL, generated again when a certificate is revoked or it gets stale.
+	crlPEM    []byte
+	crlUpdate time.Time
+}
+
+// Open opens the issuer in dir, creating the directory and a CA if needed. The CA is read from
+// ca.pem and ca.key, which may hold an existing CA. Certificates are valid for the provided
+// duration, and renewed when they expire within renewBefore.
+func Open(dir string, validity, renewBefore time.Duration) (*Issuer, error) {
+	if validity <= 0 {
+		return nil, fmt.Errorf("invalid certificate validity %v", validity)
+	}
+	if renewBefore < 0 || renewBefore >= validity {
+		return nil, fmt.Errorf("invalid renewal time %v, want less than the validity %v", renewBefore, validity)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	i := &Issuer{
+		dir:         dir,
+		validity:    validity,
+		renewBefore: renewBefore,
+		now:         time.Now,
+		issued:      map[string]*issued{},
+		pending:     map[device]*pending{},
+	}
+	ca, key, err := loadOrCreateCA(filepath.Join(dir, caCertFile), filepath.Join(dir, caKeyFile), i.now())
+	if err != nil {
+		return nil, err
+	}
+	i.ca, i.key = ca, key
+	i.caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	data, err := os.ReadFile(filepath.Join(dir, recordsFile))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &i.records); err != nil {
+			return nil, fmt.Errorf("invalid issued certificates %s: %v", filepath.Join(dir, recordsFile), err)
+		}
+	}
+	return i, nil
+}
+
+// TrustBundle returns the PEM encoded certificate of the CA.
+func (i *Issuer) TrustBundle() []byte {
+	return i.caPEM
+}
+
+// RenewalDue reports whether a current certificate expires within the renewal time.
+func (i *Issuer) RenewalDue(r Record) bool {
+	return r.Current() && !i.now().Add(i.renewBefore).Before(r.NotAfter)
+}
+
+// Records returns the certificates issued to devices, oldest first.
+func (i *Issuer) Records() []Record {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := make([]Record, 0, len(i.records))
+	for _, r := range i.records {
+		out = append(out, *r)
+	}
+	return out
+}
+
+// Revoke revokes the certificate with the provided hex serial number. The next CRL lists it, and
+// its device gets a new certificate on its next bootstrap. Revoking a revoked certificate does
+// nothing.
+func (i *Issuer) Revoke(serialNumber string) (Record, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, r := range i.records {
+		if r.SerialNumber != serialNumber {
+			continue
+		}
+		if !r.Revoked.IsZero() {
+			return *r, nil
+		}
+		r.Revoked = i.now()
+		if err := i.writeRecords(); err != nil {
+			r.Revoked = time.Time{}
+			return Record{}, err
+		}
+		i.crlPEM = nil
+		delete(i.issued, serialNumber)
+		log.Infof("Revoked certificate %v of %v/%v", r.SerialNumber, r.Manufacturer, r.Serial)
+		return *r, nil
+	}
+	return Record{}, fmt.Errorf("%w %s", ErrUnknownCertificate, serialNumber)
+}
+
+// UploadRequest returns the certz upload request of the device: its certificate and key chained
+// to the CA, the trust bundle, and the CRL if certificates were revoked. The current certificate
+// of the device is reused, unless it must be renewed, its identity changed, or its key is not
+// known since the issuer was reopened. A new certificate is only recorded once committed.
+func (i *Issuer) UploadRequest(id Identity) (*cpb.UploadRequest, Record, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	r, cert, err := i.certificate(id)
+	if err != nil {
+		return nil, Record{}, err
+	}
+	req := &cpb.UploadRequest{Entities: []*cpb.Entity{{
+		Version:   r.SerialNumber,
+		CreatedOn: uint64(r.NotBefore.Unix()),
+		Entity: &cpb.Entity_CertificateChain{CertificateChain: &cpb.CertificateChain{
+			Certificate: pemCertificate(cert.certPEM, cert.keyPEM),
+			Parent:      &cpb.CertificateChain{Certificate: pemCertificate(i.caPEM, nil)},
+		}},
+	}, {
+		Version:   digest(i.ca.Raw),
+		CreatedOn: uint64(i.ca.NotBefore.Unix()),
+		Entity: &cpb.Entity_TrustBundle{TrustBundle: &cpb.CertificateChain{
+			Certificate: pemCertificate(i.caPEM, nil),
+		}},
+	}}}
+	crl, err := i.crl()
+	if err != nil {
+		return nil, Record{}, err
+	}
+	if crl != nil {
+		id := digest(crl)
+		req.Entities = append(req.Entities, &cpb.Entity{
+			Version:   id,
+			CreatedOn: uint64(i.crlUpdate.Unix()),
+			Entity: &cpb.Entity_CertificateRevocationListBundle{CertificateRevocationListBundle: &cpb.CertificateRevocationListBundle{
+				CertificateRevocationLists: []*cpb.CertificateRevocationList{{
+					Type:                      cpb.CertificateType_CERTIFICATE_TYPE_X509,
+					Encoding:                  cpb.CertificateEncoding_CERTIFICATE_ENCODING_PEM,
+					CertificateRevocationList: crl,
+					Id:                        id,
+				}},
+			}},
+		})
+	}
+	return req, r, nil
+}
+
+// Commit records the certificate with the provided hex serial number, returned by UploadRequest
+// once it is delivered to its device, and supersedes the previous certificates of the device.
+// Committing a recorded certificate does nothing.
+func (i *Issuer) Commit(serialNumber string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for dev, p := range i.pending {
+		if p.record.SerialNumber != serialNumber {
+			continue
+		}
+		for _, old := range p.current {
+			old.SupersededBy = serialNumber
+		}
+		i.records = append(i.records, p.record)
+		if err := i.writeRecords(); err != nil {
+			i.records = i.records[:len(i.records)-1]
+			for _, old := range p.current {
+				old.SupersededBy = ""
+			}
+			return err
+		}
+		for _, old := range p.current {
+			delete(i.issued, old.SerialNumber)
+		}
+		i.issued[serialNumber] = p.cert
+		delete(i.pending, dev)
+		log.Infof("Issued certificate %v to %v/%v, valid until %v", serialNumber, dev.manufacturer, dev.serial, p.record.NotAfter)
+		return nil
+	}
+	for _, r := range i.records {
+		if r.SerialNumber == serialNumber {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %s", ErrUnknownCertificate, serialNumber)
+}
+
+// certificate returns the current certificate of the device, or mints a new one if needed. A new
+// certificate is pending until it is committed, so that a request which fails after minting it
+// neither records it nor supersedes the certificate the device holds. The caller must hold i.mu.
+func (i *Issuer) certificate(id Identity) (Record, *issued, error) {
+	dnsNames, ips := sans(id)
+	var current []*Record
+	for _, r := range i.records {
+		if r.Manufacturer == id.Manufacturer && r.Serial == id.Serial && r.Current() {
+			current = append(current, r)
+		}
+	}
+	if n := len(current); n > 0 {
+		r := current[n-1]
+		cert, ok := i.issued[r.SerialNumber]
+		if ok && !i.RenewalDue(*r) && r.CommonName == commonName(id) && equal(r.DNSNames, dnsNames) && equal(r.IPAddresses, ips) {
+			return *r, cert, nil
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Record{}, nil, err
+	}
+	serial, err := certserial.Random()
+	if err != nil {
+		return Record{}, nil, err
+	}
+	// Certificates hold times to the second.
+	now := i.now().UTC().Truncate(time.Second)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName(id), SerialNumber: id.Serial},
+		DNSNames:     dnsNames,
+		IPAddresses:  id.IPs,
+		// Devices with a slightly late clock accept the certificate.
+		NotBefore:   now.Add(-5 * time.Minute),
+		NotAfter:    now.Add(i.validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if tmpl.NotAfter.After(i.ca.NotAfter) {
+		tmpl.NotAfter = i.ca.NotAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, i.ca, key.Public(), i.key)
+	if err != nil {
+		return Record{}, nil, err
+	}
+	keyPEM, err := marshalPrivateKey(key)
+	if err != nil {
+		return Record{}, nil, err
+	}
+	r := &Record{
+		SerialNumber: serial.Text(16),
+		Manufacturer: id.Manufacturer,
+		Serial:       id.Serial,
+		CommonName:   tmpl.Subject.CommonName,
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+		NotBefore:    tmpl.NotBefore,
+		NotAfter:     tmpl.NotAfter,
+	}
+	cert := &issued{certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM: keyPEM}
+	// Only the last certificate minted for a device can be committed.
+	i.pending[device{manufacturer: id.Manufacturer, serial: id.Serial}] = &pending{record: r, cert: cert, current: current}
+	return *r, cert, nil
+}
+
+// crl returns the PEM encoded CRL of the revoked certificates, or nil if none was revoked. The
+// caller must hold i.mu.
+func (i *Issuer) crl() ([]byte, error) {
+	now := i.now()
+	if i.crlPEM != nil && now.Before(i.crlUpdate.Add(crlValidity/2)) {
+		return i.crlPEM, nil
+	}
+	var entries []x509.RevocationListEntry
+	for _, r := range i.records {
+		if r.Revoked.IsZero() {
+			continue
+		}
+		serial, ok := new(big.Int).SetString(r.SerialNumber, 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid serial number %q", r.SerialNumber)
+		}
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: serial, RevocationTime: r.Revoked})
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		// The number of the CRL increases with its time, also across restarts.
+		Number:     big.NewInt(now.Unix()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(crlValidity),
+	}, i.ca, i.key)
+	if err != nil {
+		return nil, err
+	}
+	i.crlPEM = pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+	i.crlUpdate = now
+	return i.crlPEM, nil
+}
+
+// writeRecords writes the records to the issuer directory. The caller must hold i.mu.
+func (i *Issuer) writeRecords() error {
+	data, err := json.MarshalIndent(i.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filepath.Join(i.dir, recordsFile), append(data, '\n'), 0o644)
+}
+
+// commonName returns the common name of the certificate of a device.
+func commonName(id Identity) string {
+	if id.Hostname != "" {
+		return id.Hostname
+	}
+	return id.Serial
+}
+
+// sans returns the DNS names and the IP addresses of the certificate of a device.
+func sans(id Identity) ([]string, []string) {
+	var dnsNames, ips []string
+	if id.Hostname != "" {
+		dnsNames = []string{id.Hostname}
+	}
+	for _, ip := range id.IPs {
+		ips = append(ips, ip.String())
+	}
+	return dnsNames, ips
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for n := range a {
+		if a[n] != b[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// pemCertificate returns a certz certificate of the PEM encoded certificate and optional key.
+func pemCertificate(certPEM, keyPEM []byte) *cpb.Certificate {
+	return &cpb.Certificate{
+		Type:        cpb.CertificateType_CERTIFICATE_TYPE_X509,
+		Encoding:    cpb.CertificateEncoding_CERTIFICATE_ENCODING_PEM,
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	}
+}
+
+// digest returns the first hex digits of the SHA-256 of data, as the version of certz entities.
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
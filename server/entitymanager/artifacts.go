@@ -0,0 +1,299 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+	apb "github.com/openconfig/gnsi/authz"
+)
+
+// Kinds of config artifacts.
+const (
+	ArtifactOCConfig     = "oc_config"
+	ArtifactVendorConfig = "vendor_config"
+	ArtifactAuthz        = "authz"
+	ArtifactCredentials  = "credentials"
+)
+
+// ArtifactVersion identifies the version of a config artifact served to a device.
+type ArtifactVersion struct {
+	Kind string
+	Path string
+	// Digest is the hex encoded SHA-256 of the content of the artifact.
+	Digest string
+}
+
+// artifact is a loaded and validated config artifact.
+type artifact struct {
+	version ArtifactVersion
+	// modTime and size identify the version of the file the artifact was loaded from.
+	modTime time.Time
+	size    int64
+	// authz is the parsed upload request of authz artifacts.
+	authz *apb.UploadRequest
+	// credentials is the parsed credentialz requests of credentials artifacts.
+	credentials *bpb.Credentials
+	err         error
+}
+
+type artifactKey struct {
+	kind string
+	path string
+}
+
+// artifactCache loads config artifacts from disk once, validates and fingerprints them, and reloads
+// them when their files change. Artifact content is stored by digest, so that identical files
+// share their content. The zero value is an empty cache.
+type artifactCache struct {
+	mu      sync.Mutex
+	entries map[artifactKey]*artifact
+	// blobs maps artifact digests to their content.
+	blobs map[string][]byte
+}
+
+// get returns the artifact of the kind at path, loading it if the file changed since it was last
+// loaded. Invalid artifacts are cached too, so that their error is returned until the file changes.
+func (c *artifactCache) get(kind, path string) (*artifact, []byte, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "Error opening file %s: %v", path, err)
+	}
+	key := artifactKey{kind: kind, path: path}
+	c.mu.Lock()
+	a, ok := c.entries[key]
+	if ok && a.modTime.Equal(fi.ModTime()) && a.size == fi.Size() {
+		data := c.blobs[a.version.Digest]
+		c.mu.Unlock()
+		return a, data, a.err
+	}
+	c.mu.Unlock()
+
+	a, data := loadArtifact(kind, path, fi)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[artifactKey]*artifact{}
+		c.blobs = map[string][]byte{}
+	}
+	if old, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		c.release(old.version.Digest)
+	}
+	c.entries[key] = a
+	if a.err == nil {
+		c.blobs[a.version.Digest] = data
+		log.Infof("Loaded %v artifact %v with digest %v", kind, path, a.version.Digest)
+	} else {
+		log.Warningf("Invalid %v artifact %v: %v", kind, path, a.err)
+	}
+	return a, data, a.err
+}
+
+// release drops the content with the digest if no artifact refers to it. The caller must hold c.mu.
+func (c *artifactCache) release(digest string) {
+	for _, a := range c.entries {
+		if a.err == nil && a.version.Digest == digest {
+			return
+		}
+	}
+	delete(c.blobs, digest)
+}
+
+// loadArtifact reads and validates the artifact of the kind at path.
+func loadArtifact(kind, path string, fi os.FileInfo) (*artifact, []byte) {
+	a := &artifact{
+		version: ArtifactVersion{Kind: kind, Path: path},
+		modTime: fi.ModTime(),
+		size:    fi.Size(),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		a.err = status.Errorf(codes.Internal, "Error opening file %s: %v", path, err)
+		return a, nil
+	}
+	switch kind {
+	case ArtifactOCConfig:
+		if !json.Valid(data) {
+			a.err = status.Errorf(codes.Internal, "File %s config is not a valid json", path)
+			return a, nil
+		}
+	case ArtifactAuthz:
+		req := &apb.UploadRequest{}
+		if err := prototext.Unmarshal(data, req); err != nil {
+			a.err = status.Errorf(codes.Internal, "File %s config is not a valid authz Upload Request: %v", path, err)
+			return a, nil
+		}
+		var t any
+		if err := json.Unmarshal([]byte(req.GetPolicy()), &t); err != nil {
+			a.err = status.Errorf(codes.Internal, "Provided authz policy is not a valid json: %v", err)
+			return a, nil
+		}
+		a.authz = req
+	case ArtifactCredentials:
+		creds := &bpb.Credentials{}
+		if err := prototext.Unmarshal(data, creds); err != nil {
+			a.err = status.Errorf(codes.Internal, "File %s config is not valid credentials: %v", path, err)
+			return a, nil
+		}
+		if err := validateCredentials(creds); err != nil {
+			a.err = status.Errorf(codes.Internal, "File %s config holds invalid credentials: %v", path, err)
+			return a, nil
+		}
+		a.credentials = creds
+	}
+	sum := sha256.Sum256(data)
+	a.version.Digest = hex.EncodeToString(sum[:])
+	return a, data
+}
+
+// bootConfig renders the boot config of a device from the cached artifacts, and returns the
+// versions of the artifacts it was rendered from.
+func (c *artifactCache) bootConfig(conf *epb.BootConfig) (*bpb.BootConfig, []ArtifactVersion, error) {
+	bootConfig := &bpb.BootConfig{}
+	var versions []ArtifactVersion
+	if conf.GetOcConfigFile() != "" {
+		a, data, err := c.get(ArtifactOCConfig, conf.GetOcConfigFile())
+		if err != nil {
+			return nil, nil, err
+		}
+		bootConfig.OcConfig = data
+		versions = append(versions, a.version)
+	}
+	if conf.GetVendorConfigFile() != "" {
+		a, data, err := c.get(ArtifactVendorConfig, conf.GetVendorConfigFile())
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "Could not populate vendor config %v", err)
+		}
+		bootConfig.VendorConfig = data
+		versions = append(versions, a.version)
+	}
+	// TODO: validate OC and CLI may be added. However, this may prevent negative testing
+	bootConfig.Metadata = conf.GetMetadata()
+	bootConfig.BootloaderConfig = conf.GetBootloaderConfig()
+	return bootConfig, versions, nil
+}
+
+// authz returns a copy of the cached authz upload request at path.
+func (c *artifactCache) authz(path string) (*apb.UploadRequest, ArtifactVersion, error) {
+	a, _, err := c.get(ArtifactAuthz, path)
+	if err != nil {
+		return nil, ArtifactVersion{}, err
+	}
+	return proto.Clone(a.authz).(*apb.UploadRequest), a.version, nil
+}
+
+// credentials returns a copy of the cached credentials at path.
+func (c *artifactCache) credentials(path string) (*bpb.Credentials, ArtifactVersion, error) {
+	a, _, err := c.get(ArtifactCredentials, path)
+	if err != nil {
+		return nil, ArtifactVersion{}, err
+	}
+	return proto.Clone(a.credentials).(*bpb.Credentials), a.version, nil
+}
+
+// artifactSet is a set of artifacts referenced by configs.
+type artifactSet map[artifactKey]bool
+
+// addBoot adds the config files of the boot config.
+func (set artifactSet) addBoot(conf *epb.BootConfig) {
+	if f := conf.GetOcConfigFile(); f != "" {
+		set[artifactKey{kind: ArtifactOCConfig, path: f}] = true
+	}
+	if f := conf.GetVendorConfigFile(); f != "" {
+		set[artifactKey{kind: ArtifactVendorConfig, path: f}] = true
+	}
+}
+
+// addGNSI adds the authz policy and credentials files of the gNSI config.
+func (set artifactSet) addGNSI(conf *epb.GNSIConfig) {
+	if f := conf.GetAuthzUploadFile(); f != "" {
+		set[artifactKey{kind: ArtifactAuthz, path: f}] = true
+	}
+	if f := conf.GetCredentialsFile(); f != "" {
+		set[artifactKey{kind: ArtifactCredentials, path: f}] = true
+	}
+}
+
+// addChassis adds the artifacts of the chassis and its control cards.
+func (set artifactSet) addChassis(ch *epb.Chassis) {
+	set.addBoot(ch.GetConfig().GetBootConfig())
+	set.addGNSI(ch.GetConfig().GetGnsiConfig())
+	for _, cc := range ch.GetControllerCards() {
+		set.addBoot(cc.GetBootConfig())
+		set.addGNSI(cc.GetGnsiConfig())
+	}
+}
+
+// sorted returns the artifacts ordered by path and kind.
+func (set artifactSet) sorted() []artifactKey {
+	keys := make([]artifactKey, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+		return keys[i].kind < keys[j].kind
+	})
+	return keys
+}
+
+// preloadArtifacts reads every boot config and gNSI file referenced by the chassis, profiles and
+// global config into the artifact cache, which logs the files that fail to load.
+func (m *InMemoryEntityManager) preloadArtifacts() {
+	set := artifactSet{}
+	set.addGNSI(m.defaults.GetGnsiGlobalConfig())
+	for _, ch := range m.chassisInventory {
+		set.addChassis(ch)
+	}
+	for _, p := range m.profiles {
+		set.addBoot(p.GetConfig().GetBootConfig())
+		set.addGNSI(p.GetConfig().GetGnsiConfig())
+	}
+	for _, k := range set.sorted() {
+		// Errors are logged by the cache and returned to the devices requesting the artifact.
+		m.artifacts.get(k.kind, k.path)
+	}
+}
+
+// checkArtifacts loads the artifacts referenced by the chassis, and returns the first which is
+// invalid.
+func (m *InMemoryEntityManager) checkArtifacts(ch *epb.Chassis) error {
+	set := artifactSet{}
+	set.addChassis(ch)
+	for _, k := range set.sorted() {
+		if _, _, err := m.artifacts.get(k.kind, k.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/openconfig/bootz/server/certs"
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	log "github.com/golang/glog"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+	cpb "github.com/openconfig/gnsi/certz"
+)
+
+// WithCertIssuer issues a TLS certificate to each control card, or fixed chassis, when it fetches
+// bootstrap data.
+func WithCertIssuer(i *certs.Issuer) Option {
+	return func(m *InMemoryEntityManager) {
+		m.issuer = i
+	}
+}
+
+// CertIssuer returns the issuer of the device certificates, or nil if certificates are not issued.
+func (m *InMemoryEntityManager) CertIssuer() *certs.Issuer {
+	return m.issuer
+}
+
+// populateCertificates returns the certz upload request of the control card with the provided
+// serial, with its certificate, or nil if certificates are not issued. Certificates are only
+// issued to requests authenticated by an IDevID or a nonce, since any caller may name a serial.
+func (m *InMemoryEntityManager) populateCertificates(ctx context.Context, ch *epb.Chassis, serial string) (*cpb.UploadRequest, error) {
+	if m.issuer == nil {
+		return nil, nil
+	}
+	if !service.Authenticated(ctx) {
+		log.Infof("Not issuing a certificate to %v, whose request is not authenticated", serial)
+		return nil, nil
+	}
+	id, err := certIdentity(ch, serial)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "unable to issue the certificate of %v: %v", serial, err)
+	}
+	req, _, err := m.issuer.UploadRequest(id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to issue the certificate of %v: %v", serial, err)
+	}
+	return req, nil
+}
+
+// Commit records the certificates issued in the bootstrap data response, once it is signed, so
+// that they supersede the certificates the devices held.
+func (m *InMemoryEntityManager) Commit(ctx context.Context, lookup *service.EntityLookup, resp *bpb.GetBootstrapDataResponse) error {
+	if m.issuer == nil {
+		return nil
+	}
+	for _, r := range resp.GetSignedResponse().GetResponses() {
+		for _, e := range r.GetCertificates().GetEntities() {
+			if e.GetCertificateChain() == nil {
+				continue
+			}
+			if err := m.issuer.Commit(e.GetVersion()); err != nil {
+				return status.Errorf(codes.Internal, "unable to record the certificate of %v: %v", r.GetSerialNum(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// certIdentity returns the identity of the certificate of the control card with the provided
+// serial: the name of the chassis as hostname, and the management IP of the card, or else of the
+// chassis.
+func certIdentity(ch *epb.Chassis, serial string) (certs.Identity, error) {
+	id := certs.Identity{Manufacturer: ch.GetManufacturer(), Serial: serial, Hostname: ch.GetName()}
+	addr := ch.GetDhcpConfig().GetIpAddress()
+	for _, cc := range ch.GetControllerCards() {
+		if cc.GetSerialNumber() == serial && cc.GetDhcpConfig().GetIpAddress() != "" {
+			addr = cc.GetDhcpConfig().GetIpAddress()
+		}
+	}
+	if addr == "" {
+		return id, nil
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		var err error
+		if ip, _, err = net.ParseCIDR(addr); err != nil {
+			return id, fmt.Errorf("invalid management IP %q", addr)
+		}
+	}
+	id.IPs = []net.IP{ip}
+	return id, nil
+}
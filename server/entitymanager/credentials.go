@@ -0,0 +1,475 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/bootz/server/secrets"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+	cpb "github.com/openconfig/gnsi/credentialz"
+)
+
+// populateCredentials returns the credentialz requests of the chassis: the credentials of its gnsi
+// config, or else of the global config, followed by the requests built from the users of the
+// global and chassis configs. It also returns the version of the artifact the credentials were
+// loaded from, if they were not provided inline.
+func (m *InMemoryEntityManager) populateCredentials(ch *epb.Chassis) (*bpb.Credentials, []ArtifactVersion, error) {
+	creds, versions, err := m.baseCredentials(ch)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err := m.chassisUsers(ch)
+	if err == nil {
+		err = m.appendUserCredentials(creds, ch, users)
+	}
+	if err != nil {
+		return nil, nil, status.Errorf(codes.FailedPrecondition, "invalid users of chassis %s: %v", escrowName(ch), err)
+	}
+	return creds, versions, nil
+}
+
+// baseCredentials returns a copy of the credentials of the chassis config, or else of the global
+// config, provided inline or as a file.
+func (m *InMemoryEntityManager) baseCredentials(ch *epb.Chassis) (*bpb.Credentials, []ArtifactVersion, error) {
+	for _, conf := range []*epb.GNSIConfig{ch.GetConfig().GetGnsiConfig(), m.defaults.GetGnsiGlobalConfig()} {
+		if conf.GetCredentials() != nil {
+			return proto.Clone(conf.GetCredentials()).(*bpb.Credentials), nil, nil
+		}
+		if f := conf.GetCredentialsFile(); f != "" {
+			creds, version, err := m.artifacts.credentials(f)
+			if err != nil {
+				return nil, nil, err
+			}
+			return creds, []ArtifactVersion{version}, nil
+		}
+	}
+	return &bpb.Credentials{}, nil, nil
+}
+
+// chassisUsers returns the users to install on the chassis: the users of the roles and the users
+// of the global config, then of the chassis config. Users are merged by account, in the order
+// their accounts are first declared.
+func (m *InMemoryEntityManager) chassisUsers(ch *epb.Chassis) ([]*epb.User, error) {
+	roles := map[string]*epb.Role{}
+	for _, r := range m.defaults.GetRoles() {
+		roles[r.GetName()] = r
+	}
+	var users []*epb.User
+	byAccount := map[string]*epb.User{}
+	add := func(u *epb.User) {
+		if dst, ok := byAccount[u.GetAccount()]; ok {
+			mergeUser(dst, u)
+			return
+		}
+		u = proto.Clone(u).(*epb.User)
+		byAccount[u.GetAccount()] = u
+		users = append(users, u)
+	}
+	for _, conf := range []*epb.GNSIConfig{m.defaults.GetGnsiGlobalConfig(), ch.GetConfig().GetGnsiConfig()} {
+		for _, name := range conf.GetRoles() {
+			r, ok := roles[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown role %q", name)
+			}
+			for _, u := range r.GetUsers() {
+				add(u)
+			}
+		}
+		for _, u := range conf.GetUsers() {
+			add(u)
+		}
+	}
+	return users, nil
+}
+
+// mergeUser adds the keys and principals of src to dst. The password of src, if set, replaces the
+// password of dst.
+func mergeUser(dst, src *epb.User) {
+	dst.AuthorizedKeys = appendMissing(dst.AuthorizedKeys, src.GetAuthorizedKeys())
+	dst.AuthorizedPrincipals = appendMissing(dst.AuthorizedPrincipals, src.GetAuthorizedPrincipals())
+	if src.GetPassword() != nil {
+		dst.Password = proto.Clone(src).(*epb.User).Password
+	}
+}
+
+// appendMissing appends the strings of src which are not in dst.
+func appendMissing(dst, src []string) []string {
+	for _, s := range src {
+		found := false
+		for _, d := range dst {
+			if d == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
+
+// appendUserCredentials appends the authorized keys, authorized users and password requests of the
+// users to the credentials. Requests without accounts are omitted.
+func (m *InMemoryEntityManager) appendUserCredentials(creds *bpb.Credentials, ch *epb.Chassis, users []*epb.User) error {
+	keys := &cpb.AuthorizedKeysRequest{}
+	policies := &cpb.AuthorizedUsersRequest{}
+	passwords := &cpb.PasswordRequest{}
+	for _, u := range users {
+		version, err := userVersion(u)
+		if err != nil {
+			return err
+		}
+		if len(u.GetAuthorizedKeys()) > 0 {
+			ac := &cpb.AccountCredentials{Account: u.GetAccount(), Version: version}
+			for i, k := range u.GetAuthorizedKeys() {
+				ak, err := authorizedKey(k)
+				if err != nil {
+					return fmt.Errorf("account %q: authorized key %d: %v", u.GetAccount(), i+1, err)
+				}
+				ac.AuthorizedKeys = append(ac.AuthorizedKeys, ak)
+			}
+			keys.Credentials = append(keys.Credentials, ac)
+		}
+		if len(u.GetAuthorizedPrincipals()) > 0 {
+			principals := &cpb.UserPolicy_SshAuthorizedPrincipals{}
+			for _, p := range u.GetAuthorizedPrincipals() {
+				principals.AuthorizedPrincipals = append(principals.AuthorizedPrincipals, &cpb.UserPolicy_SshAuthorizedPrincipal{AuthorizedUser: p})
+			}
+			policies.Policies = append(policies.Policies, &cpb.UserPolicy{
+				Account:              u.GetAccount(),
+				AuthorizedPrincipals: principals,
+				Version:              version,
+			})
+		}
+		if u.GetPassword() != nil {
+			hash, err := m.passwords.userHash(ch, u)
+			if err != nil {
+				return fmt.Errorf("account %q: %v", u.GetAccount(), err)
+			}
+			hashType, err := cryptHashType(hash)
+			if err != nil {
+				return fmt.Errorf("account %q: %v", u.GetAccount(), err)
+			}
+			passwords.Accounts = append(passwords.Accounts, &cpb.PasswordRequest_Account{
+				Account: u.GetAccount(),
+				Password: &cpb.PasswordRequest_Password{
+					Value: &cpb.PasswordRequest_Password_CryptoHash{
+						CryptoHash: &cpb.PasswordRequest_CryptoHash{HashType: hashType, HashValue: hash},
+					},
+				},
+				Version: version,
+			})
+		}
+	}
+	if len(keys.GetCredentials()) > 0 {
+		creds.Credentials = append(creds.Credentials, keys)
+	}
+	if len(policies.GetPolicies()) > 0 {
+		creds.Users = append(creds.Users, policies)
+	}
+	if len(passwords.GetAccounts()) > 0 {
+		creds.Passwords = append(creds.Passwords, passwords)
+	}
+	return nil
+}
+
+// userVersion returns the version of the credentials of a user, a digest of its declaration, so
+// that devices report a new version when the declaration changes.
+func userVersion(u *epb.User) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(u)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// userHash returns the crypt hash of the password of the user of the chassis. Passwords of files
+// and secrets are hashed with SHA-512 crypt, once per chassis.
+func (h *passwordHasher) userHash(ch *epb.Chassis, u *epb.User) (string, error) {
+	key := passwordKey{chassis: escrowName(ch)}
+	var password func() (string, error)
+	switch pw := u.GetPassword().(type) {
+	case *epb.User_PasswordHash:
+		if _, err := cryptHashType(pw.PasswordHash); err != nil {
+			return "", err
+		}
+		return pw.PasswordHash, nil
+	case *epb.User_PasswordFile:
+		key.password = "user " + u.GetAccount() + " file " + pw.PasswordFile
+		password = func() (string, error) { return readPasswordFile(pw.PasswordFile) }
+	case *epb.User_PasswordSecret:
+		key.password = "user " + u.GetAccount() + " secret " + pw.PasswordSecret
+		password = func() (string, error) { return h.secret(pw.PasswordSecret) }
+	default:
+		return "", errors.New("no password source")
+	}
+	return h.hash(key, secrets.SHA512Crypt, password)
+}
+
+// cryptHashType returns the credentialz type of a crypt password hash.
+func cryptHashType(hash string) (cpb.PasswordRequest_CryptoHash_HashType, error) {
+	switch {
+	case strings.HasPrefix(hash, "$6$"):
+		return cpb.PasswordRequest_CryptoHash_HASH_TYPE_CRYPT_SHA_2_512, nil
+	case strings.HasPrefix(hash, "$1$"):
+		return cpb.PasswordRequest_CryptoHash_HASH_TYPE_CRYPT_MD5, nil
+	}
+	return cpb.PasswordRequest_CryptoHash_HASH_TYPE_UNSPECIFIED, errors.New("unsupported password hash, want SHA-512 crypt ($6$) or MD5 crypt ($1$)")
+}
+
+// authorizedKey parses an SSH public key in authorized_keys format, along with its options, into
+// its credentialz form. The comment of the key becomes its description.
+func authorizedKey(line string) (*cpb.AccountCredentials_AuthorizedKey, error) {
+	pub, comment, options, rest, err := ssh.ParseAuthorizedKey([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, errors.New("more than one key")
+	}
+	keyType, err := sshKeyType(pub)
+	if err != nil {
+		return nil, err
+	}
+	ak := &cpb.AccountCredentials_AuthorizedKey{
+		AuthorizedKey: bytes.TrimSpace(ssh.MarshalAuthorizedKey(pub)),
+		KeyType:       keyType,
+		Description:   comment,
+	}
+	for _, o := range options {
+		opt, err := keyOption(o)
+		if err != nil {
+			return nil, err
+		}
+		ak.Options = append(ak.Options, opt)
+	}
+	return ak, nil
+}
+
+// sshKeyType returns the credentialz type of an SSH public key. Only the key types credentialz
+// supports are accepted.
+func sshKeyType(pub ssh.PublicKey) (cpb.KeyType, error) {
+	switch pub.Type() {
+	case ssh.KeyAlgoED25519:
+		return cpb.KeyType_KEY_TYPE_ED25519, nil
+	case ssh.KeyAlgoECDSA256:
+		return cpb.KeyType_KEY_TYPE_ECDSA_P_256, nil
+	case ssh.KeyAlgoECDSA521:
+		return cpb.KeyType_KEY_TYPE_ECDSA_P_521, nil
+	case ssh.KeyAlgoRSA:
+		if ck, ok := pub.(ssh.CryptoPublicKey); ok {
+			if rk, ok := ck.CryptoPublicKey().(*rsa.PublicKey); ok {
+				switch bits := rk.N.BitLen(); bits {
+				case 2048:
+					return cpb.KeyType_KEY_TYPE_RSA_2048, nil
+				case 4096:
+					return cpb.KeyType_KEY_TYPE_RSA_4096, nil
+				default:
+					return cpb.KeyType_KEY_TYPE_UNSPECIFIED, fmt.Errorf("unsupported RSA key size %d, want 2048 or 4096", bits)
+				}
+			}
+		}
+	}
+	return cpb.KeyType_KEY_TYPE_UNSPECIFIED, fmt.Errorf("unsupported key type %s", pub.Type())
+}
+
+// keyOption parses an authorized_keys option, e.g. from="10.0.0.0/8" or no-pty. Devices reject
+// unknown options, so only the standard options of credentialz are accepted.
+func keyOption(o string) (*cpb.Option, error) {
+	name, value, _ := strings.Cut(o, "=")
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+	}
+	id := cpb.Option_StandardOption_value["STANDARD_OPTION_"+strings.ToUpper(strings.ReplaceAll(name, "-", "_"))]
+	if id == 0 {
+		return nil, fmt.Errorf("unknown key option %q", name)
+	}
+	return &cpb.Option{Key: &cpb.Option_Id{Id: cpb.Option_StandardOption(id)}, Value: value}, nil
+}
+
+// validateUser checks the account, keys, principals and password hash of a user.
+func validateUser(u *epb.User) error {
+	if u.GetAccount() == "" {
+		return errors.New("missing account")
+	}
+	for i, k := range u.GetAuthorizedKeys() {
+		if _, err := authorizedKey(k); err != nil {
+			return fmt.Errorf("account %q: authorized key %d: %v", u.GetAccount(), i+1, err)
+		}
+	}
+	for _, p := range u.GetAuthorizedPrincipals() {
+		if p == "" {
+			return fmt.Errorf("account %q: empty authorized principal", u.GetAccount())
+		}
+	}
+	if hash, ok := u.GetPassword().(*epb.User_PasswordHash); ok {
+		if _, err := cryptHashType(hash.PasswordHash); err != nil {
+			return fmt.Errorf("account %q: %v", u.GetAccount(), err)
+		}
+	}
+	return nil
+}
+
+// validateCredentials checks the keys and password hashes of credentialz requests provided as is.
+func validateCredentials(creds *bpb.Credentials) error {
+	for _, req := range creds.GetCredentials() {
+		for _, ac := range req.GetCredentials() {
+			for i, k := range ac.GetAuthorizedKeys() {
+				pub, _, _, _, err := ssh.ParseAuthorizedKey(k.GetAuthorizedKey())
+				if err != nil {
+					return fmt.Errorf("account %q: authorized key %d: %v", ac.GetAccount(), i+1, err)
+				}
+				keyType, err := sshKeyType(pub)
+				if err != nil {
+					return fmt.Errorf("account %q: authorized key %d: %v", ac.GetAccount(), i+1, err)
+				}
+				if k.GetKeyType() != cpb.KeyType_KEY_TYPE_UNSPECIFIED && k.GetKeyType() != keyType {
+					return fmt.Errorf("account %q: authorized key %d: key type %v does not match the %v key", ac.GetAccount(), i+1, k.GetKeyType(), keyType)
+				}
+			}
+		}
+	}
+	for _, req := range creds.GetPasswords() {
+		for _, a := range req.GetAccounts() {
+			hash := a.GetPassword().GetCryptoHash()
+			if hash == nil {
+				continue
+			}
+			hashType, err := cryptHashType(hash.GetHashValue())
+			if err != nil {
+				return fmt.Errorf("account %q: %v", a.GetAccount(), err)
+			}
+			if hash.GetHashType() != hashType {
+				return fmt.Errorf("account %q: hash type %v does not match the hash", a.GetAccount(), hash.GetHashType())
+			}
+		}
+	}
+	return nil
+}
+
+// credentialsChecker validates the users and credentials of gNSI configs, and the roles they
+// refer to.
+type credentialsChecker struct {
+	m     *InMemoryEntityManager
+	roles map[string]bool
+}
+
+// newCredentialsChecker validates the roles of the global options and returns a checker of the
+// configs referring to them.
+func (m *InMemoryEntityManager) newCredentialsChecker() (*credentialsChecker, error) {
+	c := &credentialsChecker{m: m, roles: map[string]bool{}}
+	for _, r := range m.defaults.GetRoles() {
+		if r.GetName() == "" {
+			return nil, errors.New("invalid role: missing name")
+		}
+		if c.roles[r.GetName()] {
+			return nil, fmt.Errorf("invalid role %q: duplicate name", r.GetName())
+		}
+		c.roles[r.GetName()] = true
+		for _, u := range r.GetUsers() {
+			if err := c.user(u); err != nil {
+				return nil, fmt.Errorf("invalid user of role %q: %v", r.GetName(), err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// user checks the user and that its password file or secret can be read.
+func (c *credentialsChecker) user(u *epb.User) error {
+	if err := validateUser(u); err != nil {
+		return err
+	}
+	var err error
+	switch pw := u.GetPassword().(type) {
+	case *epb.User_PasswordFile:
+		_, err = readPasswordFile(pw.PasswordFile)
+	case *epb.User_PasswordSecret:
+		_, err = c.m.passwords.secret(pw.PasswordSecret)
+	}
+	if err != nil {
+		return fmt.Errorf("account %q: %v", u.GetAccount(), err)
+	}
+	return nil
+}
+
+// config checks the roles, users and inline credentials of the gNSI config of owner.
+func (c *credentialsChecker) config(owner string, conf *epb.GNSIConfig) error {
+	for _, name := range conf.GetRoles() {
+		if !c.roles[name] {
+			return fmt.Errorf("invalid users of %s: unknown role %q", owner, name)
+		}
+	}
+	for _, u := range conf.GetUsers() {
+		if err := c.user(u); err != nil {
+			return fmt.Errorf("invalid user of %s: %v", owner, err)
+		}
+	}
+	if err := validateCredentials(conf.GetCredentials()); err != nil {
+		return fmt.Errorf("invalid credentials of %s: %v", owner, err)
+	}
+	return nil
+}
+
+// chassis checks the gNSI configs of the chassis and its control cards.
+func (c *credentialsChecker) chassis(ch *epb.Chassis) error {
+	if err := c.config("chassis "+escrowName(ch), ch.GetConfig().GetGnsiConfig()); err != nil {
+		return err
+	}
+	for _, cc := range ch.GetControllerCards() {
+		if err := c.config("control card "+cc.GetSerialNumber(), cc.GetGnsiConfig()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadCredentials checks the roles, users and inline credentials of the global config, chassis
+// and profiles, and fails the load on the first invalid key, unknown role or missing password.
+func (m *InMemoryEntityManager) preloadCredentials() error {
+	c, err := m.newCredentialsChecker()
+	if err != nil {
+		return err
+	}
+	if err := c.config("the global config", m.defaults.GetGnsiGlobalConfig()); err != nil {
+		return err
+	}
+	for _, ch := range m.chassisInventory {
+		if err := c.chassis(ch); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.profiles {
+		if err := c.config(fmt.Sprintf("profile %q", p.GetName()), p.GetConfig().GetGnsiConfig()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
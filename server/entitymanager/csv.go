@@ -0,0 +1,332 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// ColumnMapping maps the columns of a CSV file to the fields of inventory chassis.
+type ColumnMapping struct {
+	fields []mappedField
+}
+
+// mappedField is a chassis field set from the columns of a CSV row.
+type mappedField struct {
+	path  string
+	steps []pathStep
+	// value is the name of the column the field is set from, or a template referencing columns
+	// as {column}.
+	value    string
+	template bool
+}
+
+// pathStep is a field of a path. index is the element of a repeated field the path continues
+// with, and -1 for other fields.
+type pathStep struct {
+	fd    protoreflect.FieldDescriptor
+	index int
+}
+
+var columnRef = regexp.MustCompile(`\{([^{}]+)\}`)
+
+var chassisDescriptor = (&epb.Chassis{}).ProtoReflect().Descriptor()
+
+// ParseColumnMapping parses a column mapping spec: a list of <field>=<column> entries separated
+// by commas or newlines, e.g. "serial_number=Serial,dhcp_config.hardware_address=MAC". Fields are
+// paths of chassis fields, with the index of the element in repeated fields, such as
+// controller_cards.0.serial_number. Columns are matched regardless of case, and a column may also
+// be a template which references columns as {column}, e.g. name={Site}-{Serial}. Blank lines and
+// lines starting with # are ignored.
+func ParseColumnMapping(spec string) (*ColumnMapping, error) {
+	cm := &ColumnMapping{}
+	for _, line := range strings.Split(spec, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			path, value, ok := strings.Cut(entry, "=")
+			path, value = strings.TrimSpace(path), strings.TrimSpace(value)
+			if !ok || path == "" || value == "" {
+				return nil, fmt.Errorf("invalid column mapping %q, want <field>=<column>", entry)
+			}
+			if err := cm.add(path, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(cm.fields) == 0 {
+		return nil, errors.New("empty column mapping")
+	}
+	return cm, nil
+}
+
+// add maps the field at path to a column or template.
+func (cm *ColumnMapping) add(path, value string) error {
+	for _, f := range cm.fields {
+		if f.path == path {
+			return fmt.Errorf("field %s is mapped more than once", path)
+		}
+	}
+	steps, err := resolveFieldPath(chassisDescriptor, path)
+	if err != nil {
+		return err
+	}
+	cm.fields = append(cm.fields, mappedField{
+		path:     path,
+		steps:    steps,
+		value:    value,
+		template: columnRef.MatchString(value),
+	})
+	return nil
+}
+
+// columns returns the columns the field is set from.
+func (f *mappedField) columns() []string {
+	if !f.template {
+		return []string{f.value}
+	}
+	var cols []string
+	for _, m := range columnRef.FindAllStringSubmatch(f.value, -1) {
+		cols = append(cols, m[1])
+	}
+	return cols
+}
+
+// resolveFieldPath resolves a dotted path of scalar fields from a message.
+func resolveFieldPath(md protoreflect.MessageDescriptor, path string) ([]pathStep, error) {
+	var steps []pathStep
+	parts := strings.Split(path, ".")
+	for i := 0; i < len(parts); i++ {
+		if md == nil {
+			return nil, fmt.Errorf("invalid field %s: %s is not a message", path, strings.Join(parts[:i], "."))
+		}
+		fd := md.Fields().ByName(protoreflect.Name(parts[i]))
+		if fd == nil {
+			return nil, fmt.Errorf("invalid field %s: %s has no field %q", path, md.Name(), parts[i])
+		}
+		step := pathStep{fd: fd, index: -1}
+		switch {
+		case fd.IsMap():
+			return nil, fmt.Errorf("invalid field %s: map fields are not supported", path)
+		case fd.IsList() && fd.Message() == nil:
+			return nil, fmt.Errorf("invalid field %s: repeated scalar fields are not supported", path)
+		case fd.IsList():
+			if i+1 == len(parts) {
+				return nil, fmt.Errorf("invalid field %s: missing the index of %s", path, fd.Name())
+			}
+			i++
+			n, err := strconv.Atoi(parts[i])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid field %s: invalid index %q of %s", path, parts[i], fd.Name())
+			}
+			step.index = n
+		}
+		steps = append(steps, step)
+		md = fd.Message()
+	}
+	if md != nil {
+		return nil, fmt.Errorf("invalid field %s: %s is a message", path, steps[len(steps)-1].fd.Name())
+	}
+	return steps, nil
+}
+
+// setFieldPath sets the scalar field at the end of the steps from its text value, creating the
+// messages and list elements leading to it.
+func setFieldPath(m protoreflect.Message, steps []pathStep, value string) error {
+	for _, s := range steps[:len(steps)-1] {
+		if s.index < 0 {
+			m = m.Mutable(s.fd).Message()
+			continue
+		}
+		l := m.Mutable(s.fd).List()
+		for l.Len() <= s.index {
+			l.Append(l.NewElement())
+		}
+		m = l.Get(s.index).Message()
+	}
+	fd := steps[len(steps)-1].fd
+	v, err := scalarValue(fd, value)
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// scalarValue parses the text of a scalar field. Enums are given by name, regardless of case and
+// of the prefix of their values, or by number.
+func scalarValue(fd protoreflect.FieldDescriptor, s string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s)), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s %q: not a bool", fd.Name(), s)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.EnumKind:
+		if ev := enumValue(fd.Enum(), s); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil && fd.Enum().Values().ByNumber(protoreflect.EnumNumber(n)) != nil {
+			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("invalid %s %q: not a value of %s", fd.Name(), s, fd.Enum().Name())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s %q: %v", fd.Name(), s, err)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s %q: %v", fd.Name(), s, err)
+		}
+		return protoreflect.ValueOfInt64(n), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s %q: %v", fd.Name(), s, err)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, fmt.Errorf("invalid %s %q: %v", fd.Name(), s, err)
+		}
+		return protoreflect.ValueOfUint64(n), nil
+	}
+	return protoreflect.Value{}, fmt.Errorf("fields of kind %v are not supported", fd.Kind())
+}
+
+// enumValue returns the value of the enum with the provided name, which may omit the prefix shared
+// by the values, e.g. secure for BOOT_MODE_SECURE.
+func enumValue(ed protoreflect.EnumDescriptor, name string) protoreflect.EnumValueDescriptor {
+	name = strings.ToUpper(name)
+	if ev := ed.Values().ByName(protoreflect.Name(name)); ev != nil {
+		return ev
+	}
+	var found protoreflect.EnumValueDescriptor
+	for i := 0; i < ed.Values().Len(); i++ {
+		ev := ed.Values().Get(i)
+		if strings.HasSuffix(string(ev.Name()), "_"+name) {
+			if found != nil {
+				return nil
+			}
+			found = ev
+		}
+	}
+	return found
+}
+
+// ImportCSV reads chassis from a CSV file whose first row is a header. Each row is a chassis,
+// with the fields set from the columns of the mapping. Empty values leave fields unset. If the
+// mapping is nil, the columns of the header are the paths of the fields they set.
+func ImportCSV(r io.Reader, mapping *ColumnMapping) ([]*epb.Chassis, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty CSV file")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if mapping == nil {
+		mapping = &ColumnMapping{}
+		for _, h := range header {
+			h = strings.TrimSpace(h)
+			if err := mapping.add(h, h); err != nil {
+				return nil, fmt.Errorf("invalid CSV header: %v", err)
+			}
+		}
+	}
+	for _, f := range mapping.fields {
+		for _, c := range f.columns() {
+			if _, ok := columns[strings.ToLower(c)]; !ok {
+				return nil, fmt.Errorf("column %q of field %s is not in the CSV header", c, f.path)
+			}
+		}
+	}
+
+	var chassis []*epb.Chassis
+	// lines maps the serials of the chassis and control cards read so far to their line.
+	lines := map[string]int{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		cell := func(c string) string {
+			return strings.TrimSpace(row[columns[strings.ToLower(c)]])
+		}
+		ch := &epb.Chassis{}
+		for _, f := range mapping.fields {
+			value := f.value
+			if f.template {
+				value = columnRef.ReplaceAllStringFunc(value, func(ref string) string {
+					return cell(ref[1 : len(ref)-1])
+				})
+			} else {
+				value = cell(value)
+			}
+			if value == "" {
+				continue
+			}
+			if err := setFieldPath(ch.ProtoReflect(), f.steps, value); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		keys := chassisKeys(ch)
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("line %d: neither a chassis nor a control card serial is set", line)
+		}
+		for _, k := range keys {
+			if other, ok := lines[k]; ok {
+				return nil, fmt.Errorf("line %d: serial %s is already imported on line %d", line, k, other)
+			}
+			lines[k] = line
+		}
+		chassis = append(chassis, ch)
+	}
+	return chassis, nil
+}
@@ -0,0 +1,375 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// DefaultMaxDiscovered is the default number of discovered devices kept. Beyond it, the pending
+// device which was seen least recently is forgotten.
+const DefaultMaxDiscovered = 1024
+
+// DiscoveredDevice is a device which requested bootstrap data but is not in the inventory.
+type DiscoveredDevice struct {
+	Manufacturer string
+	SerialNumber string
+	PartNumber   string
+	ControlCards []*bpb.ControlCard
+	// ActiveSerial is the serial of the control card which sent the last request.
+	ActiveSerial string
+	// Peer is the address the last request was sent from.
+	Peer string
+	// NoncePresent is whether the last request carried a nonce.
+	NoncePresent bool
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	Requests     uint64
+	// Rejected is whether an operator rejected the device, with RejectReason.
+	Rejected     bool
+	RejectReason string
+}
+
+// matches reports whether the device has the serial, as chassis or control card serial.
+func (d *DiscoveredDevice) matches(lookup *service.EntityLookup) bool {
+	if lookup.Manufacturer != "" && lookup.Manufacturer != d.Manufacturer {
+		return false
+	}
+	if d.SerialNumber != "" && d.SerialNumber == lookup.SerialNumber {
+		return true
+	}
+	for _, cc := range d.ControlCards {
+		if cc.GetSerialNumber() == lookup.SerialNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DiscoveredDevice) clone() *DiscoveredDevice {
+	c := *d
+	c.ControlCards = nil
+	for _, cc := range d.ControlCards {
+		c.ControlCards = append(c.ControlCards, proto.Clone(cc).(*bpb.ControlCard))
+	}
+	return &c
+}
+
+// WithMaxDiscovered sets the number of discovered devices kept.
+func WithMaxDiscovered(n int) Option {
+	return func(m *InMemoryEntityManager) {
+		m.maxDiscovered = n
+	}
+}
+
+// Discover records a bootstrap request from a device which is not in the inventory, so that an
+// operator can approve it. If the device matches a fallback profile and was not rejected, it is
+// instead added to the inventory as a provisional chassis, and Discover returns true.
+func (m *InMemoryEntityManager) Discover(ctx context.Context, req *bpb.GetBootstrapDataRequest) bool {
+	desc := req.GetChassisDescriptor()
+	lookup := &service.EntityLookup{Manufacturer: desc.GetManufacturer(), SerialNumber: desc.GetSerialNumber()}
+	active := req.GetControlCardState().GetSerialNumber()
+	if lookup.SerialNumber == "" {
+		lookup.SerialNumber = active
+	}
+	if lookup.SerialNumber == "" {
+		return false
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := m.findDiscovered(lookup)
+	if d == nil || !d.Rejected {
+		if p := m.fallbackProfile(desc); p != nil {
+			if d != nil {
+				m.removeDiscovered(d)
+			}
+			m.addProvisional(ctx, p, desc, now)
+			return true
+		}
+	}
+	if d == nil {
+		d = &DiscoveredDevice{
+			Manufacturer: desc.GetManufacturer(),
+			SerialNumber: desc.GetSerialNumber(),
+			FirstSeen:    now,
+		}
+		m.evictDiscovered()
+		m.discovered = append(m.discovered, d)
+		log.Infof("Discovered %v chassis %v which is not in the inventory", d.Manufacturer, lookup.SerialNumber)
+	}
+	d.PartNumber = desc.GetPartNumber()
+	d.ControlCards = nil
+	for _, cc := range desc.GetControlCards() {
+		d.ControlCards = append(d.ControlCards, proto.Clone(cc).(*bpb.ControlCard))
+	}
+	d.ActiveSerial = active
+	d.Peer = sourceAddress(ctx)
+	d.NoncePresent = req.GetNonce() != ""
+	d.LastSeen = now
+	d.Requests++
+	return false
+}
+
+// findDiscovered returns the discovered device at the lookup, or nil. The caller must hold m.mu.
+func (m *InMemoryEntityManager) findDiscovered(lookup *service.EntityLookup) *DiscoveredDevice {
+	for _, d := range m.discovered {
+		if d.matches(lookup) {
+			return d
+		}
+	}
+	return nil
+}
+
+// evictDiscovered makes room for a discovered device by forgetting the pending device seen least
+// recently. Rejected devices are kept so that they are not listed as pending again. The caller must hold m.mu.
+func (m *InMemoryEntityManager) evictDiscovered() {
+	if m.maxDiscovered <= 0 || len(m.discovered) < m.maxDiscovered {
+		return
+	}
+	oldest := -1
+	for i, d := range m.discovered {
+		if d.Rejected {
+			continue
+		}
+		if oldest < 0 || d.LastSeen.Before(m.discovered[oldest].LastSeen) {
+			oldest = i
+		}
+	}
+	if oldest < 0 {
+		oldest = 0
+	}
+	m.discovered = append(m.discovered[:oldest], m.discovered[oldest+1:]...)
+}
+
+// inInventory reports whether the chassis or one of the control cards of the device is in the inventory.
+// The caller must hold m.mu.
+func (m *InMemoryEntityManager) inInventory(d *DiscoveredDevice) bool {
+	if d.SerialNumber != "" && first(m.index.bySerial[d.SerialNumber], d.Manufacturer, false) != nil {
+		return true
+	}
+	for _, cc := range d.ControlCards {
+		if first(m.index.byControlCard[cc.GetSerialNumber()], d.Manufacturer, false) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDiscovered forgets the discovered device. The caller must hold m.mu.
+func (m *InMemoryEntityManager) removeDiscovered(d *DiscoveredDevice) {
+	for i, o := range m.discovered {
+		if o == d {
+			m.discovered = append(m.discovered[:i], m.discovered[i+1:]...)
+			return
+		}
+	}
+}
+
+// Discovered returns a copy of the discovered devices, in the order they were first seen.
+func (m *InMemoryEntityManager) Discovered() []*DiscoveredDevice {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*DiscoveredDevice
+	for _, d := range m.discovered {
+		out = append(out, d.clone())
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].FirstSeen.Before(out[j].FirstSeen) })
+	return out
+}
+
+// GetProfile returns a copy of the profile with the provided name.
+func (m *InMemoryEntityManager) GetProfile(name string) (*epb.Profile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range m.profiles {
+		if p.GetName() == name {
+			return proto.Clone(p).(*epb.Profile), nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "profile %q not found", name)
+}
+
+// ApproveDevice adds the discovered device at the lookup to the inventory, configured from the named
+// profile, and returns the new chassis. The chassis is named after its serial unless a name is provided.
+// Ownership vouchers are generated for the serials of the device which have none, so that it can
+// bootstrap in secure mode.
+func (m *InMemoryEntityManager) ApproveDevice(lookup *service.EntityLookup, profile string, name string) (*epb.Chassis, error) {
+	if profile == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "a profile is required to approve a device")
+	}
+	p, err := m.GetProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	ovs, err := m.generateOVs(m.approvalSerials(lookup))
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := m.findDiscovered(lookup)
+	if d == nil {
+		ch, err := m.confirmProvisional(lookup, p, name)
+		if err != nil {
+			return nil, err
+		}
+		m.updateOVs(ovs)
+		return ch, nil
+	}
+	if m.inInventory(d) {
+		m.removeDiscovered(d)
+		return nil, status.Errorf(codes.AlreadyExists, "chassis %v is already in the inventory", lookup.SerialNumber)
+	}
+	ch := chassisFromProfile(p, d.Manufacturer, d.SerialNumber, d.PartNumber, d.ControlCards)
+	if name != "" {
+		ch.Name = name
+	}
+	m.addChassis(ch)
+	m.updateOVs(ovs)
+	m.removeDiscovered(d)
+	log.Infof("Approved %v chassis %v into the inventory with profile %v", ch.GetManufacturer(), ch.GetName(), profile)
+	return proto.Clone(ch).(*epb.Chassis), nil
+}
+
+// approvalSerials returns the serials of the discovered or provisional device at the lookup which
+// have no ownership voucher: those of its control cards, or the chassis serial of a fixed chassis.
+func (m *InMemoryEntityManager) approvalSerials(lookup *service.EntityLookup) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var serial string
+	var cards []string
+	if d := m.findDiscovered(lookup); d != nil {
+		serial = d.SerialNumber
+		for _, cc := range d.ControlCards {
+			cards = append(cards, cc.GetSerialNumber())
+		}
+	} else if ch := m.provisionalChassis(lookup); ch != nil {
+		serial = ch.GetSerialNumber()
+		for _, cc := range ch.GetControllerCards() {
+			cards = append(cards, cc.GetSerialNumber())
+		}
+	}
+	return m.missingOVs(serial, cards)
+}
+
+// missingOVs returns the serials of the control cards, or else the chassis serial, which have no
+// ownership voucher. The caller must hold m.mu.
+func (m *InMemoryEntityManager) missingOVs(serial string, cards []string) []string {
+	if m.secArtifacts == nil {
+		return nil
+	}
+	if len(cards) == 0 && serial != "" {
+		cards = []string{serial}
+	}
+	var missing []string
+	for _, s := range cards {
+		if _, ok := m.secArtifacts.OV[s]; !ok {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// generateOVs returns ownership vouchers for the serials from the OV generator, if set. It must be
+// called without holding m.mu.
+func (m *InMemoryEntityManager) generateOVs(serials []string) (service.OVList, error) {
+	if m.ovGenerator == nil || len(serials) == 0 {
+		return nil, nil
+	}
+	ovs := service.OVList{}
+	for _, s := range serials {
+		ov, err := m.ovGenerator(s)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to generate ownership voucher for %v: %v", s, err)
+		}
+		ovs[s] = ov
+	}
+	return ovs, nil
+}
+
+// provisionalChassis returns the provisional chassis at the lookup, or nil. The caller must hold m.mu.
+func (m *InMemoryEntityManager) provisionalChassis(lookup *service.EntityLookup) *epb.Chassis {
+	candidates := append(append([]*epb.Chassis{}, m.index.bySerial[lookup.SerialNumber]...), m.index.byControlCard[lookup.SerialNumber]...)
+	for _, ch := range candidates {
+		if ch.GetProvisional() && chassisMatches(ch, lookup) {
+			return ch
+		}
+	}
+	return nil
+}
+
+// confirmProvisional reconfigures the provisional chassis at the lookup from the profile, and clears
+// its provisional mark. The caller must hold m.mu.
+func (m *InMemoryEntityManager) confirmProvisional(lookup *service.EntityLookup, p *epb.Profile, name string) (*epb.Chassis, error) {
+	if ch := m.provisionalChassis(lookup); ch != nil {
+		var cards []*bpb.ControlCard
+		for _, cc := range ch.GetControllerCards() {
+			cards = append(cards, &bpb.ControlCard{SerialNumber: cc.GetSerialNumber(), PartNumber: cc.GetPartNumber()})
+		}
+		confirmed := chassisFromProfile(p, ch.GetManufacturer(), ch.GetSerialNumber(), ch.GetPartNumber(), cards)
+		if name != "" {
+			confirmed.Name = name
+		}
+		m.replaceChassis(m.position(ch), confirmed)
+		delete(m.provisioned, provisionalKey(ch))
+		log.Infof("Confirmed provisional %v chassis %v with profile %v", ch.GetManufacturer(), confirmed.GetName(), p.GetName())
+		return proto.Clone(confirmed).(*epb.Chassis), nil
+	}
+	return nil, status.Errorf(codes.NotFound, "no discovered or provisional device with serial %v", lookup.SerialNumber)
+}
+
+// chassisMatches reports whether the chassis or one of its control cards has the serial of the lookup.
+func chassisMatches(ch *epb.Chassis, lookup *service.EntityLookup) bool {
+	if lookup.Manufacturer != "" && lookup.Manufacturer != ch.GetManufacturer() {
+		return false
+	}
+	if ch.GetSerialNumber() != "" && ch.GetSerialNumber() == lookup.SerialNumber {
+		return true
+	}
+	for _, cc := range ch.GetControllerCards() {
+		if cc.GetSerialNumber() == lookup.SerialNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// RejectDevice marks the discovered device at the lookup as rejected and returns it.
+func (m *InMemoryEntityManager) RejectDevice(lookup *service.EntityLookup, reason string) (*DiscoveredDevice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d := m.findDiscovered(lookup)
+	if d == nil {
+		return nil, status.Errorf(codes.NotFound, "no discovered device with serial %v", lookup.SerialNumber)
+	}
+	d.Rejected = true
+	d.RejectReason = reason
+	log.Infof("Rejected discovered %v chassis %v: %v", d.Manufacturer, lookup.SerialNumber, reason)
+	return d.clone(), nil
+}
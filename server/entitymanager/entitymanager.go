@@ -18,39 +18,75 @@ package entitymanager
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"os"
 	"sync"
+	"time"
 
 	ownercertificate "github.com/openconfig/bootz/common/owner_certificate"
 	"github.com/openconfig/bootz/common/signature"
+	"github.com/openconfig/bootz/server/certs"
+	"github.com/openconfig/bootz/server/events"
 	"github.com/openconfig/bootz/server/service"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	log "github.com/golang/glog"
 
 	bpb "github.com/openconfig/bootz/proto/bootz"
+	admpb "github.com/openconfig/bootz/server/admin/proto/admin"
 	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
 	apb "github.com/openconfig/gnsi/authz"
 )
 
+// DefaultHistorySize is the default number of status transitions kept per chassis and per control card.
+const DefaultHistorySize = 32
+
 // InMemoryEntityManager provides a simple in memory handler
 // for Entities.
 type InMemoryEntityManager struct {
+	// mu guards the inventory and the artifacts. Every bootstrap request takes it, so files are
+	// read and vouchers generated without holding it, and the results applied under it.
 	mu sync.Mutex
 	// inventory represents an organization's inventory of owned chassis.
 	chassisInventory []*epb.Chassis
+	// indexes the chassis of the inventory by their identifiers
+	index *inventoryIndex
+	// the version and snapshots of the inventory
+	snapshots snapshotState
 	// represents the current status of known control cards
 	controlCardStatuses map[string]bpb.ControlCardState_ControlCardStatus
+	// represents the last status reported by each chassis
+	chassisStatuses map[service.EntityLookup]*ChassisStatus
+	// holds the audited decisions about chassis which were never served nor reported a status
+	audits map[service.EntityLookup]*ChassisStatus
+	// the maximum number of status transitions kept per chassis and per control card
+	historySize int
+	// receives an event for each status report, if set
+	events *events.Bus
+	// profiles to configure approved devices from
+	profiles []*epb.Profile
+	// devices which requested bootstrap data but are not in the inventory
+	discovered    []*DiscoveredDevice
+	maxDiscovered int
+	// when each provisional chassis was provisioned, and how long it is kept without approval
+	provisioned    map[service.EntityLookup]time.Time
+	provisionalTTL time.Duration
+	// generates the OVs of replacement control cards, if set
+	ovGenerator OVGenerator
 	// stores the default config such as security artifacts dir.
 	defaults *epb.Options
 	// security artifacts  (OVs, OC and PDC).
 	// TODO: handle mutlti-vendor case
 	secArtifacts *service.SecurityArtifacts
+	// caches the config artifacts referenced by the inventory
+	artifacts artifactCache
+	// hashes the bootloader passwords of the inventory
+	passwords passwordHasher
+	// issues the TLS certificates of devices, if set
+	issuer *certs.Issuer
 }
 
 // ResolveChassis returns an entity based on the provided lookup.
@@ -60,96 +96,63 @@ func (m *InMemoryEntityManager) ResolveChassis(ctx context.Context, lookup *serv
 	if err != nil {
 		return nil, err
 	}
-	return &service.ChassisEntity{BootMode: chassis.GetBootMode()}, nil
+	// The chassis holding the active control card is the one the device is part of, whatever
+	// serial it describes.
+	if ccSerial != "" {
+		m.mu.Lock()
+		if owner := first(m.index.byControlCard[ccSerial], lookup.Manufacturer, false); owner != nil {
+			chassis = owner
+		}
+		m.mu.Unlock()
+	}
+	return &service.ChassisEntity{
+		BootMode:     chassis.GetBootMode(),
+		Manufacturer: chassis.GetManufacturer(),
+		SerialNumber: chassis.GetSerialNumber(),
+	}, nil
 }
 
 func (m *InMemoryEntityManager) lookupChassis(lookup *service.EntityLookup, ccSerial string) (*epb.Chassis, error) {
+	return m.findChassis(lookup, ccSerial, false)
+}
+
+// findChassis returns the chassis with the serial of the lookup or holding the control card with
+// the provided serial. An empty manufacturer matches any chassis if anyManufacturer is set.
+func (m *InMemoryEntityManager) findChassis(lookup *service.EntityLookup, ccSerial string, anyManufacturer bool) (*epb.Chassis, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	// Search for the chassis first.
-	for _, chassis := range m.chassisInventory {
-		if chassis.GetManufacturer() == lookup.Manufacturer {
-			if chassis.GetSerialNumber() == lookup.SerialNumber {
-				return chassis, nil
-			}
-			// While we're here, try looking up by control card.
-			if ccSerial != "" {
-				for _, c := range chassis.GetControllerCards() {
-					if c.GetSerialNumber() == ccSerial {
-						return chassis, nil
-					}
-				}
-			}
+	if ch := first(m.index.bySerial[lookup.SerialNumber], lookup.Manufacturer, anyManufacturer); ch != nil {
+		return ch, nil
+	}
+	if ccSerial != "" {
+		if ch := first(m.index.byControlCard[ccSerial], lookup.Manufacturer, anyManufacturer); ch != nil {
+			return ch, nil
 		}
 	}
 	return nil, status.Errorf(codes.NotFound, "could not find chassis for lookup %+v and control card %v", lookup, ccSerial)
 }
 
-func readOCConfig(path string) ([]byte, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Error opening file %s: %v", path, err)
-	}
-	var v any
-	err = json.Unmarshal(data, &v)
-	if err != nil {
-		fmt.Printf("unmarshal error %v", err)
-	}
-	if !json.Valid(data) {
-		return nil, status.Errorf(codes.Internal, "File %s config is not a valid json", path)
-	}
-	return data, nil
-}
-
-func (m *InMemoryEntityManager) populateAuthzConfig(ch *epb.Chassis) (*apb.UploadRequest, error) {
+// populateAuthzConfig returns the authz upload request of the chassis, and the version of the
+// artifact it was loaded from if it was not provided inline.
+func (m *InMemoryEntityManager) populateAuthzConfig(ch *epb.Chassis) (*apb.UploadRequest, []ArtifactVersion, error) {
 	gnsiConf := ch.GetConfig().GetGnsiConfig()
 	gnsiAuthzReq := gnsiConf.GetAuthzUpload()
 	gnsiAuthzReqFile := gnsiConf.GetAuthzUploadFile()
 	if gnsiAuthzReqFile == "" {
-		gnsiAuthzReqFile = m.defaults.GnsiGlobalConfig.GetAuthzUploadFile()
+		gnsiAuthzReqFile = m.defaults.GetGnsiGlobalConfig().GetAuthzUploadFile()
 	}
 	if gnsiAuthzReq.GetPolicy() != "" && gnsiAuthzReq.GetVersion() != "" {
-		return gnsiAuthzReq, nil
+		return gnsiAuthzReq, nil, nil
 	}
 	if gnsiAuthzReqFile == "" {
-		return nil, status.Errorf(codes.NotFound, "Could not populate authz config, please add config in inventory file")
+		return nil, nil, status.Errorf(codes.NotFound, "Could not populate authz config, please add config in inventory file")
 	}
-	data, err := os.ReadFile(gnsiAuthzReqFile)
+	req, version, err := m.artifacts.authz(gnsiAuthzReqFile)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Error opening file %s: %v", gnsiAuthzReqFile, err)
+		return nil, nil, err
 	}
-	gnsiAuthzReq = &apb.UploadRequest{}
-	if err := prototext.Unmarshal(data, gnsiAuthzReq); err != nil {
-		return nil, status.Errorf(codes.Internal, "File %s config is not a valid authz Upload Request: %v", gnsiAuthzReqFile, err)
-	}
-	var t any
-	err = json.Unmarshal([]byte(gnsiAuthzReq.Policy), &t)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Provided authz policy is not a valid json: %v", err)
-	}
-	return gnsiAuthzReq, nil
-}
-
-func populateBootConfig(conf *epb.BootConfig) (*bpb.BootConfig, error) {
-	bootConfig := &bpb.BootConfig{}
-	if conf.GetOcConfigFile() != "" {
-		ocConf, err := readOCConfig(conf.GetOcConfigFile())
-		if err != nil {
-			return nil, err
-		}
-		bootConfig.OcConfig = ocConf
-	}
-	if conf.GetVendorConfigFile() != "" {
-		cliConf, err := os.ReadFile(string(conf.VendorConfigFile))
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "Could not populate vendor config %v", err)
-		}
-		bootConfig.VendorConfig = cliConf
-	}
-	// TODO: validate OC and CLI may be added. However, this may prevent negative testing
-	bootConfig.Metadata = conf.GetMetadata()
-	bootConfig.BootloaderConfig = conf.GetBootloaderConfig()
-	return bootConfig, nil
+	return req, []ArtifactVersion{version}, nil
 }
 
 // GetBootstrapData fetches and returns the bootstrap data response from the server.
@@ -163,80 +166,335 @@ func (m *InMemoryEntityManager) GetBootstrapData(ctx context.Context, lookup *se
 		return nil, err
 	}
 	log.Infof("Control card located in inventory")
-	// TODO: for now add status for the controller card. We may need to move all runtime info to bootz service.
+	// Control cards of modular chassis may override the chassis data.
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.controlCardStatuses[serial] = bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED
-	bootCfg, err := populateBootConfig(chassis.GetConfig().GetBootConfig())
+	card := controlCardView(chassis, serial)
+	m.mu.Unlock()
+
+	// Config artifacts are loaded without holding the lock, from the copy of the chassis.
+	bootCfg, versions, err := m.artifacts.bootConfig(card.GetConfig().GetBootConfig())
 	if err != nil {
 		return nil, err
 	}
-	authzConf, err := m.populateAuthzConfig(chassis)
+	authzConf, authzVersions, err := m.populateAuthzConfig(card)
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, authzVersions...)
+	passwordHash, err := m.passwords.bootloaderHash(card)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	creds, credsVersions, err := m.populateCredentials(card)
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, credsVersions...)
+	certificates, err := m.populateCertificates(ctx, card, serial)
 	if err != nil {
 		return nil, err
 	}
 
+	// TODO: for now add status for the controller card. We may need to move all runtime info to bootz service.
+	m.mu.Lock()
+	m.controlCardStatuses[serial] = bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED
+	// Fetching bootstrap data (re)starts the bootstrap of the chassis.
+	cs := m.chassisStatus(chassis)
+	cs.Status = bpb.ReportStatusRequest_BOOTSTRAP_STATUS_UNSPECIFIED
+	cs.StatusMessage = ""
+	cs.ServedArtifacts[serial] = versions
+	m.recordTransition(cs, StatusTransition{
+		Serial:            serial,
+		ControlCardStatus: bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED,
+		Message:           "bootstrap data served",
+		Time:              time.Now(),
+		Source:            sourceAddress(ctx),
+	})
+	// The security artifacts are replaced when vouchers are added.
+	sa := m.secArtifacts
+	m.mu.Unlock()
+
 	// TODO: Populate gnsi config
 	return &bpb.BootstrapDataResponse{
 		SerialNum:        serial,
-		IntendedImage:    chassis.GetSoftwareImage(),
-		BootPasswordHash: chassis.BootloaderPasswordHash,
-		ServerTrustCert:  base64.StdEncoding.EncodeToString(m.secArtifacts.TrustAnchor.Raw),
+		IntendedImage:    card.GetSoftwareImage(),
+		BootPasswordHash: passwordHash,
+		ServerTrustCert:  serverTrustCert(ctx, sa),
 		BootConfig:       bootCfg,
-		Credentials:      &bpb.Credentials{},
-		// TODO: Populate pathz, authz and certificates.
-		Authz: authzConf,
+		Credentials:      creds,
+		// TODO: Populate pathz.
+		Authz:        authzConf,
+		Certificates: certificates,
 	}, nil
 }
 
-// SetStatus updates the status for each control card on the chassis.
-func (m *InMemoryEntityManager) SetStatus(ctx context.Context, req *bpb.ReportStatusRequest) error {
+// serverTrustCert encodes the certificate chain of the TLS serving certificate, from the leaf to the
+// trust anchor, as the base64 encoding of the concatenated DER certificates. Listeners with their own
+// keypair serve its chain as is.
+func serverTrustCert(ctx context.Context, sa *service.SecurityArtifacts) string {
+	var chain []byte
+	if certs := service.ServingChain(ctx); certs != nil {
+		for _, c := range certs {
+			chain = append(chain, c...)
+		}
+		return base64.StdEncoding.EncodeToString(chain)
+	}
+	if sa.TLSKeypair != nil {
+		for _, c := range sa.TLSKeypair.Certificate {
+			chain = append(chain, c...)
+		}
+	}
+	chain = append(chain, sa.TrustAnchor.Raw...)
+	return base64.StdEncoding.EncodeToString(chain)
+}
+
+// StatusTransition is a change of the bootstrap status of a chassis or control card.
+type StatusTransition struct {
+	// Serial is the serial of the control card, or of the chassis for fixed form factor devices.
+	Serial            string
+	ControlCardStatus bpb.ControlCardState_ControlCardStatus
+	BootstrapStatus   bpb.ReportStatusRequest_BootstrapStatus
+	Message           string
+	Time              time.Time
+	// Source is the address of the device which caused the transition.
+	Source string
+}
+
+// ChassisStatus is the last bootstrap status reported by a chassis, along with its recent history.
+type ChassisStatus struct {
+	Status        bpb.ReportStatusRequest_BootstrapStatus
+	StatusMessage string
+	// ControlCards maps the serial of each reported control card, or of a fixed chassis, to its status.
+	ControlCards map[string]bpb.ControlCardState_ControlCardStatus
+	Updated      time.Time
+	// History holds the most recent transitions of the chassis, oldest first.
+	History []StatusTransition
+	// ControlCardHistory holds the most recent transitions of each control card, oldest first.
+	ControlCardHistory map[string][]StatusTransition
+	// ServedArtifacts maps the serial of each control card, or of a fixed chassis, to the versions
+	// of the config artifacts last served to it.
+	ServedArtifacts map[string][]ArtifactVersion
+}
+
+// sourceAddress returns the address of the device which sent the request.
+func sourceAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// chassisStatus returns the status of the chassis, creating it if needed. The caller must hold m.mu.
+func (m *InMemoryEntityManager) chassisStatus(chassis *epb.Chassis) *ChassisStatus {
+	key := service.EntityLookup{Manufacturer: chassis.GetManufacturer(), SerialNumber: chassis.GetSerialNumber()}
+	cs, ok := m.chassisStatuses[key]
+	if !ok {
+		// The history starts with the decisions audited before the chassis was seen.
+		cs = m.auditStatus(key)
+		delete(m.audits, key)
+		m.chassisStatuses[key] = cs
+	}
+	return cs
+}
+
+// auditStatus returns the audit history of the chassis at the key, which was never seen, creating it
+// if needed. The caller must hold m.mu.
+func (m *InMemoryEntityManager) auditStatus(key service.EntityLookup) *ChassisStatus {
+	cs, ok := m.audits[key]
+	if !ok {
+		cs = &ChassisStatus{
+			ControlCards:       map[string]bpb.ControlCardState_ControlCardStatus{},
+			ControlCardHistory: map[string][]StatusTransition{},
+			ServedArtifacts:    map[string][]ArtifactVersion{},
+		}
+		m.audits[key] = cs
+	}
+	return cs
+}
+
+// recordTransition appends the transition to the chassis and control card histories,
+// dropping the oldest transitions beyond the history size. The caller must hold m.mu.
+func (m *InMemoryEntityManager) recordTransition(cs *ChassisStatus, t StatusTransition) {
+	cs.Updated = t.Time
+	cs.History = appendBounded(cs.History, t, m.historySize)
+	cs.ControlCardHistory[t.Serial] = appendBounded(cs.ControlCardHistory[t.Serial], t, m.historySize)
+}
+
+func appendBounded(h []StatusTransition, t StatusTransition, size int) []StatusTransition {
+	h = append(h, t)
+	if len(h) > size {
+		h = append([]StatusTransition{}, h[len(h)-size:]...)
+	}
+	return h
+}
+
+// Audit records a security decision about a request of the control card with the provided serial
+// in the history of its chassis, without changing the status of the chassis. Decisions about a
+// chassis which was never served nor reported a status are kept aside, so that it is still
+// reported as never seen, and start its history once it is. Decisions about chassis which are not
+// in the inventory are only logged.
+func (m *InMemoryEntityManager) Audit(ctx context.Context, lookup *service.EntityLookup, serial, message string) {
+	chassis, err := m.lookupChassis(lookup, serial)
+	if err != nil {
+		log.Infof("Not recording %q for unknown chassis %v: %v", message, lookup.SerialNumber, err)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := service.EntityLookup{Manufacturer: chassis.GetManufacturer(), SerialNumber: chassis.GetSerialNumber()}
+	cs, ok := m.chassisStatuses[key]
+	if !ok {
+		cs = m.auditStatus(key)
+	}
+	m.recordTransition(cs, StatusTransition{
+		Serial:            serial,
+		ControlCardStatus: m.controlCardStatuses[serial],
+		Message:           message,
+		Time:              time.Now(),
+		Source:            sourceAddress(ctx),
+	})
+}
+
+// SetStatus updates the status for each control card on the chassis of the reporting device,
+// which is resolved from the provided lookup and control card serial. Devices identified by their
+// IDevID have no manufacturer in the lookup and are resolved by serial alone.
+func (m *InMemoryEntityManager) SetStatus(ctx context.Context, lookup *service.EntityLookup, ccSerial string, req *bpb.ReportStatusRequest) error {
 	if len(req.GetStates()) == 0 {
 		return status.Errorf(codes.InvalidArgument, "no control card or fixed chassis states provided")
 	}
-	log.Infof("Bootstrap Status: %v: Status message: %v", req.GetStatus(), req.GetStatusMessage())
+	chassis, err := m.findChassis(lookup, ccSerial, true)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "unable to resolve the chassis of the reporting device: %v", err)
+	}
+	members := map[string]bool{}
+	if chassis.GetSerialNumber() != "" {
+		members[chassis.GetSerialNumber()] = true
+	}
+	for _, c := range chassis.GetControllerCards() {
+		members[c.GetSerialNumber()] = true
+	}
+	for _, c := range req.GetStates() {
+		if !members[c.GetSerialNumber()] {
+			return status.Errorf(codes.PermissionDenied, "control card %v is not part of chassis %v", c.GetSerialNumber(), chassis.GetSerialNumber())
+		}
+	}
+	log.Infof("Bootstrap Status of chassis %v: %v: Status message: %v", chassis.GetSerialNumber(), req.GetStatus(), req.GetStatusMessage())
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	cs := m.chassisStatus(chassis)
+	cs.Status = req.GetStatus()
+	cs.StatusMessage = req.GetStatusMessage()
+	now, source := time.Now(), sourceAddress(ctx)
+	event := &admpb.Event{
+		Type:            admpb.EventType_EVENT_TYPE_STATUS_REPORTED,
+		Timestamp:       timestamppb.New(now),
+		Manufacturer:    chassis.GetManufacturer(),
+		ChassisSerial:   chassis.GetSerialNumber(),
+		Source:          source,
+		Message:         req.GetStatusMessage(),
+		BootstrapStatus: req.GetStatus(),
+		States:          req.GetStates(),
+	}
+	if req.GetStatus() == bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE {
+		event.Type = admpb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED
+	}
 	for _, c := range req.GetStates() {
-		previousStatus, ok := m.controlCardStatuses[c.GetSerialNumber()]
-		if !ok {
-			return status.Errorf(codes.NotFound, "control card %v not found in inventory", c.GetSerialNumber())
-		}
+		event.ControlCardSerials = append(event.ControlCardSerials, c.GetSerialNumber())
+		previousStatus := m.controlCardStatuses[c.GetSerialNumber()]
 		log.Infof("control card %v changed status from %v to %v", c.GetSerialNumber(), previousStatus, c.GetStatus())
 		m.controlCardStatuses[c.GetSerialNumber()] = c.GetStatus()
+		cs.ControlCards[c.GetSerialNumber()] = c.GetStatus()
+		m.recordTransition(cs, StatusTransition{
+			Serial:            c.GetSerialNumber(),
+			ControlCardStatus: c.GetStatus(),
+			BootstrapStatus:   req.GetStatus(),
+			Message:           req.GetStatusMessage(),
+			Time:              now,
+			Source:            source,
+		})
+	}
+	m.events.Publish(event)
+	return nil
+}
+
+// GetStatus returns a copy of the status and history of the chassis at the provided lookup.
+// It returns NotFound if the chassis never fetched bootstrap data nor reported a status.
+func (m *InMemoryEntityManager) GetStatus(lookup *service.EntityLookup) (*ChassisStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cs, ok := m.chassisStatuses[*lookup]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no status reported for chassis %+v", *lookup)
+	}
+	cards := make(map[string]bpb.ControlCardState_ControlCardStatus, len(cs.ControlCards))
+	for k, v := range cs.ControlCards {
+		cards[k] = v
+	}
+	cardHistory := make(map[string][]StatusTransition, len(cs.ControlCardHistory))
+	for k, v := range cs.ControlCardHistory {
+		cardHistory[k] = append([]StatusTransition{}, v...)
+	}
+	served := make(map[string][]ArtifactVersion, len(cs.ServedArtifacts))
+	for k, v := range cs.ServedArtifacts {
+		served[k] = append([]ArtifactVersion{}, v...)
+	}
+	return &ChassisStatus{
+		Status:             cs.Status,
+		StatusMessage:      cs.StatusMessage,
+		ControlCards:       cards,
+		Updated:            cs.Updated,
+		History:            append([]StatusTransition{}, cs.History...),
+		ControlCardHistory: cardHistory,
+		ServedArtifacts:    served,
+	}, nil
+}
+
+// AuditHistory returns a copy of the decisions audited about the chassis at the provided lookup
+// while it was never seen, oldest first.
+func (m *InMemoryEntityManager) AuditHistory(lookup *service.EntityLookup) []StatusTransition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cs, ok := m.audits[*lookup]; ok {
+		return append([]StatusTransition{}, cs.History...)
 	}
 	return nil
 }
 
 // Sign unmarshals the SignedResponse bytes then generates a signature from its Ownership Certificate private key.
 func (m *InMemoryEntityManager) Sign(ctx context.Context, resp *bpb.GetBootstrapDataResponse, chassis *service.EntityLookup, controllerCard string) error {
+	// Only the OV is read under the lock; signing does not need it.
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	sa := m.secArtifacts
+	var ov []byte
+	var ovErr error
+	if sa != nil {
+		ov, ovErr = m.fetchOwnershipVoucher(controllerCard)
+	}
+	m.mu.Unlock()
 	// Check if security artifacts are provided for signing.
-	if m.secArtifacts == nil {
+	if sa == nil {
 		return status.Errorf(codes.Internal, "security artifact is missing")
 	}
 	if len(resp.GetSerializedBootstrapData()) == 0 {
 		return status.Errorf(codes.InvalidArgument, "empty serialized bootstrap data")
 	}
 
-	sig, err := signature.Sign(m.secArtifacts.OwnerCertPrivateKey, resp.GetSerializedBootstrapData())
+	sig, err := signature.Sign(sa.OwnerCertPrivateKey, resp.GetSerializedBootstrapData())
 	if err != nil {
 		return err
 	}
 	resp.ResponseSignature = sig
 
 	// Populate the OV
-	ov, err := m.fetchOwnershipVoucher(controllerCard)
-	if err != nil {
-		return err
+	if ovErr != nil {
+		return ovErr
 	}
 	resp.OwnershipVoucher = ov
 	log.Infof("OV populated")
 
 	// Populate the OC
-	ocCMS, err := ownercertificate.GenerateCMS(m.secArtifacts.OwnerCert, m.secArtifacts.OwnerCertPrivateKey)
+	ocCMS, err := ownercertificate.GenerateCMS(sa.OwnerCert, sa.OwnerCertPrivateKey)
 	if err != nil {
 		return err
 	}
@@ -254,6 +512,27 @@ func (m *InMemoryEntityManager) fetchOwnershipVoucher(ccSerial string) ([]byte,
 	return ov, nil
 }
 
+// updateOVs installs a copy of the security artifacts with the provided ownership vouchers added
+// and those of the retired serials removed. The artifacts are shared with the server, so they are
+// replaced rather than modified. The caller must hold m.mu.
+func (m *InMemoryEntityManager) updateOVs(add service.OVList, retired ...string) {
+	if m.secArtifacts == nil || (len(add) == 0 && len(retired) == 0) {
+		return
+	}
+	sa := *m.secArtifacts
+	sa.OV = make(service.OVList, len(m.secArtifacts.OV)+len(add))
+	for k, v := range m.secArtifacts.OV {
+		sa.OV[k] = v
+	}
+	for k, v := range add {
+		sa.OV[k] = v
+	}
+	for _, k := range retired {
+		delete(sa.OV, k)
+	}
+	m.secArtifacts = &sa
+}
+
 // AddControlCard adds a new control card to the entity manager.
 func (m *InMemoryEntityManager) AddControlCard(serial string) *InMemoryEntityManager {
 	m.mu.Lock()
@@ -267,7 +546,7 @@ func (m *InMemoryEntityManager) AddControlCard(serial string) *InMemoryEntityMan
 func (m *InMemoryEntityManager) AddChassis(bootMode bpb.BootMode, manufacturer string, serial string) *InMemoryEntityManager {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.chassisInventory = append(m.chassisInventory, &epb.Chassis{
+	m.addChassis(&epb.Chassis{
 		Manufacturer: manufacturer,
 		SerialNumber: serial,
 		BootMode:     bootMode,
@@ -276,35 +555,69 @@ func (m *InMemoryEntityManager) AddChassis(bootMode bpb.BootMode, manufacturer s
 	return m
 }
 
-// GetChassisInventory returns the chassis inventory
+// GetChassisInventory returns the chassis of a snapshot of the inventory, which must not be
+// modified.
 func (m *InMemoryEntityManager) GetChassisInventory() []*epb.Chassis {
-	return m.chassisInventory
+	return m.Snapshot().Chassis
+}
+
+// Option configures an InMemoryEntityManager.
+type Option func(*InMemoryEntityManager)
+
+// WithHistorySize sets the number of status transitions kept per chassis and per control card.
+func WithHistorySize(n int) Option {
+	return func(m *InMemoryEntityManager) {
+		m.historySize = n
+	}
+}
+
+// WithEvents publishes an event to the bus for each status report.
+func WithEvents(bus *events.Bus) Option {
+	return func(m *InMemoryEntityManager) {
+		m.events = bus
+	}
 }
 
 // New returns a new in-memory entity manager.
-func New(chassisConfigFile string, artifacts *service.SecurityArtifacts) (*InMemoryEntityManager, error) {
+func New(chassisConfigFile string, artifacts *service.SecurityArtifacts, opts ...Option) (*InMemoryEntityManager, error) {
 	newManager := &InMemoryEntityManager{
 		controlCardStatuses: map[string]bpb.ControlCardState_ControlCardStatus{},
+		chassisStatuses:     map[service.EntityLookup]*ChassisStatus{},
+		audits:              map[service.EntityLookup]*ChassisStatus{},
+		historySize:         DefaultHistorySize,
+		maxDiscovered:       DefaultMaxDiscovered,
+		provisioned:         map[service.EntityLookup]time.Time{},
+		provisionalTTL:      DefaultProvisionalTTL,
 		defaults:            &epb.Options{GnsiGlobalConfig: &epb.GNSIConfig{}},
 		secArtifacts:        artifacts,
+		index:               newInventoryIndex(nil),
+	}
+	for _, opt := range opts {
+		opt(newManager)
 	}
 	if chassisConfigFile == "" {
 		return newManager, nil
 	}
-	protoTextFile, err := os.ReadFile(chassisConfigFile)
+	entities, err := loadInventory(chassisConfigFile)
 	if err != nil {
-		log.Errorf("Error in opening file %s : #%v ", chassisConfigFile, err)
-		return nil, err
-	}
-	entities := epb.Entities{}
-	err = prototext.Unmarshal(protoTextFile, &entities)
-	if err != nil {
-		log.Errorf("Error in un-marshalling %s: %v", protoTextFile, err)
+		log.Errorf("Error in loading inventory %s: %v", chassisConfigFile, err)
 		return nil, err
 	}
 	log.Infof("New entity manager is initialized successfully from chassis config file %s", chassisConfigFile)
 	newManager.chassisInventory = entities.Chassis
+	newManager.index = newInventoryIndex(entities.Chassis)
 	newManager.defaults = entities.GetOptions()
+	if err := validateProfiles(entities.GetProfiles()); err != nil {
+		return nil, fmt.Errorf("invalid profiles in %s: %v", chassisConfigFile, err)
+	}
+	newManager.profiles = entities.GetProfiles()
+	newManager.preloadArtifacts()
+	if err := newManager.preloadPasswords(); err != nil {
+		return nil, err
+	}
+	if err := newManager.preloadCredentials(); err != nil {
+		return nil, err
+	}
 	return newManager, nil
 }
 
@@ -317,11 +630,9 @@ func (m *InMemoryEntityManager) ReplaceDevice(old *service.EntityLookup, new *ep
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	for i, ch := range m.chassisInventory {
-		if ch.GetManufacturer() == old.Manufacturer && ch.GetSerialNumber() == old.SerialNumber {
-			m.chassisInventory[i] = new
-			return nil
-		}
+	if ch := first(m.index.bySerial[old.SerialNumber], old.Manufacturer, false); ch != nil {
+		m.replaceChassis(m.position(ch), new)
+		return nil
 	}
 
 	return status.Errorf(codes.NotFound, "chassis %+v not found", *old)
@@ -331,10 +642,12 @@ func (m *InMemoryEntityManager) ReplaceDevice(old *service.EntityLookup, new *ep
 func (m *InMemoryEntityManager) DeleteDevice(chassis *service.EntityLookup) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	for i, ch := range m.chassisInventory {
-		if ch.GetManufacturer() == chassis.Manufacturer && ch.GetSerialNumber() == chassis.SerialNumber {
-			m.chassisInventory = append(m.chassisInventory[:i], m.chassisInventory[i+1:]...)
+	for {
+		ch := first(m.index.bySerial[chassis.SerialNumber], chassis.Manufacturer, false)
+		if ch == nil {
+			return
 		}
+		m.removeChassis(m.position(ch))
 	}
 }
 
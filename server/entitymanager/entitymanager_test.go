@@ -17,23 +17,47 @@ package entitymanager
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/h-fam/errdiff"
 	ownercertificate "github.com/openconfig/bootz/common/owner_certificate"
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
 	"github.com/openconfig/bootz/common/signature"
+	"github.com/openconfig/bootz/server/certs"
+	"github.com/openconfig/bootz/server/secrets"
 	"github.com/openconfig/bootz/server/service"
 	artifacts "github.com/openconfig/bootz/testdata"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	bpb "github.com/openconfig/bootz/proto/bootz"
 	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
 	apb "github.com/openconfig/gnsi/authz"
+	cpb "github.com/openconfig/gnsi/credentialz"
 )
 
 // MustMarshalBootstrapDataSigned is a helper function that marshals a BootstrapDataSigned message.
@@ -198,6 +222,7 @@ func TestFetchOwnershipVoucher(t *testing.T) {
 
 	em, _ := New("", a)
 	em.chassisInventory = []*epb.Chassis{&chassis}
+	em.index = newInventoryIndex(em.chassisInventory)
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
@@ -212,6 +237,23 @@ func TestFetchOwnershipVoucher(t *testing.T) {
 	}
 }
 
+func TestResolveChassisByControlCard(t *testing.T) {
+	em, err := New("../../testdata/inventory.prototxt", nil)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "456")
+	for _, serial := range []string{"123", "456", ""} {
+		got, err := em.ResolveChassis(context.Background(), &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial}, "123A")
+		if err != nil {
+			t.Fatalf("ResolveChassis(%q, 123A) err = %v", serial, err)
+		}
+		if got.SerialNumber != "123" {
+			t.Errorf("ResolveChassis(%q, 123A) = chassis %q, want the chassis of the control card 123", serial, got.SerialNumber)
+		}
+	}
+}
+
 func TestResolveChassis(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -226,7 +268,9 @@ func TestResolveChassis(t *testing.T) {
 			Manufacturer: "Cisco",
 		},
 		want: &service.ChassisEntity{
-			BootMode: bpb.BootMode_BOOT_MODE_SECURE,
+			BootMode:     bpb.BootMode_BOOT_MODE_SECURE,
+			Manufacturer: "Cisco",
+			SerialNumber: "123",
 		},
 	}, {
 		desc: "Chassis Not Found",
@@ -327,56 +371,121 @@ func TestSign(t *testing.T) {
 
 func TestSetStatus(t *testing.T) {
 	ctx := context.Background()
+	initialized := func(serials ...string) []*bpb.ControlCardState {
+		var states []*bpb.ControlCardState
+		for _, s := range serials {
+			states = append(states, &bpb.ControlCardState{
+				SerialNumber: s,
+				Status:       *bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED.Enum(),
+			})
+		}
+		return states
+	}
 	tests := []struct {
-		desc    string
-		input   *bpb.ReportStatusRequest
-		wantErr bool
+		desc     string
+		lookup   *service.EntityLookup
+		ccSerial string
+		input    *bpb.ReportStatusRequest
+		wantCode codes.Code
 	}{{
-		desc: "No control card states",
+		desc:   "No control card states",
+		lookup: &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"},
 		input: &bpb.ReportStatusRequest{
 			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
 			StatusMessage: "Bootstrap status succeeded",
 		},
-		wantErr: true,
+		wantCode: codes.InvalidArgument,
 	}, {
-		desc: "Control card initialized",
+		desc:   "Control card initialized",
+		lookup: &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"},
 		input: &bpb.ReportStatusRequest{
 			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
 			StatusMessage: "Bootstrap status succeeded",
-			States: []*bpb.ControlCardState{
-				{
-					SerialNumber: "123A",
-					Status:       *bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED.Enum(),
-				},
-			},
+			States:        initialized("123A"),
 		},
-		wantErr: false,
 	}, {
-		desc: "Unknown control card",
+		desc:     "Caller resolved by control card",
+		lookup:   &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123B"},
+		ccSerial: "123B",
 		input: &bpb.ReportStatusRequest{
 			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
 			StatusMessage: "Bootstrap status succeeded",
-			States: []*bpb.ControlCardState{
-				{
-					SerialNumber: "123C",
-					Status:       *bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED.Enum(),
-				},
-			},
+			States:        initialized("123A", "123B"),
 		},
-		wantErr: true,
-	},
-	}
+	}, {
+		desc:     "Caller resolved by IDevID serial without manufacturer",
+		lookup:   &service.EntityLookup{SerialNumber: "123A"},
+		ccSerial: "123A",
+		input: &bpb.ReportStatusRequest{
+			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+			StatusMessage: "Bootstrap status succeeded",
+			States:        initialized("123A"),
+		},
+	}, {
+		desc:   "Unknown control card",
+		lookup: &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"},
+		input: &bpb.ReportStatusRequest{
+			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+			StatusMessage: "Bootstrap status succeeded",
+			States:        initialized("123C"),
+		},
+		wantCode: codes.PermissionDenied,
+	}, {
+		desc:   "Control card of another chassis",
+		lookup: &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"},
+		input: &bpb.ReportStatusRequest{
+			Status:        bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+			StatusMessage: "Bootstrap status succeeded",
+			States:        initialized("123A", "456A"),
+		},
+		wantCode: codes.PermissionDenied,
+	}, {
+		desc:   "Unknown caller",
+		lookup: &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "789"},
+		input: &bpb.ReportStatusRequest{
+			Status: bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+			States: initialized("123A"),
+		},
+		wantCode: codes.PermissionDenied,
+	}}
 	em, _ := New("", nil)
-	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "123").AddControlCard("123A")
+	em.chassisInventory = []*epb.Chassis{{
+		Manufacturer:    "Cisco",
+		SerialNumber:    "123",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "123A"}, {SerialNumber: "123B"}},
+	}, {
+		Manufacturer:    "Cisco",
+		SerialNumber:    "456",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "456A"}},
+	}}
+	em.index = newInventoryIndex(em.chassisInventory)
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			err := em.SetStatus(ctx, test.input)
-			if (err != nil) != test.wantErr {
-				t.Errorf("SetStatus(%v) err = %v, want %v", test.input, err, test.wantErr)
+			err := em.SetStatus(ctx, test.lookup, test.ccSerial, test.input)
+			if got := status.Code(err); got != test.wantCode {
+				t.Fatalf("SetStatus(%v) err = %v, want code %v", test.input, err, test.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			got, err := em.GetStatus(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"})
+			if err != nil {
+				t.Fatalf("GetStatus() err = %v", err)
+			}
+			if got.Status != test.input.GetStatus() || got.StatusMessage != test.input.GetStatusMessage() {
+				t.Errorf("GetStatus() = %v %q, want %v %q", got.Status, got.StatusMessage, test.input.GetStatus(), test.input.GetStatusMessage())
+			}
+			for _, s := range test.input.GetStates() {
+				if got.ControlCards[s.GetSerialNumber()] != s.GetStatus() {
+					t.Errorf("GetStatus() control card %v = %v, want %v", s.GetSerialNumber(), got.ControlCards[s.GetSerialNumber()], s.GetStatus())
+				}
 			}
 		})
 	}
+	if _, err := em.GetStatus(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "456"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetStatus() of chassis without reports err = %v, want NotFound", err)
+	}
 }
 
 func TestGetBootstrapData(t *testing.T) {
@@ -385,7 +494,11 @@ func TestGetBootstrapData(t *testing.T) {
 		t.Fatalf("unable to generate server artifacts: %v", err)
 	}
 	ctx := context.Background()
-	encodedServerTrustCert := base64.StdEncoding.EncodeToString(a.TrustAnchor.Raw)
+	var chain []byte
+	for _, c := range a.TLSKeypair.Certificate {
+		chain = append(chain, c...)
+	}
+	encodedServerTrustCert := base64.StdEncoding.EncodeToString(append(chain, a.TrustAnchor.Raw...))
 	chassis := epb.Chassis{
 		Name:                   "test",
 		SerialNumber:           "123",
@@ -542,6 +655,7 @@ func TestGetBootstrapData(t *testing.T) {
 		t.Fatalf("unable to create entitymanager: %v", err)
 	}
 	em.chassisInventory = []*epb.Chassis{&chassis}
+	em.index = newInventoryIndex(em.chassisInventory)
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
@@ -554,6 +668,16 @@ func TestGetBootstrapData(t *testing.T) {
 			}
 		})
 	}
+
+	// A listener with its own keypair sends its chain as the trust cert.
+	served := [][]byte{[]byte("leaf"), []byte("root")}
+	got, err := em.GetBootstrapData(service.WithServingChain(ctx, served), &service.EntityLookup{SerialNumber: "123", Manufacturer: "Cisco"}, &bpb.ControlCard{SerialNumber: "123A"})
+	if err != nil {
+		t.Fatalf("GetBootstrapData() over a listener with its own keypair err = %v", err)
+	}
+	if want := base64.StdEncoding.EncodeToString([]byte("leafroot")); got.GetServerTrustCert() != want {
+		t.Errorf("GetBootstrapData() over a listener with its own keypair server_trust_cert = %q, want %q", got.GetServerTrustCert(), want)
+	}
 }
 
 func readTextFromFile(t *testing.T, file string) string {
@@ -624,7 +748,8 @@ func TestLoadConfig(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			gotBootConfig, err := populateBootConfig(test.bootConfig)
+			var cache artifactCache
+			gotBootConfig, _, err := cache.bootConfig(test.bootConfig)
 			if err == nil {
 				if diff := cmp.Diff(test.wantBootConfig.GetVendorConfig(), gotBootConfig.GetVendorConfig()); diff != "" {
 					t.Fatalf("wanted vendor config differs from the got config %s", diff)
@@ -679,6 +804,7 @@ func TestGetDevice(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			em := InMemoryEntityManager{
 				chassisInventory: tt.inventory,
+				index:            newInventoryIndex(tt.inventory),
 			}
 
 			got, err := em.GetDevice(tt.lookup)
@@ -716,6 +842,7 @@ func TestGetAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			em := InMemoryEntityManager{
 				chassisInventory: tt.inventory,
+				index:            newInventoryIndex(tt.inventory),
 			}
 			got := em.GetAll()
 
@@ -760,6 +887,7 @@ func TestReplaceDevice(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			em := InMemoryEntityManager{
 				chassisInventory: tt.inventory,
+				index:            newInventoryIndex(tt.inventory),
 			}
 
 			err := em.ReplaceDevice(tt.lookup, tt.newChassis)
@@ -816,6 +944,7 @@ func TestDeleteDevice(t *testing.T) {
 
 			em := InMemoryEntityManager{
 				chassisInventory: tt.inventory,
+				index:            newInventoryIndex(tt.inventory),
 			}
 
 			em.DeleteDevice(tt.lookup)
@@ -826,3 +955,1679 @@ func TestDeleteDevice(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscovery(t *testing.T) {
+	ctx := context.Background()
+	em, err := New("../../testdata/inventory.prototxt", nil, WithMaxDiscovered(2))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	request := func(serial string, cards ...string) *bpb.GetBootstrapDataRequest {
+		req := &bpb.GetBootstrapDataRequest{
+			ChassisDescriptor: &bpb.ChassisDescriptor{Manufacturer: "Cisco", SerialNumber: serial, PartNumber: "PN-" + serial},
+			Nonce:             "nonce",
+		}
+		for _, c := range cards {
+			req.ChassisDescriptor.ControlCards = append(req.ChassisDescriptor.ControlCards, &bpb.ControlCard{SerialNumber: c, PartNumber: "PN-" + c})
+		}
+		if len(cards) > 0 {
+			req.ControlCardState = &bpb.ControlCardState{SerialNumber: cards[0]}
+		}
+		return req
+	}
+	em.Discover(ctx, request("900", "900A", "900B"))
+	em.Discover(ctx, request("900", "900A", "900B"))
+	em.Discover(ctx, request("901"))
+
+	got := em.Discovered()
+	if len(got) != 2 {
+		t.Fatalf("Discovered() returned %d devices, want 2", len(got))
+	}
+	if got[0].SerialNumber != "900" || got[0].Requests != 2 || !got[0].NoncePresent || got[0].ActiveSerial != "900A" || len(got[0].ControlCards) != 2 {
+		t.Errorf("Discovered()[0] = %+v, want chassis 900 seen twice from 900A with a nonce", got[0])
+	}
+
+	if _, err := em.RejectDevice(&service.EntityLookup{SerialNumber: "901"}, "not ours"); err != nil {
+		t.Fatalf("RejectDevice() err = %v", err)
+	}
+	// Rejected devices stay rejected when they retry, and are not evicted.
+	em.Discover(ctx, request("901"))
+	em.Discover(ctx, request("902"))
+	em.Discover(ctx, request("903"))
+	var serials []string
+	for _, d := range em.Discovered() {
+		serials = append(serials, fmt.Sprintf("%v:%v", d.SerialNumber, d.Rejected))
+	}
+	if want := []string{"901:true", "903:false"}; !cmp.Equal(serials, want) {
+		t.Errorf("Discovered() = %v, want %v", serials, want)
+	}
+
+	tests := []struct {
+		desc     string
+		serial   string
+		profile  string
+		wantCode codes.Code
+	}{
+		{desc: "No profile", serial: "903", wantCode: codes.InvalidArgument},
+		{desc: "Unknown profile", serial: "903", profile: "missing", wantCode: codes.NotFound},
+		{desc: "Unknown device", serial: "999", profile: "default", wantCode: codes.NotFound},
+		{desc: "Approved", serial: "903", profile: "default"},
+		{desc: "Already approved", serial: "903", profile: "default", wantCode: codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := em.ApproveDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: test.serial}, test.profile, "")
+			if status.Code(err) != test.wantCode {
+				t.Errorf("ApproveDevice() err = %v, want %v", err, test.wantCode)
+			}
+		})
+	}
+	ch, err := em.GetDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "903"})
+	if err != nil {
+		t.Fatalf("GetDevice() err = %v", err)
+	}
+	if ch.GetName() != "903" || ch.GetProfile() != "default" || ch.GetPartNumber() != "PN-903" || ch.GetSoftwareImage().GetVersion() != "1.0" {
+		t.Errorf("approved chassis = %v, want chassis 903 configured from the default profile", ch)
+	}
+}
+
+// TestApproveSecureMode tests that an approved device gets an ownership voucher and bootstraps in
+// secure mode.
+func TestApproveSecureMode(t *testing.T) {
+	a, err := artifacts.GenerateSecurityArtifacts([]string{"123A", "123B"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	em, err := New("../../testdata/inventory.prototxt", a, WithOVGenerator(func(serial string) ([]byte, error) {
+		return artifacts.NewOwnershipVoucher(serial, a.PDC, a.VendorCA, a.VendorCAPrivateKey)
+	}))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	s := service.New(em, service.WithDiscoverer(em))
+	ctx := context.Background()
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			SerialNumber: "900",
+			PartNumber:   "PN-900",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "900A", PartNumber: "PN-900A", Slot: 1}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "900A"},
+		Nonce:            "first-nonce",
+	}
+	if _, err := s.GetBootstrapData(ctx, req); err == nil {
+		t.Fatalf("GetBootstrapData() of an unknown device succeeded, want error")
+	}
+	if _, err := em.ApproveDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "900"}, "default", ""); err != nil {
+		t.Fatalf("ApproveDevice() err = %v", err)
+	}
+	if _, ok := a.OV["900A"]; ok {
+		t.Errorf("ApproveDevice() modified the shared security artifacts")
+	}
+	req.Nonce = "second-nonce"
+	resp, err := s.GetBootstrapData(ctx, req)
+	if err != nil {
+		t.Fatalf("GetBootstrapData() of the approved device err = %v", err)
+	}
+	ov, err := ownershipvoucher.Unmarshal(resp.GetOwnershipVoucher(), nil)
+	if err != nil {
+		t.Fatalf("unable to parse the ownership voucher: %v", err)
+	}
+	if got := ov.OV.SerialNumber; got != "900A" {
+		t.Errorf("ownership voucher serial = %v, want 900A", got)
+	}
+}
+
+func TestFallbackProfiles(t *testing.T) {
+	desc := func(part string, cards ...string) *bpb.ChassisDescriptor {
+		d := &bpb.ChassisDescriptor{Manufacturer: "Cisco", SerialNumber: "S1", PartNumber: part}
+		for i, c := range cards {
+			d.ControlCards = append(d.ControlCards, &bpb.ControlCard{SerialNumber: fmt.Sprintf("S1-%d", i), PartNumber: c})
+		}
+		return d
+	}
+	tests := []struct {
+		desc    string
+		match   *epb.ProfileMatch
+		chassis *bpb.ChassisDescriptor
+		want    bool
+	}{{
+		desc:    "Chassis and control cards match",
+		match:   &epb.ProfileMatch{Manufacturer: "Cisco", PartNumber: "8201-*", ControlCardPartNumber: "8201-RP*"},
+		chassis: desc("8201-SYS", "8201-RP1", "8201-RP2"),
+		want:    true,
+	}, {
+		desc:    "Other manufacturer",
+		match:   &epb.ProfileMatch{Manufacturer: "Juniper", PartNumber: "8201-*"},
+		chassis: desc("8201-SYS"),
+	}, {
+		desc:    "Chassis part mismatch",
+		match:   &epb.ProfileMatch{Manufacturer: "Cisco", PartNumber: "8201-*"},
+		chassis: desc("8808-SYS"),
+	}, {
+		desc:    "One control card mismatch",
+		match:   &epb.ProfileMatch{Manufacturer: "Cisco", ControlCardPartNumber: "8201-RP*"},
+		chassis: desc("8201-SYS", "8201-RP1", "8800-RP"),
+	}, {
+		desc:    "Fixed chassis with control card pattern",
+		match:   &epb.ProfileMatch{Manufacturer: "Cisco", ControlCardPartNumber: "8201-RP*"},
+		chassis: desc("8201-SYS"),
+	}, {
+		desc:    "Manufacturer only",
+		match:   &epb.ProfileMatch{Manufacturer: "Cisco"},
+		chassis: desc("anything"),
+		want:    true,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := fallbackMatches(test.match, test.chassis); got != test.want {
+				t.Errorf("fallbackMatches() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	invalid := []*epb.Profile{{Name: "bad", Fallback: []*epb.ProfileMatch{{Manufacturer: "Cisco", PartNumber: "[8201"}}}}
+	if err := validateProfiles(invalid); err == nil {
+		t.Errorf("validateProfiles() with an invalid pattern succeeded")
+	}
+
+	em, err := New("../../testdata/inventory.prototxt", nil)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	ctx := context.Background()
+	spare := &bpb.GetBootstrapDataRequest{ChassisDescriptor: desc("8201-SYS", "8201-RP1")}
+	if !em.Discover(ctx, spare) {
+		t.Fatalf("Discover() of a spare = false, want provisioned")
+	}
+	ch, err := em.ResolveChassis(ctx, &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "S1"}, "S1-0")
+	if err != nil {
+		t.Fatalf("ResolveChassis() of a provisioned spare err = %v", err)
+	}
+	if ch.BootMode != bpb.BootMode_BOOT_MODE_INSECURE {
+		t.Errorf("ResolveChassis() boot mode = %v, want the profile's", ch.BootMode)
+	}
+	inv, err := em.GetDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "S1"})
+	if err != nil {
+		t.Fatalf("GetDevice() err = %v", err)
+	}
+	if !inv.GetProvisional() || inv.GetProfile() != "spares" || inv.GetSoftwareImage().GetVersion() != "0.9" {
+		t.Errorf("provisioned chassis = %v, want provisional chassis with the spares profile", inv)
+	}
+	cs, err := em.GetStatus(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "S1"})
+	if err != nil || len(cs.History) != 1 {
+		t.Fatalf("GetStatus() = %+v, %v, want the provisioning transition", cs, err)
+	}
+	if len(em.Discovered()) != 0 {
+		t.Errorf("Discovered() = %v, want provisioned devices not listed", em.Discovered())
+	}
+
+	// Approving a provisional chassis confirms it with the chosen profile.
+	confirmed, err := em.ApproveDevice(&service.EntityLookup{SerialNumber: "S1-0"}, "default", "spare-1")
+	if err != nil {
+		t.Fatalf("ApproveDevice() err = %v", err)
+	}
+	if confirmed.GetProvisional() || confirmed.GetProfile() != "default" || confirmed.GetName() != "spare-1" || len(confirmed.GetControllerCards()) != 1 {
+		t.Errorf("ApproveDevice() = %v, want confirmed chassis spare-1 with the default profile", confirmed)
+	}
+
+	// Devices which match no fallback are discovered.
+	other := desc("8808-SYS")
+	other.SerialNumber = "S2"
+	if em.Discover(ctx, &bpb.GetBootstrapDataRequest{ChassisDescriptor: other}) {
+		t.Errorf("Discover() of an unmatched device = true, want false")
+	}
+}
+
+// TestProvisionalLimits tests that provisional chassis are bounded in number and expire without approval.
+func TestProvisionalLimits(t *testing.T) {
+	spare := func(serial string) *bpb.GetBootstrapDataRequest {
+		return &bpb.GetBootstrapDataRequest{ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			SerialNumber: serial,
+			PartNumber:   "8201-SYS",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: serial + "A", PartNumber: "8201-RP1"}},
+		}}
+	}
+	provisioned := func(em *InMemoryEntityManager) []string {
+		var serials []string
+		for _, ch := range em.GetAll() {
+			if ch.GetProvisional() {
+				serials = append(serials, ch.GetSerialNumber())
+			}
+		}
+		return serials
+	}
+	ctx := context.Background()
+
+	em, err := New("../../testdata/inventory.prototxt", nil, WithMaxDiscovered(2))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	for _, serial := range []string{"S1", "S2", "S3"} {
+		if !em.Discover(ctx, spare(serial)) {
+			t.Fatalf("Discover(%v) = false, want provisioned", serial)
+		}
+	}
+	if got, want := provisioned(em), []string{"S2", "S3"}; !cmp.Equal(got, want) {
+		t.Errorf("provisional chassis = %v, want %v", got, want)
+	}
+	if _, err := em.GetStatus(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "S1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetStatus() of a dropped provisional chassis err = %v, want NotFound", err)
+	}
+
+	em, err = New("../../testdata/inventory.prototxt", nil, WithProvisionalTTL(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	em.Discover(ctx, spare("S1"))
+	if _, err := em.ApproveDevice(&service.EntityLookup{SerialNumber: "S1"}, "default", ""); err != nil {
+		t.Fatalf("ApproveDevice() err = %v", err)
+	}
+	em.Discover(ctx, spare("S2"))
+	time.Sleep(time.Millisecond)
+	em.Discover(ctx, spare("S3"))
+	if got, want := provisioned(em), []string{"S3"}; !cmp.Equal(got, want) {
+		t.Errorf("provisional chassis = %v, want %v", got, want)
+	}
+	if _, err := em.GetDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "S1"}); err != nil {
+		t.Errorf("GetDevice() of an approved chassis err = %v, want it kept", err)
+	}
+}
+
+func TestReplaceControlCard(t *testing.T) {
+	a, err := artifacts.GenerateSecurityArtifacts([]string{"123A", "123B"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	newOV := func(serial string) []byte {
+		ov, err := artifacts.NewOwnershipVoucher(serial, a.PDC, a.VendorCA, a.VendorCAPrivateKey)
+		if err != nil {
+			t.Fatalf("NewOwnershipVoucher() err = %v", err)
+		}
+		return ov
+	}
+	em, err := New("../../testdata/inventory.prototxt", a, WithOVGenerator(func(serial string) ([]byte, error) {
+		return newOV(serial), nil
+	}))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "456")
+	ctx := context.Background()
+	chassis := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+	if _, err := em.GetBootstrapData(ctx, chassis, &bpb.ControlCard{SerialNumber: "123B"}); err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	suppliedOV := newOV("123D")
+	before := em.Snapshot()
+
+	tests := []struct {
+		desc     string
+		r        *ControlCardReplacement
+		wantCode codes.Code
+	}{{
+		desc:     "Missing serial",
+		r:        &ControlCardReplacement{OldSerial: "123B"},
+		wantCode: codes.InvalidArgument,
+	}, {
+		desc:     "Unknown card",
+		r:        &ControlCardReplacement{OldSerial: "999A", NewSerial: "999B"},
+		wantCode: codes.NotFound,
+	}, {
+		desc:     "New card already in inventory",
+		r:        &ControlCardReplacement{OldSerial: "123B", NewSerial: "123A"},
+		wantCode: codes.AlreadyExists,
+	}, {
+		desc:     "OV for another serial",
+		r:        &ControlCardReplacement{OldSerial: "123B", NewSerial: "123C", OwnershipVoucher: suppliedOV},
+		wantCode: codes.InvalidArgument,
+	}, {
+		desc: "Generated OV",
+		r:    &ControlCardReplacement{OldSerial: "123B", NewSerial: "123C", HardwareAddress: "00:11:22:33:44:55"},
+	}, {
+		desc: "Supplied OV",
+		r:    &ControlCardReplacement{Manufacturer: "Cisco", OldSerial: "123C", NewSerial: "123D", OwnershipVoucher: suppliedOV},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := em.ReplaceControlCard(ctx, test.r)
+			if status.Code(err) != test.wantCode {
+				t.Errorf("ReplaceControlCard() err = %v, want %v", err, test.wantCode)
+			}
+		})
+	}
+
+	ch, err := em.GetDevice(chassis)
+	if err != nil {
+		t.Fatalf("GetDevice() err = %v", err)
+	}
+	want := []*epb.ControlCard{
+		{SerialNumber: "123A", PartNumber: "123A", DhcpConfig: &epb.DHCPConfig{}},
+		{SerialNumber: "123D", PartNumber: "123B", DhcpConfig: &epb.DHCPConfig{}},
+	}
+	if diff := cmp.Diff(want, ch.GetControllerCards(), protocmp.Transform()); diff != "" {
+		t.Errorf("control cards after replacement differ (-want +got):\n%s", diff)
+	}
+	// Earlier snapshots and the shared security artifacts are left unchanged.
+	for _, ch := range before.Chassis {
+		if ch.GetSerialNumber() == "123" && ch.GetControllerCards()[1].GetSerialNumber() != "123B" {
+			t.Errorf("snapshot taken before the replacement has control card %v, want 123B", ch.GetControllerCards()[1].GetSerialNumber())
+		}
+	}
+	if _, ok := a.OV["123B"]; !ok || a.OV["123D"] != nil {
+		t.Errorf("ReplaceControlCard() modified the shared security artifacts")
+	}
+	if _, ok := em.secArtifacts.OV["123B"]; ok {
+		t.Errorf("OV of the replaced card 123B was not retired")
+	}
+	if !bytes.Equal(em.secArtifacts.OV["123D"], suppliedOV) {
+		t.Errorf("OV of 123D is not the supplied OV")
+	}
+	cs, err := em.GetStatus(chassis)
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if _, ok := cs.ControlCards["123B"]; ok {
+		t.Errorf("status of the replaced card 123B was not retired")
+	}
+	if h := cs.ControlCardHistory["123B"]; len(h) < 2 || h[len(h)-1].Message != "control card 123B replaced by 123C" {
+		t.Errorf("history of the replaced card 123B = %+v, want its bootstrap and replacement kept", h)
+	}
+	if got := cs.History[len(cs.History)-1].Message; got != "control card 123C replaced by 123D" {
+		t.Errorf("last transition = %q, want the replacement", got)
+	}
+
+	// The replacement card can fetch signed bootstrap data.
+	resp := &bpb.GetBootstrapDataResponse{SerializedBootstrapData: []byte("data")}
+	if err := em.Sign(ctx, resp, chassis, "123D"); err != nil {
+		t.Errorf("Sign() for the replacement card err = %v", err)
+	}
+}
+
+func TestControlCardView(t *testing.T) {
+	image := &bpb.SoftwareImage{Name: "Default Image", Version: "1.0"}
+	standby := &bpb.SoftwareImage{Name: "Standby Image", Version: "1.1"}
+	mustStruct := func(m map[string]any) *structpb.Struct {
+		s, err := structpb.NewStruct(m)
+		if err != nil {
+			t.Fatalf("NewStruct() err = %v", err)
+		}
+		return s
+	}
+	chassis := &epb.Chassis{
+		SerialNumber:           "123",
+		BootloaderPasswordHash: "ABCD123",
+		SoftwareImage:          image,
+		Config: &epb.Config{
+			BootConfig: &epb.BootConfig{
+				VendorConfigFile: "chassis.cfg",
+				Metadata:         mustStruct(map[string]any{"a": "chassis", "b": "chassis"}),
+			},
+			GnsiConfig: &epb.GNSIConfig{
+				AuthzUpload:     &apb.UploadRequest{Version: "chassis"},
+				PathzUploadFile: "pathz.prototext",
+			},
+		},
+		ControllerCards: []*epb.ControlCard{{
+			SerialNumber: "123A",
+		}, {
+			SerialNumber:           "123B",
+			SoftwareImage:          standby,
+			BootloaderPasswordHash: "EFGH456",
+			BootConfig: &epb.BootConfig{
+				Metadata: mustStruct(map[string]any{"b": "card"}),
+			},
+			GnsiConfig: &epb.GNSIConfig{
+				AuthzUploadFile: "authz.prototext",
+			},
+		}},
+	}
+	tests := []struct {
+		desc   string
+		serial string
+		want   *epb.Chassis
+	}{{
+		desc:   "card without overrides",
+		serial: "123A",
+		want:   chassis,
+	}, {
+		desc:   "unknown card",
+		serial: "123C",
+		want:   chassis,
+	}, {
+		desc:   "card with overrides",
+		serial: "123B",
+		want: func() *epb.Chassis {
+			want := proto.Clone(chassis).(*epb.Chassis)
+			want.SoftwareImage = standby
+			want.BootloaderPasswordHash = "EFGH456"
+			want.Config.BootConfig.Metadata = mustStruct(map[string]any{"a": "chassis", "b": "card"})
+			want.Config.GnsiConfig.AuthzUpload = nil
+			want.Config.GnsiConfig.AuthzUploadFile = "authz.prototext"
+			return want
+		}(),
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := controlCardView(chassis, test.serial)
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("controlCardView() diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+	if chassis.GetSoftwareImage() != image || chassis.GetConfig().GetGnsiConfig().GetAuthzUpload() == nil {
+		t.Errorf("controlCardView() modified the chassis: %v", chassis)
+	}
+}
+
+// TestReportStatusIDevID tests that a device presenting an IDevID whose organization differs from
+// the manufacturer of the inventory reports the status of its chassis.
+func TestReportStatusIDevID(t *testing.T) {
+	em, err := New("../../testdata/inventory.prototxt", nil)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	s := service.New(em)
+	idevid := &x509.Certificate{Subject: pkix.Name{SerialNumber: "123A", Organization: []string{"Cisco Systems, Inc."}}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{idevid}},
+		}},
+	})
+	for serial, wantCode := range map[string]codes.Code{"123B": codes.OK, "456": codes.PermissionDenied} {
+		_, err := s.ReportStatus(ctx, &bpb.ReportStatusRequest{
+			Status: bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+			States: []*bpb.ControlCardState{{SerialNumber: serial, Status: bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED}},
+		})
+		if got := status.Code(err); got != wantCode {
+			t.Errorf("ReportStatus() for %v err = %v, want code %v", serial, err, wantCode)
+		}
+	}
+	cs, err := em.GetStatus(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"})
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	if got := cs.ControlCards["123B"]; got != bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED {
+		t.Errorf("GetStatus() control card 123B = %v, want CONTROL_CARD_STATUS_INITIALIZED", got)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	em, err := New("../../testdata/inventory.prototxt", &service.SecurityArtifacts{TrustAnchor: &x509.Certificate{}})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	ctx := context.Background()
+	lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+	em.Audit(ctx, lookup, "123A", "nonce 01234567 rejected")
+	em.Audit(ctx, &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "unknown"}, "unknown", "nonce 01234567 accepted")
+	// A chassis which was never seen keeps no status.
+	if _, err := em.GetStatus(lookup); status.Code(err) != codes.NotFound {
+		t.Errorf("GetStatus() of an audited chassis err = %v, want NotFound", err)
+	}
+	if h := em.AuditHistory(lookup); len(h) != 1 || h[0].Message != "nonce 01234567 rejected" {
+		t.Errorf("AuditHistory() = %+v, want the audited decision of 123A", h)
+	}
+
+	em.Audit(ctx, lookup, "123A", "nonce 89abcdef accepted")
+	if _, err := em.GetBootstrapData(ctx, lookup, &bpb.ControlCard{SerialNumber: "123A"}); err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	em.Audit(ctx, lookup, "123A", "nonce 89abcdef rejected: replayed")
+	cs, err := em.GetStatus(lookup)
+	if err != nil {
+		t.Fatalf("GetStatus() err = %v", err)
+	}
+	var history []string
+	for _, h := range cs.History {
+		history = append(history, h.Message)
+	}
+	want := []string{"nonce 01234567 rejected", "nonce 89abcdef accepted", "bootstrap data served", "nonce 89abcdef rejected: replayed"}
+	if !cmp.Equal(history, want) || len(cs.ControlCardHistory["123A"]) != len(want) {
+		t.Errorf("GetStatus() history = %v, want %v", history, want)
+	}
+	if cs.Status != bpb.ReportStatusRequest_BOOTSTRAP_STATUS_UNSPECIFIED {
+		t.Errorf("GetStatus() status = %v, want unchanged", cs.Status)
+	}
+	if h := em.AuditHistory(lookup); len(h) != 0 {
+		t.Errorf("AuditHistory() of a seen chassis = %+v, want none", h)
+	}
+}
+
+func TestInventoryIndex(t *testing.T) {
+	em, err := New("", &service.SecurityArtifacts{}, WithOVGenerator(func(serial string) ([]byte, error) {
+		return []byte("ov-" + serial), nil
+	}))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	ctx := context.Background()
+	resolves := func(lookup *service.EntityLookup, ccSerial string) bool {
+		_, err := em.ResolveChassis(ctx, lookup, ccSerial)
+		return err == nil
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_INSECURE, "Cisco", "123")
+	lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+	if !resolves(lookup, "") {
+		t.Fatalf("ResolveChassis() of an added chassis failed")
+	}
+	if resolves(&service.EntityLookup{Manufacturer: "Arista", SerialNumber: "123"}, "") {
+		t.Errorf("ResolveChassis() resolved the chassis of another manufacturer")
+	}
+
+	if err := em.ReplaceDevice(lookup, &epb.Chassis{
+		Manufacturer: "Cisco",
+		SerialNumber: "123",
+		ControllerCards: []*epb.ControlCard{{
+			SerialNumber: "123A",
+			DhcpConfig:   &epb.DHCPConfig{HardwareAddress: "AA:BB:CC:00:00:01"},
+		}},
+	}); err != nil {
+		t.Fatalf("ReplaceDevice() err = %v", err)
+	}
+	if !resolves(&service.EntityLookup{Manufacturer: "Cisco"}, "123A") {
+		t.Errorf("ResolveChassis() by the control card of a replaced chassis failed")
+	}
+	if _, err := em.LookupByHardwareAddress("aa-bb-cc-00-00-01"); err != nil {
+		t.Errorf("LookupByHardwareAddress() err = %v", err)
+	}
+
+	if _, err := em.ReplaceControlCard(ctx, &ControlCardReplacement{OldSerial: "123A", NewSerial: "123C", HardwareAddress: "aa:bb:cc:00:00:02"}); err != nil {
+		t.Fatalf("ReplaceControlCard() err = %v", err)
+	}
+	if resolves(&service.EntityLookup{Manufacturer: "Cisco"}, "123A") || !resolves(&service.EntityLookup{Manufacturer: "Cisco"}, "123C") {
+		t.Errorf("ResolveChassis() by control card does not follow the replacement")
+	}
+	if _, err := em.LookupByHardwareAddress("aa:bb:cc:00:00:01"); status.Code(err) != codes.NotFound {
+		t.Errorf("LookupByHardwareAddress() of the replaced card err = %v, want NotFound", err)
+	}
+	if _, err := em.LookupByHardwareAddress("AABB.CC00.0002"); err != nil {
+		t.Errorf("LookupByHardwareAddress() of the replacement card err = %v", err)
+	}
+
+	em.DeleteDevice(lookup)
+	if resolves(lookup, "") || resolves(&service.EntityLookup{Manufacturer: "Cisco"}, "123C") {
+		t.Errorf("ResolveChassis() of a deleted chassis succeeded")
+	}
+	if _, err := em.LookupByHardwareAddress("aa:bb:cc:00:00:02"); status.Code(err) != codes.NotFound {
+		t.Errorf("LookupByHardwareAddress() of a deleted chassis err = %v, want NotFound", err)
+	}
+}
+
+// benchInventorySizes shows that lookups stay flat as the inventory grows.
+var benchInventorySizes = []int{1000, 10000, 100000}
+
+// newBenchEntityManager returns an entity manager with n modular chassis of two control cards.
+func newBenchEntityManager(b *testing.B, sa *service.SecurityArtifacts, n int) *InMemoryEntityManager {
+	b.Helper()
+	em, err := New("", sa)
+	if err != nil {
+		b.Fatalf("New() err = %v", err)
+	}
+	for i := 0; i < n; i++ {
+		serial := fmt.Sprintf("S%06d", i)
+		em.addChassis(&epb.Chassis{
+			Manufacturer: "Cisco",
+			SerialNumber: serial,
+			BootMode:     bpb.BootMode_BOOT_MODE_INSECURE,
+			Config: &epb.Config{
+				GnsiConfig: &epb.GNSIConfig{AuthzUpload: &apb.UploadRequest{Version: "v1", Policy: "{}"}},
+			},
+			ControllerCards: []*epb.ControlCard{{
+				SerialNumber: serial + "A",
+				DhcpConfig:   &epb.DHCPConfig{HardwareAddress: fmt.Sprintf("02:00:00:%02x:%02x:%02x", i>>16&0xff, i>>8&0xff, i&0xff)},
+			}, {
+				SerialNumber: serial + "B",
+			}},
+		})
+	}
+	return em
+}
+
+func TestArtifactCache(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile(%v) err = %v", path, err)
+		}
+		return path
+	}
+	digest := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+	ocA := write("a.json", `{"a": 1}`)
+	ocB := write("b.json", `{"a": 1}`)
+	sa, err := artifacts.GenerateSecurityArtifacts([]string{"123"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	em, err := New("", sa)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	em.chassisInventory = []*epb.Chassis{{
+		SerialNumber: "123",
+		Manufacturer: "Cisco",
+		Config: &epb.Config{
+			BootConfig: &epb.BootConfig{OcConfigFile: ocA},
+			GnsiConfig: &epb.GNSIConfig{AuthzUploadFile: "../../testdata/authz.prototext"},
+		},
+	}, {
+		SerialNumber: "456",
+		Manufacturer: "Cisco",
+		Config: &epb.Config{
+			BootConfig: &epb.BootConfig{OcConfigFile: ocB},
+			GnsiConfig: &epb.GNSIConfig{AuthzUploadFile: "../../testdata/authz.prototext"},
+		},
+	}}
+	em.index = newInventoryIndex(em.chassisInventory)
+	em.preloadArtifacts()
+	if got := len(em.artifacts.blobs); got != 2 {
+		t.Errorf("preloadArtifacts() stored %d blobs, want 2 for the shared OC config and the authz policy", got)
+	}
+
+	ctx := context.Background()
+	lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+	served := func() []ArtifactVersion {
+		t.Helper()
+		if _, err := em.GetBootstrapData(ctx, lookup, nil); err != nil {
+			t.Fatalf("GetBootstrapData() err = %v", err)
+		}
+		cs, err := em.GetStatus(lookup)
+		if err != nil {
+			t.Fatalf("GetStatus() err = %v", err)
+		}
+		return cs.ServedArtifacts["123"]
+	}
+	authz := readTextFromFile(t, "../../testdata/authz.prototext")
+	want := []ArtifactVersion{
+		{Kind: ArtifactOCConfig, Path: ocA, Digest: digest(`{"a": 1}`)},
+		{Kind: ArtifactAuthz, Path: "../../testdata/authz.prototext", Digest: digest(authz)},
+	}
+	if diff := cmp.Diff(want, served()); diff != "" {
+		t.Errorf("ServedArtifacts differ (-want +got):\n%s", diff)
+	}
+
+	// Modified files are reloaded.
+	write("a.json", `{"a": 2, "b": 3}`)
+	want[0].Digest = digest(`{"a": 2, "b": 3}`)
+	if diff := cmp.Diff(want, served()); diff != "" {
+		t.Errorf("ServedArtifacts after modification differ (-want +got):\n%s", diff)
+	}
+	if got := len(em.artifacts.blobs); got != 3 {
+		t.Errorf("artifact cache stores %d blobs, want 3", got)
+	}
+
+	// Invalid artifacts fail the request until they are fixed.
+	write("a.json", `{"a": `)
+	if _, err := em.GetBootstrapData(ctx, lookup, nil); err == nil || !strings.Contains(err.Error(), "not a valid json") {
+		t.Errorf("GetBootstrapData() with invalid OC config err = %v, want invalid json", err)
+	}
+	if got := len(em.artifacts.blobs); got != 2 {
+		t.Errorf("artifact cache stores %d blobs after invalidation, want 2", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	em, err := New("", &service.SecurityArtifacts{}, WithOVGenerator(func(serial string) ([]byte, error) {
+		return []byte("ov-" + serial), nil
+	}))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "123")
+	em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", "456")
+	em.chassisInventory[0].ControllerCards = []*epb.ControlCard{{SerialNumber: "123A", PartNumber: "PN"}}
+	em.index = newInventoryIndex(em.chassisInventory)
+
+	snap := em.Snapshot()
+	if snap.Version != 2 || len(snap.Chassis) != 2 {
+		t.Fatalf("Snapshot() = version %d with %d chassis, want version 2 with 2 chassis", snap.Version, len(snap.Chassis))
+	}
+	if got := em.Snapshot(); got != snap {
+		t.Errorf("Snapshot() of an unchanged inventory returned a new snapshot")
+	}
+
+	// Watchers are woken up by changes.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	watched := make(chan *Snapshot, 1)
+	go func() {
+		s, err := em.WatchSnapshot(ctx, snap.Version)
+		if err != nil {
+			t.Errorf("WatchSnapshot() err = %v", err)
+		}
+		watched <- s
+	}()
+	if _, err := em.ReplaceControlCard(ctx, &ControlCardReplacement{Manufacturer: "Cisco", OldSerial: "123A", NewSerial: "123B"}); err != nil {
+		t.Fatalf("ReplaceControlCard() err = %v", err)
+	}
+	next := <-watched
+	if next == nil || next.Version <= snap.Version {
+		t.Fatalf("WatchSnapshot() = %+v, want a newer version than %d", next, snap.Version)
+	}
+
+	// Snapshots are not affected by later changes, and unchanged chassis are shared.
+	if got := snap.Chassis[0].GetControllerCards()[0].GetSerialNumber(); got != "123A" {
+		t.Errorf("old snapshot control card = %v, want 123A", got)
+	}
+	if got := next.Chassis[0].GetControllerCards()[0].GetSerialNumber(); got != "123B" {
+		t.Errorf("new snapshot control card = %v, want 123B", got)
+	}
+	if next.Chassis[1] != snap.Chassis[1] {
+		t.Errorf("unchanged chassis was copied again")
+	}
+
+	em.DeleteDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "456"})
+	if got := em.Snapshot(); got.Version <= next.Version || len(got.Chassis) != 1 || len(next.Chassis) != 2 {
+		t.Errorf("Snapshot() after delete = version %d with %d chassis, previous snapshot has %d chassis", got.Version, len(got.Chassis), len(next.Chassis))
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := em.WatchSnapshot(cancelled, em.Snapshot().Version); err != context.Canceled {
+		t.Errorf("WatchSnapshot() with cancelled context err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSnapshotConcurrency(t *testing.T) {
+	em, err := New("", &service.SecurityArtifacts{})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			serial := fmt.Sprintf("S%d", i)
+			em.AddChassis(bpb.BootMode_BOOT_MODE_SECURE, "Cisco", serial)
+			if i%2 == 0 {
+				em.DeleteDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial})
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var last uint64
+		for i := 0; i < 200; i++ {
+			snap := em.Snapshot()
+			if snap.Version < last {
+				t.Errorf("Snapshot() version went from %d to %d", last, snap.Version)
+			}
+			last = snap.Version
+			for _, ch := range snap.Chassis {
+				_ = ch.GetSerialNumber()
+			}
+			_ = em.GetChassisInventory()
+		}
+	}()
+	wg.Wait()
+	if got := len(em.Snapshot().Chassis); got != 100 {
+		t.Errorf("Snapshot() has %d chassis, want 100", got)
+	}
+}
+
+func TestInventoryFormats(t *testing.T) {
+	want, err := LoadEntities("../../testdata/inventory.prototxt")
+	if err != nil {
+		t.Fatalf("LoadEntities() err = %v", err)
+	}
+	// Values which YAML would otherwise reinterpret, and integers which do not fit in a double.
+	want.Chassis = append(want.Chassis, &epb.Chassis{
+		SerialNumber: "0123",
+		Manufacturer: "true",
+		Config: &epb.Config{
+			GnsiConfig: &epb.GNSIConfig{
+				AuthzUpload: &apb.UploadRequest{Version: "1.10", CreatedOn: 1694813669807611349, Policy: "{}"},
+			},
+		},
+	})
+	formats := []Format{FormatPrototext, FormatJSON, FormatYAML}
+	for _, from := range formats {
+		data, err := MarshalEntities(want, from)
+		if err != nil {
+			t.Fatalf("MarshalEntities(%v) err = %v", from, err)
+		}
+		for _, to := range formats {
+			got, err := UnmarshalEntities(data, from)
+			if err != nil {
+				t.Fatalf("UnmarshalEntities(%v) err = %v\n%s", from, err, data)
+			}
+			out, err := MarshalEntities(got, to)
+			if err != nil {
+				t.Fatalf("MarshalEntities(%v) err = %v", to, err)
+			}
+			got, err = UnmarshalEntities(out, to)
+			if err != nil {
+				t.Fatalf("UnmarshalEntities(%v) err = %v\n%s", to, err, out)
+			}
+			if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("%v to %v conversion differs (-want +got):\n%s", from, to, diff)
+			}
+		}
+	}
+
+	dir := t.TempDir()
+	yml := filepath.Join(dir, "inventory.yaml")
+	if err := os.WriteFile(yml, []byte("chassis:\n  - serial_number: 123\n    manufacturer: Cisco\n    boot_mode: BOOT_MODE_INSECURE\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	em, err := New(yml, nil)
+	if err != nil {
+		t.Fatalf("New(%v) err = %v", yml, err)
+	}
+	if _, err := em.GetDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}); err != nil {
+		t.Errorf("GetDevice() from YAML inventory err = %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"chassis": [{"serial": "123"}]}`), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	if _, err := New(bad, nil); err == nil || !strings.Contains(err.Error(), "invalid json inventory") {
+		t.Errorf("New(%v) err = %v, want invalid json inventory", bad, err)
+	}
+}
+
+func TestLoadInventory(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("MkdirAll() err = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile(%v) err = %v", path, err)
+		}
+		return path
+	}
+	top := write("inventory.prototxt", `
+options { gnsi_global_config { authz_upload_file: "authz/global.prototext" } }
+chassis { serial_number: "100" manufacturer: "Cisco" }
+includes: "sites"
+includes: "extra/*.yaml"
+`)
+	write("sites/a.prototxt", `
+options { gnsi_global_config { authz_upload_file: "a_authz.prototext" } }
+chassis { serial_number: "200" manufacturer: "Cisco" }
+chassis {
+  serial_number: "201"
+  manufacturer: "Cisco"
+  config { gnsi_config { authz_upload_file: "/abs/authz.prototext" } boot_config { oc_config_file: "oc/201.json" } }
+}
+`)
+	write("sites/b.json", `{"chassis": [{"serial_number": "300", "manufacturer": "Cisco", "controller_cards": [{"serial_number": "300A"}]}]}`)
+	write("sites/README.md", "not an inventory")
+	write("extra/c.yaml", "chassis:\n  - serial_number: 400\n    manufacturer: Cisco\n")
+
+	got, err := loadInventory(top)
+	if err != nil {
+		t.Fatalf("loadInventory() err = %v", err)
+	}
+	want := &epb.Entities{
+		Options: &epb.Options{GnsiGlobalConfig: &epb.GNSIConfig{AuthzUploadFile: filepath.Join(dir, "authz/global.prototext")}},
+		Chassis: []*epb.Chassis{
+			{SerialNumber: "100", Manufacturer: "Cisco"},
+			{SerialNumber: "200", Manufacturer: "Cisco", Config: &epb.Config{
+				GnsiConfig: &epb.GNSIConfig{AuthzUploadFile: filepath.Join(dir, "sites/a_authz.prototext")},
+			}},
+			{SerialNumber: "201", Manufacturer: "Cisco", Config: &epb.Config{
+				GnsiConfig: &epb.GNSIConfig{AuthzUploadFile: "/abs/authz.prototext"},
+				BootConfig: &epb.BootConfig{OcConfigFile: filepath.Join(dir, "sites/oc/201.json")},
+			}},
+			{SerialNumber: "300", Manufacturer: "Cisco", ControllerCards: []*epb.ControlCard{{SerialNumber: "300A"}}},
+			{SerialNumber: "400", Manufacturer: "Cisco"},
+		},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("loadInventory() differs (-want +got):\n%s", diff)
+	}
+
+	errTests := []struct {
+		desc    string
+		files   map[string]string
+		wantErr []string
+	}{{
+		desc: "duplicate chassis",
+		files: map[string]string{
+			"dup/inventory.prototxt": `chassis { serial_number: "1" manufacturer: "Cisco" } includes: "other.prototxt"`,
+			"dup/other.prototxt":     `chassis { serial_number: "1" manufacturer: "Cisco" }`,
+		},
+		wantErr: []string{"other.prototxt", "chassis Cisco 1", "already defined in", "dup/inventory.prototxt"},
+	}, {
+		desc: "duplicate control card",
+		files: map[string]string{
+			"card/inventory.prototxt": `chassis { serial_number: "1" manufacturer: "Cisco" controller_cards { serial_number: "1A" } } includes: "other.prototxt"`,
+			"card/other.prototxt":     `chassis { serial_number: "2" manufacturer: "Cisco" controller_cards { serial_number: "1A" } }`,
+		},
+		wantErr: []string{"other.prototxt", "control card 1A of chassis Cisco 2"},
+	}, {
+		desc: "cycle",
+		files: map[string]string{
+			"cycle/inventory.prototxt": `includes: "other.prototxt"`,
+			"cycle/other.prototxt":     `includes: "inventory.prototxt"`,
+		},
+		wantErr: []string{"includes itself"},
+	}, {
+		desc: "missing include",
+		files: map[string]string{
+			"missing/inventory.prototxt": `includes: "other.prototxt"`,
+		},
+		wantErr: []string{"missing/inventory.prototxt", "invalid include"},
+	}, {
+		desc: "invalid included file",
+		files: map[string]string{
+			"invalid/inventory.prototxt": `includes: "other.yaml"`,
+			"invalid/other.yaml":         "chassis:\n  - serial: 1\n",
+		},
+		wantErr: []string{"invalid yaml inventory", "other.yaml"},
+	}, {
+		desc: "server option in included file",
+		files: map[string]string{
+			"options/inventory.prototxt": `includes: "other.prototxt"`,
+			"options/other.prototxt":     `options { artifact_dir: "certs" }`,
+		},
+		wantErr: []string{"options/other.prototxt", "artifact_dir is only supported in the top level inventory"},
+	}}
+	for _, tt := range errTests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var path string
+			for name, content := range tt.files {
+				p := write(name, content)
+				if strings.HasSuffix(name, "/inventory.prototxt") {
+					path = p
+				}
+			}
+			_, err := loadInventory(path)
+			if err == nil {
+				t.Fatalf("loadInventory() succeeded, want error")
+			}
+			for _, w := range tt.wantErr {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("loadInventory() err = %v, want it to contain %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkResolveChassis(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range benchInventorySizes {
+		em := newBenchEntityManager(b, nil, n)
+		b.Run(fmt.Sprintf("chassis=%d/by_serial", n), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: fmt.Sprintf("S%06d", i*7919%n)}
+					if _, err := em.ResolveChassis(ctx, lookup, ""); err != nil {
+						b.Fatalf("ResolveChassis() err = %v", err)
+					}
+				}
+			})
+		})
+		b.Run(fmt.Sprintf("chassis=%d/by_control_card", n), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					lookup := &service.EntityLookup{Manufacturer: "Cisco"}
+					if _, err := em.ResolveChassis(ctx, lookup, fmt.Sprintf("S%06dB", i*7919%n)); err != nil {
+						b.Fatalf("ResolveChassis() err = %v", err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetBootstrapData(b *testing.B) {
+	sa, err := artifacts.GenerateSecurityArtifacts([]string{"S000000A"}, "Google", "Cisco")
+	if err != nil {
+		b.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	ctx := context.Background()
+	for _, n := range benchInventorySizes {
+		em := newBenchEntityManager(b, sa, n)
+		b.Run(fmt.Sprintf("chassis=%d", n), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					serial := fmt.Sprintf("S%06d", i*7919%n)
+					lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial}
+					if _, err := em.GetBootstrapData(ctx, lookup, &bpb.ControlCard{SerialNumber: serial + "A"}); err != nil {
+						b.Fatalf("GetBootstrapData() err = %v", err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	const spec = `
+# procurement export
+serial_number=Serial, part_number=PN, manufacturer=Vendor
+name={Site}-{Serial}
+boot_mode=Mode
+controller_cards.0.serial_number=RP0
+controller_cards.0.dhcp_config.hardware_address=RP0 MAC
+controller_cards.1.serial_number=RP1
+`
+	tests := []struct {
+		desc    string
+		spec    string
+		csv     string
+		want    []*epb.Chassis
+		wantErr string
+	}{{
+		desc: "mapping",
+		spec: spec,
+		csv:  "Serial,PN,Vendor,Site,Mode,RP0,RP0 MAC,RP1\n900,8808,Cisco,lab1,secure,900A,00:00:5E:00:53:01,900B\n901,8201,Cisco,lab2,,901A,,\n",
+		want: []*epb.Chassis{{
+			Name:         "lab1-900",
+			SerialNumber: "900",
+			PartNumber:   "8808",
+			Manufacturer: "Cisco",
+			BootMode:     bpb.BootMode_BOOT_MODE_SECURE,
+			ControllerCards: []*epb.ControlCard{
+				{SerialNumber: "900A", DhcpConfig: &epb.DHCPConfig{HardwareAddress: "00:00:5E:00:53:01"}},
+				{SerialNumber: "900B"},
+			},
+		}, {
+			Name:            "lab2-901",
+			SerialNumber:    "901",
+			PartNumber:      "8201",
+			Manufacturer:    "Cisco",
+			ControllerCards: []*epb.ControlCard{{SerialNumber: "901A"}},
+		}},
+	}, {
+		desc: "header paths",
+		csv:  "serial_number,dhcp_config.hardware_address,provisional\n902,00:00:5E:00:53:02,true\n",
+		want: []*epb.Chassis{{
+			SerialNumber: "902",
+			DhcpConfig:   &epb.DHCPConfig{HardwareAddress: "00:00:5E:00:53:02"},
+			Provisional:  true,
+		}},
+	}, {
+		desc:    "missing column",
+		spec:    "serial_number=Serial,name={Site}",
+		csv:     "Serial\n900\n",
+		wantErr: `column "Site" of field name is not in the CSV header`,
+	}, {
+		desc:    "invalid enum",
+		spec:    spec,
+		csv:     "Serial,PN,Vendor,Site,Mode,RP0,RP0 MAC,RP1\n900,8808,Cisco,lab1,fast,,,\n",
+		wantErr: `line 2: invalid boot_mode "fast"`,
+	}, {
+		desc:    "duplicate serial",
+		spec:    spec,
+		csv:     "Serial,PN,Vendor,Site,Mode,RP0,RP0 MAC,RP1\n900,,,,,900A,,\n901,,,,,900A,,\n",
+		wantErr: "line 3: serial 900A is already imported on line 2",
+	}, {
+		desc:    "no serial",
+		csv:     "name\nlab1\n",
+		wantErr: "line 2: neither a chassis nor a control card serial is set",
+	}, {
+		desc:    "unknown header field",
+		csv:     "serial\n900\n",
+		wantErr: `Chassis has no field "serial"`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			var mapping *ColumnMapping
+			if test.spec != "" {
+				var err error
+				if mapping, err = ParseColumnMapping(test.spec); err != nil {
+					t.Fatalf("ParseColumnMapping() err = %v", err)
+				}
+			}
+			got, err := ImportCSV(strings.NewReader(test.csv), mapping)
+			if s := errdiff.Substring(err, test.wantErr); s != "" {
+				t.Fatalf("ImportCSV() %s", s)
+			}
+			if diff := cmp.Diff(test.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("ImportCSV() differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	for spec, wantErr := range map[string]string{
+		"serial_number":                    "want <field>=<column>",
+		"serial_number=A,serial_number=B":  "mapped more than once",
+		"controller_cards.serial_number=A": `invalid index "serial_number"`,
+		"dhcp_config=A":                    "dhcp_config is a message",
+		"":                                 "empty column mapping",
+	} {
+		if _, err := ParseColumnMapping(spec); errdiff.Substring(err, wantErr) != "" {
+			t.Errorf("ParseColumnMapping(%q) err = %v, want %q", spec, err, wantErr)
+		}
+	}
+}
+
+func TestMergeImport(t *testing.T) {
+	entities := &epb.Entities{
+		Chassis: []*epb.Chassis{{
+			Name:            "core1",
+			SerialNumber:    "123",
+			Manufacturer:    "Cisco",
+			PartNumber:      "8808",
+			ControllerCards: []*epb.ControlCard{{SerialNumber: "123A", PartNumber: "RP"}},
+		}},
+		Profiles: []*epb.Profile{{
+			Name:          "default",
+			BootMode:      bpb.BootMode_BOOT_MODE_INSECURE,
+			SoftwareImage: &bpb.SoftwareImage{Version: "1.0"},
+		}},
+	}
+	imported := []*epb.Chassis{{
+		SerialNumber:    "900",
+		Manufacturer:    "Cisco",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "900A"}},
+	}, {
+		ControllerCards: []*epb.ControlCard{
+			{SerialNumber: "123A", DhcpConfig: &epb.DHCPConfig{HardwareAddress: "00:00:5E:00:53:01"}},
+			{SerialNumber: "123B"},
+		},
+	}}
+	if _, err := MergeImport(entities, imported, "missing"); errdiff.Substring(err, `profile "missing" not found`) != "" {
+		t.Errorf("MergeImport() with unknown profile err = %v", err)
+	}
+	if _, err := MergeImport(entities, append(imported, &epb.Chassis{SerialNumber: "900"}), ""); errdiff.Substring(err, "serial 900 is imported more than once") != "" {
+		t.Errorf("MergeImport() with duplicate serials err = %v", err)
+	}
+
+	changes, err := MergeImport(entities, imported, "default")
+	if err != nil {
+		t.Fatalf("MergeImport() err = %v", err)
+	}
+	added := &epb.Chassis{
+		Name:            "900",
+		SerialNumber:    "900",
+		Manufacturer:    "Cisco",
+		BootMode:        bpb.BootMode_BOOT_MODE_INSECURE,
+		SoftwareImage:   &bpb.SoftwareImage{Version: "1.0"},
+		Profile:         "default",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "900A"}},
+	}
+	updated := &epb.Chassis{
+		Name:          "core1",
+		SerialNumber:  "123",
+		Manufacturer:  "Cisco",
+		PartNumber:    "8808",
+		BootMode:      bpb.BootMode_BOOT_MODE_INSECURE,
+		SoftwareImage: &bpb.SoftwareImage{Version: "1.0"},
+		Profile:       "default",
+		ControllerCards: []*epb.ControlCard{
+			{SerialNumber: "123A", PartNumber: "RP", DhcpConfig: &epb.DHCPConfig{HardwareAddress: "00:00:5E:00:53:01"}},
+			{SerialNumber: "123B"},
+		},
+	}
+	want := []ImportChange{{After: added}, {Before: &epb.Chassis{
+		Name:            "core1",
+		SerialNumber:    "123",
+		Manufacturer:    "Cisco",
+		PartNumber:      "8808",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "123A", PartNumber: "RP"}},
+	}, After: updated}}
+	if diff := cmp.Diff(want, changes, protocmp.Transform()); diff != "" {
+		t.Errorf("MergeImport() changes differ (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*epb.Chassis{updated, added}, entities.GetChassis(), protocmp.Transform()); diff != "" {
+		t.Errorf("MergeImport() inventory differs (-want +got):\n%s", diff)
+	}
+
+	// Importing the same chassis again changes nothing.
+	changes, err = MergeImport(entities, imported, "default")
+	if err != nil || len(changes) != 0 {
+		t.Errorf("MergeImport() again = %v, %v, want no changes", changes, err)
+	}
+}
+
+func TestImportChassis(t *testing.T) {
+	tests := []struct {
+		desc    string
+		chassis *epb.Chassis
+		wantErr string
+	}{{
+		desc:    "No manufacturer",
+		chassis: &epb.Chassis{SerialNumber: "900"},
+		wantErr: "has no manufacturer",
+	}, {
+		desc: "Missing config file",
+		chassis: &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "900", Config: &epb.Config{
+			BootConfig: &epb.BootConfig{VendorConfigFile: "missing.cfg"},
+		}},
+		wantErr: "missing.cfg",
+	}, {
+		desc: "Missing password file",
+		chassis: &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "900", BootloaderPassword: &epb.BootloaderPassword{
+			Source: &epb.BootloaderPassword_PasswordFile{PasswordFile: "missing.txt"},
+		}},
+		wantErr: "invalid bootloader password of chassis Cisco/900",
+	}, {
+		desc: "Generated password without escrow",
+		chassis: &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "900", BootloaderPassword: &epb.BootloaderPassword{
+			Source: &epb.BootloaderPassword_Generate{Generate: true},
+		}},
+		wantErr: "require a password escrow",
+	}, {
+		desc: "Unknown role",
+		chassis: &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "900", Config: &epb.Config{
+			GnsiConfig: &epb.GNSIConfig{Roles: []string{"missing"}},
+		}},
+		wantErr: `unknown role "missing"`,
+	}, {
+		desc: "Valid chassis",
+		chassis: &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "900", ControllerCards: []*epb.ControlCard{
+			{SerialNumber: "900A"}, {SerialNumber: "900B"},
+		}},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			em, err := New("../../testdata/inventory.prototxt", &service.SecurityArtifacts{OV: service.OVList{"900B": []byte("known")}},
+				WithOVGenerator(func(serial string) ([]byte, error) { return []byte("ov-" + serial), nil }))
+			if err != nil {
+				t.Fatalf("New() err = %v", err)
+			}
+			for _, dryRun := range []bool{true, false} {
+				_, err := em.ImportChassis([]*epb.Chassis{test.chassis}, "", dryRun)
+				if s := errdiff.Substring(err, test.wantErr); s != "" {
+					t.Fatalf("ImportChassis(dryRun=%v) %s", dryRun, s)
+				}
+				if err != nil && status.Code(err) != codes.InvalidArgument {
+					t.Errorf("ImportChassis(dryRun=%v) err = %v, want InvalidArgument", dryRun, err)
+				}
+			}
+			_, lookupErr := em.GetDevice(&service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "900"})
+			if got, want := lookupErr == nil, test.wantErr == ""; got != want {
+				t.Errorf("GetDevice() after import err = %v, want imported %v", lookupErr, want)
+			}
+			if test.wantErr != "" {
+				return
+			}
+			want := service.OVList{"900A": []byte("ov-900A"), "900B": []byte("known")}
+			if diff := cmp.Diff(want, em.secArtifacts.OV); diff != "" {
+				t.Errorf("ImportChassis() ownership vouchers differ (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBootloaderPassword(t *testing.T) {
+	a, err := artifacts.GenerateSecurityArtifacts([]string{"1A", "2A", "3A", "4A"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("Failed to generate security artifacts: %v", err)
+	}
+	authz, err := filepath.Abs("../../testdata/authz.prototext")
+	if err != nil {
+		t.Fatalf("Abs() err = %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "password.txt"), []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	key := bytes.Repeat([]byte{7}, secrets.KeySize)
+	store, err := secrets.Open(filepath.Join(dir, "secrets.json"), key, secrets.SecretStore)
+	if err != nil {
+		t.Fatalf("secrets.Open() err = %v", err)
+	}
+	if err := store.Set("core", "from-store"); err != nil {
+		t.Fatalf("Set() err = %v", err)
+	}
+	escrowPath := filepath.Join(dir, "escrow.json")
+	openEscrow := func() *secrets.Store {
+		s, err := secrets.Open(escrowPath, key, secrets.PasswordEscrow)
+		if err != nil {
+			t.Fatalf("secrets.Open() err = %v", err)
+		}
+		return s
+	}
+	inventory := filepath.Join(dir, "inventory.prototxt")
+	writeInventory := func(chassis string) {
+		t.Helper()
+		inv := fmt.Sprintf("options { gnsi_global_config { authz_upload_file: %q } }\n%s", authz, chassis)
+		if err := os.WriteFile(inventory, []byte(inv), 0o600); err != nil {
+			t.Fatalf("WriteFile() err = %v", err)
+		}
+	}
+	writeInventory(`
+chassis { serial_number: "1" manufacturer: "Cisco" bootloader_password { password_file: "password.txt" } controller_cards { serial_number: "1A" } }
+chassis { serial_number: "2" manufacturer: "Cisco" bootloader_password { secret: "core" scheme: PASSWORD_HASH_SCHEME_SHA256_CRYPT } controller_cards { serial_number: "2A" } }
+chassis { serial_number: "3" manufacturer: "Cisco" bootloader_password { generate: true } controller_cards { serial_number: "3A" } }
+chassis { serial_number: "4" manufacturer: "Cisco" bootloader_password { generate: true } controller_cards { serial_number: "4A" bootloader_password_hash: "CARDHASH" } }
+`)
+
+	serve := func(em *InMemoryEntityManager, serial string) string {
+		t.Helper()
+		resp, err := em.GetBootstrapData(context.Background(), &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial}, &bpb.ControlCard{SerialNumber: serial + "A"})
+		if err != nil {
+			t.Fatalf("GetBootstrapData(%v) err = %v", serial, err)
+		}
+		return resp.GetBootPasswordHash()
+	}
+	em, err := New(inventory, a, WithSecretStore(store), WithPasswordEscrow(openEscrow()))
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if got := serve(em, "1"); !strings.HasPrefix(got, "$6$") || !secrets.Verify(got, "from-file") {
+		t.Errorf("hash of the password file = %q, want the SHA-512 crypt of from-file", got)
+	}
+	if got := serve(em, "2"); !strings.HasPrefix(got, "$5$") || !secrets.Verify(got, "from-store") {
+		t.Errorf("hash of the secret = %q, want the SHA-256 crypt of from-store", got)
+	}
+	first := serve(em, "1")
+	if again := serve(em, "1"); again != first {
+		t.Errorf("hash served again = %q, want the first hash %q", again, first)
+	}
+	generated, ok := openEscrow().Get("Cisco/3")
+	if !ok || len(generated) != generatedPasswordLength {
+		t.Fatalf("escrowed password of chassis 3 = %q, %v, want a generated password", generated, ok)
+	}
+	if got := serve(em, "3"); !secrets.Verify(got, generated) {
+		t.Errorf("hash of the generated password = %q, does not match the escrowed password", got)
+	}
+	if got := serve(em, "4"); got != "CARDHASH" {
+		t.Errorf("hash of the control card = %q, want the override CARDHASH", got)
+	}
+
+	// Generated passwords are reused from the escrow.
+	em, err = New(inventory, a, WithSecretStore(store), WithPasswordEscrow(openEscrow()))
+	if err != nil {
+		t.Fatalf("New() again err = %v", err)
+	}
+	if got := serve(em, "3"); !secrets.Verify(got, generated) {
+		t.Errorf("hash of the generated password after a restart = %q, does not match the escrowed password", got)
+	}
+
+	for _, test := range []struct {
+		desc    string
+		chassis string
+		wantErr string
+	}{{
+		desc:    "missing secret",
+		chassis: `chassis { serial_number: "5" manufacturer: "Cisco" bootloader_password { secret: "missing" } }`,
+		wantErr: `invalid bootloader password of chassis Cisco/5: secret "missing" not found`,
+	}, {
+		desc:    "missing file",
+		chassis: `chassis { serial_number: "5" manufacturer: "Cisco" bootloader_password { password_file: "missing.txt" } }`,
+		wantErr: "missing.txt",
+	}, {
+		desc:    "profile without escrow",
+		chassis: `profiles { name: "spares" bootloader_password { generate: true } }`,
+		wantErr: `invalid bootloader password of profile "spares": generated passwords require a password escrow`,
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			writeInventory(test.chassis)
+			_, err := New(inventory, a, WithSecretStore(store))
+			if s := errdiff.Substring(err, test.wantErr); s != "" {
+				t.Errorf("New() %s", s)
+			}
+		})
+	}
+}
+
+// authorizedKeyLine returns a new ed25519 SSH public key in authorized_keys format.
+func authorizedKeyLine(t *testing.T, comment string) string {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() err = %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("NewPublicKey() err = %v", err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + comment
+}
+
+func TestCredentials(t *testing.T) {
+	a, err := artifacts.GenerateSecurityArtifacts([]string{"1A", "2A"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("Failed to generate security artifacts: %v", err)
+	}
+	authz, err := filepath.Abs("../../testdata/authz.prototext")
+	if err != nil {
+		t.Fatalf("Abs() err = %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "password.txt"), []byte("viewer-password\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	opsKey := authorizedKeyLine(t, "ops@example.com")
+	chassisKey := authorizedKeyLine(t, "break-glass")
+	backupKey := authorizedKeyLine(t, "backup")
+	if err := os.WriteFile(filepath.Join(dir, "creds.prototxt"), []byte(fmt.Sprintf(`
+credentials { credentials { account: "backup" authorized_keys { authorized_key: %q key_type: KEY_TYPE_ED25519 } } }
+`, strings.TrimSuffix(backupKey, " backup"))), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	rootHash, err := secrets.Crypt(secrets.SHA512Crypt, "root-password")
+	if err != nil {
+		t.Fatalf("Crypt() err = %v", err)
+	}
+	inventory := filepath.Join(dir, "inventory.prototxt")
+	writeInventory := func(inv string) {
+		t.Helper()
+		if err := os.WriteFile(inventory, []byte(inv), 0o600); err != nil {
+			t.Fatalf("WriteFile() err = %v", err)
+		}
+	}
+	options := func(roles string) string {
+		return fmt.Sprintf(`options {
+  gnsi_global_config { authz_upload_file: %q roles: "netops" users { account: "root" password_hash: %q } }
+  roles { name: "netops" users { account: "admin" authorized_keys: %q authorized_principals: "ops" } }
+  %s
+}
+`, authz, rootHash, opsKey, roles)
+	}
+	writeInventory(options(`roles { name: "audit" users { account: "admin" authorized_principals: "auditor" } users { account: "viewer" password_file: "password.txt" } }`) + fmt.Sprintf(`
+chassis {
+  serial_number: "1" manufacturer: "Cisco" controller_cards { serial_number: "1A" }
+  config { gnsi_config { roles: "audit" users { account: "admin" authorized_keys: %q } } }
+}
+chassis {
+  serial_number: "2" manufacturer: "Cisco" controller_cards { serial_number: "2A" }
+  config { gnsi_config { credentials_file: "creds.prototxt" } }
+}
+`, `from="10.0.0.0/8",no-pty `+chassisKey))
+
+	em, err := New(inventory, a)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	serve := func(serial string) *bpb.Credentials {
+		t.Helper()
+		resp, err := em.GetBootstrapData(context.Background(), &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: serial}, &bpb.ControlCard{SerialNumber: serial + "A"})
+		if err != nil {
+			t.Fatalf("GetBootstrapData(%v) err = %v", serial, err)
+		}
+		return resp.GetCredentials()
+	}
+	key := func(line string, opts ...*cpb.Option) *cpb.AccountCredentials_AuthorizedKey {
+		fields := strings.Fields(line)
+		return &cpb.AccountCredentials_AuthorizedKey{
+			AuthorizedKey: []byte(fields[0] + " " + fields[1]),
+			KeyType:       cpb.KeyType_KEY_TYPE_ED25519,
+			Description:   fields[2],
+			Options:       opts,
+		}
+	}
+	principals := func(account string, names ...string) *cpb.UserPolicy {
+		p := &cpb.UserPolicy{Account: account, AuthorizedPrincipals: &cpb.UserPolicy_SshAuthorizedPrincipals{}}
+		for _, n := range names {
+			p.AuthorizedPrincipals.AuthorizedPrincipals = append(p.AuthorizedPrincipals.AuthorizedPrincipals, &cpb.UserPolicy_SshAuthorizedPrincipal{AuthorizedUser: n})
+		}
+		return p
+	}
+	password := func(account, hash string) *cpb.PasswordRequest_Account {
+		return &cpb.PasswordRequest_Account{
+			Account: account,
+			Password: &cpb.PasswordRequest_Password{
+				Value: &cpb.PasswordRequest_Password_CryptoHash{
+					CryptoHash: &cpb.PasswordRequest_CryptoHash{HashType: cpb.PasswordRequest_CryptoHash_HASH_TYPE_CRYPT_SHA_2_512, HashValue: hash},
+				},
+			},
+		}
+	}
+	ignoreVersions := cmp.Options{
+		protocmp.Transform(),
+		protocmp.IgnoreFields(&cpb.AccountCredentials{}, "version"),
+		protocmp.IgnoreFields(&cpb.UserPolicy{}, "version"),
+		protocmp.IgnoreFields(&cpb.PasswordRequest_Account{}, "version"),
+	}
+
+	got := serve("1")
+	viewer := got.GetPasswords()[0].GetAccounts()[1].GetPassword().GetCryptoHash()
+	if !secrets.Verify(viewer.GetHashValue(), "viewer-password") {
+		t.Errorf("hash of the viewer password = %q, want the SHA-512 crypt of the password file", viewer.GetHashValue())
+	}
+	want := &bpb.Credentials{
+		Credentials: []*cpb.AuthorizedKeysRequest{{Credentials: []*cpb.AccountCredentials{{
+			Account: "admin",
+			AuthorizedKeys: []*cpb.AccountCredentials_AuthorizedKey{
+				key(opsKey),
+				key(chassisKey,
+					&cpb.Option{Key: &cpb.Option_Id{Id: cpb.Option_STANDARD_OPTION_FROM}, Value: "10.0.0.0/8"},
+					&cpb.Option{Key: &cpb.Option_Id{Id: cpb.Option_STANDARD_OPTION_NO_PTY}}),
+			},
+		}}}},
+		Users: []*cpb.AuthorizedUsersRequest{{Policies: []*cpb.UserPolicy{principals("admin", "ops", "auditor")}}},
+		Passwords: []*cpb.PasswordRequest{{Accounts: []*cpb.PasswordRequest_Account{
+			password("root", rootHash),
+			password("viewer", viewer.GetHashValue()),
+		}}},
+	}
+	if diff := cmp.Diff(want, got, ignoreVersions); diff != "" {
+		t.Errorf("credentials of chassis 1 differ (-want +got):\n%s", diff)
+	}
+	if again := serve("1"); !proto.Equal(again, got) {
+		t.Errorf("credentials served again = %v, want the first credentials %v", again, got)
+	}
+	if v := got.GetCredentials()[0].GetCredentials()[0].GetVersion(); v == "" {
+		t.Errorf("version of the admin credentials is empty")
+	}
+
+	want = &bpb.Credentials{
+		Credentials: []*cpb.AuthorizedKeysRequest{
+			{Credentials: []*cpb.AccountCredentials{{Account: "backup", AuthorizedKeys: []*cpb.AccountCredentials_AuthorizedKey{{
+				AuthorizedKey: []byte(strings.TrimSuffix(backupKey, " backup")),
+				KeyType:       cpb.KeyType_KEY_TYPE_ED25519,
+			}}}}},
+			{Credentials: []*cpb.AccountCredentials{{Account: "admin", AuthorizedKeys: []*cpb.AccountCredentials_AuthorizedKey{key(opsKey)}}}},
+		},
+		Users:     []*cpb.AuthorizedUsersRequest{{Policies: []*cpb.UserPolicy{principals("admin", "ops")}}},
+		Passwords: []*cpb.PasswordRequest{{Accounts: []*cpb.PasswordRequest_Account{password("root", rootHash)}}},
+	}
+	if diff := cmp.Diff(want, serve("2"), ignoreVersions); diff != "" {
+		t.Errorf("credentials of chassis 2 differ (-want +got):\n%s", diff)
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("GenerateKey() err = %v", err)
+	}
+	rsaPub, err := ssh.NewPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("NewPublicKey() err = %v", err)
+	}
+	for _, test := range []struct {
+		desc    string
+		inv     string
+		wantErr string
+	}{{
+		desc:    "invalid key",
+		inv:     options(`roles { name: "audit" users { account: "admin" authorized_keys: "ssh-ed25519 AAAA" } }`),
+		wantErr: `invalid user of role "audit": account "admin": authorized key 1`,
+	}, {
+		desc:    "unsupported key size",
+		inv:     options(fmt.Sprintf(`roles { name: "audit" users { account: "admin" authorized_keys: %q } }`, ssh.MarshalAuthorizedKey(rsaPub))),
+		wantErr: "unsupported RSA key size 1024",
+	}, {
+		desc:    "unknown key option",
+		inv:     options(fmt.Sprintf(`roles { name: "audit" users { account: "admin" authorized_keys: %q } }`, "bogus-option "+opsKey)),
+		wantErr: `unknown key option "bogus-option"`,
+	}, {
+		desc:    "unknown role",
+		inv:     options("") + `chassis { serial_number: "1" manufacturer: "Cisco" config { gnsi_config { roles: "missing" } } }`,
+		wantErr: `invalid users of chassis Cisco/1: unknown role "missing"`,
+	}, {
+		desc:    "duplicate role",
+		inv:     options(`roles { name: "netops" }`),
+		wantErr: `invalid role "netops": duplicate name`,
+	}, {
+		desc:    "unsupported password hash",
+		inv:     options("") + `chassis { serial_number: "1" manufacturer: "Cisco" config { gnsi_config { users { account: "admin" password_hash: "$5$salt$hash" } } } }`,
+		wantErr: `invalid user of chassis Cisco/1: account "admin": unsupported password hash`,
+	}, {
+		desc:    "missing password secret",
+		inv:     options(`roles { name: "audit" users { account: "viewer" password_secret: "viewer" } }`),
+		wantErr: `secret "viewer" requires a secret store`,
+	}, {
+		desc:    "missing account",
+		inv:     options(`roles { name: "audit" users { authorized_principals: "ops" } }`),
+		wantErr: "missing account",
+	}} {
+		t.Run(test.desc, func(t *testing.T) {
+			writeInventory(test.inv)
+			_, err := New(inventory, a)
+			if s := errdiff.Substring(err, test.wantErr); s != "" {
+				t.Errorf("New() %s", s)
+			}
+		})
+	}
+}
+
+func TestCertIdentity(t *testing.T) {
+	ch := &epb.Chassis{
+		Manufacturer:    "Cisco",
+		SerialNumber:    "123",
+		Name:            "core1.example.com",
+		DhcpConfig:      &epb.DHCPConfig{IpAddress: "192.0.2.10/24"},
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "123A", DhcpConfig: &epb.DHCPConfig{IpAddress: "2001:db8::1"}}, {SerialNumber: "123B"}},
+	}
+	tests := []struct {
+		desc    string
+		ch      *epb.Chassis
+		serial  string
+		want    certs.Identity
+		wantErr string
+	}{{
+		desc:   "card address",
+		ch:     ch,
+		serial: "123A",
+		want:   certs.Identity{Manufacturer: "Cisco", Serial: "123A", Hostname: "core1.example.com", IPs: []net.IP{net.ParseIP("2001:db8::1")}},
+	}, {
+		desc:   "chassis address",
+		ch:     ch,
+		serial: "123B",
+		want:   certs.Identity{Manufacturer: "Cisco", Serial: "123B", Hostname: "core1.example.com", IPs: []net.IP{net.ParseIP("192.0.2.10").To4()}},
+	}, {
+		desc:   "no address",
+		ch:     &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "456"},
+		serial: "456",
+		want:   certs.Identity{Manufacturer: "Cisco", Serial: "456"},
+	}, {
+		desc:    "invalid address",
+		ch:      &epb.Chassis{Manufacturer: "Cisco", SerialNumber: "456", DhcpConfig: &epb.DHCPConfig{IpAddress: "core1"}},
+		serial:  "456",
+		wantErr: `invalid management IP "core1"`,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := certIdentity(test.ch, test.serial)
+			if s := errdiff.Substring(err, test.wantErr); s != "" {
+				t.Fatalf("certIdentity() %s", s)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("certIdentity() differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
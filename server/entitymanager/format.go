@@ -0,0 +1,247 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v3"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// Format is the encoding of an inventory file.
+type Format string
+
+// Supported inventory formats.
+const (
+	FormatPrototext Format = "prototext"
+	FormatJSON      Format = "json"
+	FormatYAML      Format = "yaml"
+)
+
+// ParseFormat returns the format with the provided name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatPrototext, FormatJSON, FormatYAML:
+		return f, nil
+	case "textproto", "prototxt", "txtpb":
+		return FormatPrototext, nil
+	case "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unknown inventory format %q, want one of prototext, json or yaml", name)
+}
+
+// FormatOf returns the format of an inventory file from its extension. Files with other
+// extensions are prototext, the original inventory format.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatPrototext
+}
+
+// UnmarshalEntities decodes an inventory in the provided format. Unknown fields are rejected in
+// all formats.
+func UnmarshalEntities(data []byte, f Format) (*epb.Entities, error) {
+	entities := &epb.Entities{}
+	switch f {
+	case FormatPrototext:
+		if err := prototext.Unmarshal(data, entities); err != nil {
+			return nil, err
+		}
+	case FormatJSON:
+		if err := protojson.Unmarshal(data, entities); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		js, err := yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := protojson.Unmarshal(js, entities); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown inventory format %q", f)
+	}
+	return entities, nil
+}
+
+// MarshalEntities encodes an inventory in the provided format. JSON and YAML inventories use the
+// field names of the proto definition, as prototext inventories do.
+func MarshalEntities(entities *epb.Entities, f Format) ([]byte, error) {
+	switch f {
+	case FormatPrototext:
+		return prototext.MarshalOptions{Multiline: true, Indent: "    "}.Marshal(entities)
+	case FormatJSON:
+		js, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(entities)
+		if err != nil {
+			return nil, err
+		}
+		return append(js, '\n'), nil
+	case FormatYAML:
+		js, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(entities)
+		if err != nil {
+			return nil, err
+		}
+		return jsonToYAML(js)
+	}
+	return nil, fmt.Errorf("unknown inventory format %q", f)
+}
+
+// LoadEntities reads an inventory file, in the format given by its extension.
+func LoadEntities(path string) (*epb.Entities, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entities, err := UnmarshalEntities(data, FormatOf(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v inventory %s: %v", FormatOf(path), path, err)
+	}
+	return entities, nil
+}
+
+// yamlToJSON converts a YAML inventory to JSON. Scalars keep the text they have in the
+// document, and the scalars of string fields are strings even if YAML would read them as another
+// type, so that serials such as 123 do not need to be quoted.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return []byte("{}"), nil
+	}
+	v, err := yamlValue(doc.Content[0], (&epb.Entities{}).ProtoReflect().Descriptor(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// yamlValue converts a YAML node to a JSON value. md is the message the node encodes, and fd the
+// field it is the value of, if known.
+func yamlValue(n *yaml.Node, md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) (any, error) {
+	// Well known types have their own JSON mapping, and are converted without a schema.
+	if md != nil && strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		md, fd = nil, nil
+	}
+	switch n.Kind {
+	case yaml.AliasNode:
+		return yamlValue(n.Alias, md, fd)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			if k.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: keys must be scalars", k.Line)
+			}
+			var cmd protoreflect.MessageDescriptor
+			var cfd protoreflect.FieldDescriptor
+			switch {
+			case fd != nil && fd.IsMap():
+				cfd = fd.MapValue()
+				cmd = cfd.Message()
+			case md != nil:
+				if cfd = md.Fields().ByName(protoreflect.Name(k.Value)); cfd == nil {
+					cfd = md.Fields().ByJSONName(k.Value)
+				}
+				if cfd != nil {
+					cmd = cfd.Message()
+				}
+			}
+			val, err := yamlValue(v, cmd, cfd)
+			if err != nil {
+				return nil, err
+			}
+			m[k.Value] = val
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		l := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			val, err := yamlValue(c, md, fd)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, val)
+		}
+		return l, nil
+	case yaml.ScalarNode:
+		if n.ShortTag() == "!!null" {
+			return nil, nil
+		}
+		if fd != nil && (fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind) {
+			return n.Value, nil
+		}
+		switch n.ShortTag() {
+		case "!!bool":
+			var b bool
+			if err := n.Decode(&b); err != nil {
+				return nil, err
+			}
+			return b, nil
+		case "!!int", "!!float":
+			// Numbers are passed through as written, so that 64 bit integers are not rounded.
+			if json.Valid([]byte(n.Value)) {
+				return json.Number(n.Value), nil
+			}
+		}
+		return n.Value, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", n.Line)
+}
+
+// jsonToYAML converts a JSON document to block style YAML, keeping the order of fields.
+func jsonToYAML(js []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(js, &doc); err != nil {
+		return nil, err
+	}
+	resetStyle(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle clears the flow and quoting styles JSON documents are parsed with. Strings which
+// would otherwise be read back as another type are still quoted by the encoder.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	log "github.com/golang/glog"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// ImportChange is a chassis added or updated by an import.
+type ImportChange struct {
+	// Before is the chassis before the import, or nil if the chassis was added.
+	Before *epb.Chassis
+	// After is the chassis after the import.
+	After *epb.Chassis
+}
+
+// chassisKeys returns the serials of the chassis and its control cards.
+func chassisKeys(ch *epb.Chassis) []string {
+	var keys []string
+	seen := map[string]bool{}
+	add := func(s string) {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			keys = append(keys, s)
+		}
+	}
+	add(ch.GetSerialNumber())
+	for _, cc := range ch.GetControllerCards() {
+		add(cc.GetSerialNumber())
+	}
+	return keys
+}
+
+// findImported returns the chassis of the inventory an imported chassis refers to: the chassis
+// with the same serial, or with one of its control cards.
+func findImported(inventory []*epb.Chassis, ch *epb.Chassis) *epb.Chassis {
+	for _, o := range inventory {
+		if ch.GetManufacturer() != "" && o.GetManufacturer() != "" && ch.GetManufacturer() != o.GetManufacturer() {
+			continue
+		}
+		if ch.GetSerialNumber() != "" && ch.GetSerialNumber() == o.GetSerialNumber() {
+			return o
+		}
+		for _, cc := range ch.GetControllerCards() {
+			if cc.GetSerialNumber() != "" && findCard(o, cc.GetSerialNumber()) != nil {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+// findCard returns the control card of the chassis with the provided serial, or nil.
+func findCard(ch *epb.Chassis, serial string) *epb.ControlCard {
+	for _, cc := range ch.GetControllerCards() {
+		if cc.GetSerialNumber() == serial {
+			return cc
+		}
+	}
+	return nil
+}
+
+// applyProfile returns the chassis configured from the profile, keeping the fields set on the
+// chassis. The result shares the fields of the chassis.
+func applyProfile(ch *epb.Chassis, p *epb.Profile) *epb.Chassis {
+	out := chassisFromProfile(p, "", "", "", nil)
+	out.Name = ""
+	replaceFields(out.ProtoReflect(), ch.ProtoReflect())
+	out.ControllerCards = ch.GetControllerCards()
+	return out
+}
+
+// replaceFields sets the fields of dst to the fields set in src, except for the control cards of
+// chassis.
+func replaceFields(dst, src protoreflect.Message) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.ContainingMessage() == chassisDescriptor && fd.Name() == "controller_cards" {
+			return true
+		}
+		dst.Set(fd, v)
+		return true
+	})
+}
+
+// planImport returns the changes of the inventory which import the chassis: chassis which are
+// not in the inventory are added, named after their serial if they have no name, and the fields
+// set on the other chassis and their control cards replace those of the inventory. Chassis are
+// configured from the profile, if any. The inventory is left unchanged.
+func planImport(inventory []*epb.Chassis, imported []*epb.Chassis, p *epb.Profile) ([]ImportChange, error) {
+	var changes []ImportChange
+	seen := map[string]bool{}
+	matched := map[*epb.Chassis]bool{}
+	for _, ch := range imported {
+		keys := chassisKeys(ch)
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("chassis %q has neither a chassis nor a control card serial", ch.GetName())
+		}
+		for _, k := range keys {
+			if seen[k] {
+				return nil, fmt.Errorf("serial %s is imported more than once", k)
+			}
+			seen[k] = true
+		}
+		ch = proto.Clone(ch).(*epb.Chassis)
+		if p != nil {
+			ch = applyProfile(ch, p)
+		}
+		before := findImported(inventory, ch)
+		if before == nil {
+			if ch.GetName() == "" {
+				ch.Name = keys[0]
+			}
+			changes = append(changes, ImportChange{After: ch})
+			continue
+		}
+		if matched[before] {
+			return nil, fmt.Errorf("chassis %s is imported more than once", before.GetName())
+		}
+		matched[before] = true
+		after := proto.Clone(before).(*epb.Chassis)
+		replaceFields(after.ProtoReflect(), ch.ProtoReflect())
+		for _, cc := range ch.GetControllerCards() {
+			if old := findCard(after, cc.GetSerialNumber()); old != nil {
+				replaceFields(old.ProtoReflect(), cc.ProtoReflect())
+				continue
+			}
+			after.ControllerCards = append(after.ControllerCards, cc)
+		}
+		if !proto.Equal(before, after) {
+			changes = append(changes, ImportChange{Before: before, After: after})
+		}
+	}
+	return changes, nil
+}
+
+// MergeImport merges imported chassis into an inventory, configured from the inventory profile
+// with the provided name if it is not empty, and returns the changes of the inventory. Chassis
+// which are not in the inventory are appended to it.
+func MergeImport(entities *epb.Entities, imported []*epb.Chassis, profile string) ([]ImportChange, error) {
+	var p *epb.Profile
+	if profile != "" {
+		for _, o := range entities.GetProfiles() {
+			if o.GetName() == profile {
+				p = o
+			}
+		}
+		if p == nil {
+			return nil, fmt.Errorf("profile %q not found", profile)
+		}
+	}
+	changes, err := planImport(entities.GetChassis(), imported, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		if c.Before == nil {
+			entities.Chassis = append(entities.Chassis, c.After)
+			continue
+		}
+		for i, ch := range entities.Chassis {
+			if ch == c.Before {
+				entities.Chassis[i] = c.After
+			}
+		}
+	}
+	return changes, nil
+}
+
+// MergeImportFile merges imported chassis into the inventory file at path like MergeImport, and
+// returns the entities of the file along with the changes. Chassis are matched across the files
+// the inventory includes, but only the file itself is changed: importing a chassis defined in an
+// included file fails, and the profile must be defined in the file.
+func MergeImportFile(path string, imported []*epb.Chassis, profile string) (*epb.Entities, []ImportChange, error) {
+	l, err := newInventoryLoader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ch := range imported {
+		if o := findImported(l.entities.GetChassis(), ch); o != nil && l.files[o] != path {
+			return nil, nil, fmt.Errorf("chassis %s is defined in the included file %s, which imports do not change", o.GetName(), l.files[o])
+		}
+	}
+	if f, ok := l.profileFiles[profile]; ok && f != path {
+		return nil, nil, fmt.Errorf("profile %q is defined in the included file %s, import from the file itself", profile, f)
+	}
+	entities, err := LoadEntities(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := MergeImport(entities, imported, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entities, changes, nil
+}
+
+// ImportChassis merges the chassis into the inventory, configured from the named profile if one
+// is provided, and returns the changes. The imported chassis are validated like the inventory
+// loaded by New, and ownership vouchers are generated for their serials which have none. With
+// dryRun, the changes are only validated and returned. Discovered devices which are added to the
+// inventory are no longer listed as discovered.
+func (m *InMemoryEntityManager) ImportChassis(chassis []*epb.Chassis, profile string, dryRun bool) ([]ImportChange, error) {
+	var p *epb.Profile
+	if profile != "" {
+		var err error
+		if p, err = m.GetProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	m.mu.Lock()
+	changes, err := planImport(m.chassisInventory, chassis, p)
+	var serials []string
+	for _, c := range changes {
+		var cards []string
+		for _, cc := range c.After.GetControllerCards() {
+			cards = append(cards, cc.GetSerialNumber())
+		}
+		serials = append(serials, m.missingOVs(c.After.GetSerialNumber(), cards)...)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := m.checkImported(changes, dryRun); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	out := make([]ImportChange, len(changes))
+	for i, c := range changes {
+		out[i].After = proto.Clone(c.After).(*epb.Chassis)
+		if c.Before != nil {
+			out[i].Before = proto.Clone(c.Before).(*epb.Chassis)
+		}
+	}
+	if dryRun || len(changes) == 0 {
+		return out, nil
+	}
+	ovs, err := m.generateOVs(serials)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Chassis are replaced rather than modified, so the import applies as planned as long as it
+	// changes the same chassis.
+	current, err := planImport(m.chassisInventory, chassis, p)
+	if err != nil || !sameChanges(changes, current) {
+		return nil, status.Errorf(codes.Aborted, "the inventory changed during the import, retry it")
+	}
+	for _, c := range changes {
+		if c.Before == nil {
+			m.addChassis(c.After)
+		} else {
+			m.replaceChassis(m.position(c.Before), c.After)
+		}
+	}
+	m.updateOVs(ovs)
+	for _, d := range append([]*DiscoveredDevice{}, m.discovered...) {
+		if m.inInventory(d) {
+			m.removeDiscovered(d)
+		}
+	}
+	log.Infof("Imported %d chassis into the inventory", len(changes))
+	return out, nil
+}
+
+// checkImported validates the chassis added or updated by an import: they must have a
+// manufacturer, and valid artifacts, bootloader password and credentials. Generated passwords are
+// escrowed unless dryRun is set.
+func (m *InMemoryEntityManager) checkImported(changes []ImportChange, dryRun bool) error {
+	creds, err := m.newCredentialsChecker()
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		ch := c.After
+		if ch.GetManufacturer() == "" {
+			return fmt.Errorf("chassis %s has no manufacturer", ch.GetName())
+		}
+		if err := m.checkArtifacts(ch); err != nil {
+			return fmt.Errorf("chassis %s: %v", ch.GetName(), status.Convert(err).Message())
+		}
+		if dryRun {
+			err = m.passwords.checkPassword(ch)
+		} else {
+			_, err = m.passwords.bootloaderHash(ch)
+		}
+		if err != nil {
+			return err
+		}
+		if err := creds.chassis(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameChanges reports whether the changes update the same chassis the same way.
+func sameChanges(a, b []ImportChange) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Before != b[i].Before || !proto.Equal(a[i].After, b[i].After) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,337 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// inventoryExtensions are the extensions of the files loaded from included directories.
+var inventoryExtensions = map[string]bool{
+	".prototxt":  true,
+	".prototext": true,
+	".textproto": true,
+	".txtpb":     true,
+	".json":      true,
+	".yaml":      true,
+	".yml":       true,
+}
+
+// inventoryLoader loads an inventory file and the files it includes.
+type inventoryLoader struct {
+	entities *epb.Entities
+	// loaded holds the absolute paths of the files loaded so far.
+	loaded map[string]bool
+	// chassisFiles and cardFiles map the serials of chassis and control cards to the file which
+	// defines them, to detect duplicates across files.
+	chassisFiles map[string]string
+	cardFiles    map[string]string
+	profileFiles map[string]string
+	// files maps the chassis of the inventory to the file which defines them.
+	files map[*epb.Chassis]string
+}
+
+// loadInventory loads the inventory file at path along with the files it includes. Relative
+// file references in each file are resolved against the directory of the file, and the options of
+// included files are applied to their chassis and profiles. The options of the top level file are
+// the options of the inventory.
+func loadInventory(path string) (*epb.Entities, error) {
+	l, err := newInventoryLoader(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.entities, nil
+}
+
+// newInventoryLoader returns the loader of the inventory file at path, once it loaded the file
+// along with the files it includes.
+func newInventoryLoader(path string) (*inventoryLoader, error) {
+	l := &inventoryLoader{
+		entities:     &epb.Entities{},
+		loaded:       map[string]bool{},
+		chassisFiles: map[string]string{},
+		cardFiles:    map[string]string{},
+		profileFiles: map[string]string{},
+		files:        map[*epb.Chassis]string{},
+	}
+	if err := l.load(path, nil, nil); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// load loads the file at path. stack holds the files including it, and scope the options of the
+// included files it is nested in.
+func (l *inventoryLoader) load(path string, stack []string, scope *epb.Options) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, s := range stack {
+		if s == abs {
+			return fmt.Errorf("inventory %s includes itself through %s", path, strings.Join(stack, " -> "))
+		}
+	}
+	if l.loaded[abs] {
+		return fmt.Errorf("inventory %s is included more than once (from %s)", path, stack[len(stack)-1])
+	}
+	l.loaded[abs] = true
+
+	entities, err := LoadEntities(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	rewritePaths(entities.ProtoReflect(), func(p string) string {
+		return resolvePath(dir, p)
+	})
+
+	if len(stack) == 0 {
+		l.entities.Options = entities.GetOptions()
+	} else {
+		if err := checkIncludedOptions(entities.GetOptions()); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		scope = mergeOptions(scope, entities.GetOptions())
+		// Roles are defined once for the whole inventory, wherever they are declared.
+		if roles := entities.GetOptions().GetRoles(); len(roles) > 0 {
+			if l.entities.Options == nil {
+				l.entities.Options = &epb.Options{}
+			}
+			l.entities.Options.Roles = append(l.entities.Options.Roles, roles...)
+		}
+	}
+	for _, ch := range entities.GetChassis() {
+		if err := l.checkChassis(path, ch); err != nil {
+			return err
+		}
+		applyOptions(scope, ch.GetConfig(), func(c *epb.Config) { ch.Config = c })
+		l.entities.Chassis = append(l.entities.Chassis, ch)
+		l.files[ch] = path
+	}
+	if err := validateProfiles(entities.GetProfiles()); err != nil {
+		return fmt.Errorf("%s: invalid profiles: %v", path, err)
+	}
+	for _, p := range entities.GetProfiles() {
+		if other, ok := l.profileFiles[p.GetName()]; ok && other != path {
+			return fmt.Errorf("%s: profile %q is already defined in %s", path, p.GetName(), other)
+		}
+		l.profileFiles[p.GetName()] = path
+		applyOptions(scope, p.GetConfig(), func(c *epb.Config) { p.Config = c })
+		l.entities.Profiles = append(l.entities.Profiles, p)
+	}
+
+	stack = append(stack, abs)
+	for _, inc := range entities.GetIncludes() {
+		files, err := expandInclude(inc)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		for _, f := range files {
+			if err := l.load(f, stack, scope); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkChassis fails if the chassis or one of its control cards is already defined in another
+// file. Duplicates within a file are left to the inventory, as they were before includes.
+func (l *inventoryLoader) checkChassis(path string, ch *epb.Chassis) error {
+	if s := ch.GetSerialNumber(); s != "" {
+		key := ch.GetManufacturer() + "/" + s
+		if other, ok := l.chassisFiles[key]; ok && other != path {
+			return fmt.Errorf("%s: chassis %s %s is already defined in %s", path, ch.GetManufacturer(), s, other)
+		}
+		l.chassisFiles[key] = path
+	}
+	for _, cc := range ch.GetControllerCards() {
+		s := cc.GetSerialNumber()
+		if s == "" {
+			continue
+		}
+		if other, ok := l.cardFiles[s]; ok && other != path {
+			return fmt.Errorf("%s: control card %s of chassis %s %s is already defined in %s", path, s, ch.GetManufacturer(), ch.GetSerialNumber(), other)
+		}
+		l.cardFiles[s] = path
+	}
+	return nil
+}
+
+// expandInclude returns the inventory files an include refers to: the file itself, the inventory
+// files of a directory, or the files and directories matching a glob pattern, in lexical order.
+// Patterns may match no file, but files and directories must exist.
+func expandInclude(inc string) ([]string, error) {
+	var paths []string
+	if strings.ContainsAny(inc, "*?[") {
+		matches, err := filepath.Glob(inc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %v", inc, err)
+		}
+		paths = matches
+	} else {
+		paths = []string{inc}
+	}
+	var files []string
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include %q: %v", inc, err)
+		}
+		if !fi.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include %q: %v", inc, err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && inventoryExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+				files = append(files, filepath.Join(p, e.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// resolvePath resolves a relative path against dir.
+func resolvePath(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// RebasePaths rewrites the relative file references of an inventory read from a file in fromDir,
+// so that they refer to the same files from an inventory file in toDir.
+func RebasePaths(entities *epb.Entities, fromDir, toDir string) error {
+	from, err := filepath.Abs(fromDir)
+	if err != nil {
+		return err
+	}
+	to, err := filepath.Abs(toDir)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	var rebaseErr error
+	rewritePaths(entities.ProtoReflect(), func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		rel, err := filepath.Rel(to, filepath.Join(from, p))
+		if err != nil {
+			rebaseErr = err
+			return p
+		}
+		return rel
+	})
+	return rebaseErr
+}
+
+// rewritePaths replaces the file references of an inventory message with the result of f. File
+// references are the includes of inventories and the string fields named *_file or *_dir.
+func rewritePaths(m protoreflect.Message, f func(string) string) {
+	var paths []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+		switch {
+		case fd.IsMap():
+		case fd.Message() != nil && fd.IsList():
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				rewritePaths(l.Get(i).Message(), f)
+			}
+		case fd.Message() != nil:
+			rewritePaths(v.Message(), f)
+		case fd.Kind() != protoreflect.StringKind:
+		case fd.IsList() && fd.ContainingMessage().FullName() == entitiesName && name == "includes":
+			l := v.List()
+			for i := 0; i < l.Len(); i++ {
+				l.Set(i, protoreflect.ValueOfString(f(l.Get(i).String())))
+			}
+		case !fd.IsList() && (strings.HasSuffix(name, "_file") || strings.HasSuffix(name, "_dir")):
+			paths = append(paths, fd)
+		}
+		return true
+	})
+	// Fields are only set once the iteration is over.
+	for _, fd := range paths {
+		m.Set(fd, protoreflect.ValueOfString(f(m.Get(fd).String())))
+	}
+}
+
+var entitiesName = (&epb.Entities{}).ProtoReflect().Descriptor().FullName()
+
+// checkIncludedOptions fails if the options of an included file set options which only apply to
+// the whole inventory.
+func checkIncludedOptions(opts *epb.Options) error {
+	if opts.GetBootzserver() != "" {
+		return errors.New("option bootzserver is only supported in the top level inventory")
+	}
+	if opts.GetArtifactDir() != "" {
+		return errors.New("option artifact_dir is only supported in the top level inventory")
+	}
+	return nil
+}
+
+// mergeOptions returns the gNSI options of an included file nested in the provided scope. The
+// options of the file take precedence.
+func mergeOptions(scope, opts *epb.Options) *epb.Options {
+	if opts == nil {
+		return scope
+	}
+	merged := &epb.Options{}
+	if scope != nil {
+		merged = proto.Clone(scope).(*epb.Options)
+	}
+	if opts.GetGnsiGlobalConfig() != nil {
+		if merged.GnsiGlobalConfig == nil {
+			merged.GnsiGlobalConfig = &epb.GNSIConfig{}
+		}
+		mergeGNSIConfig(merged.GnsiGlobalConfig, opts.GetGnsiGlobalConfig())
+	}
+	return merged
+}
+
+// applyOptions applies the gNSI config of the options to a chassis or profile config, unless the
+// config sets the same policies. set stores the resulting config.
+func applyOptions(opts *epb.Options, conf *epb.Config, set func(*epb.Config)) {
+	if opts.GetGnsiGlobalConfig() == nil {
+		return
+	}
+	gnsi := proto.Clone(opts.GetGnsiGlobalConfig()).(*epb.GNSIConfig)
+	mergeGNSIConfig(gnsi, conf.GetGnsiConfig())
+	if conf == nil {
+		conf = &epb.Config{}
+		set(conf)
+	}
+	conf.GnsiConfig = gnsi
+}
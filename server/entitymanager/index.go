@@ -0,0 +1,169 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// inventoryIndex indexes the chassis of the inventory by their identifiers. Each key maps to the
+// chassis carrying it, in the order they were indexed, so that duplicate identifiers resolve to
+// the same chassis as a scan of the inventory would.
+type inventoryIndex struct {
+	// bySerial maps chassis serials to chassis.
+	bySerial map[string][]*epb.Chassis
+	// byControlCard maps control card serials to the chassis holding the card.
+	byControlCard map[string][]*epb.Chassis
+	// byHardwareAddress maps the normalized hardware addresses in the DHCP config of chassis and
+	// control cards to the chassis.
+	byHardwareAddress map[string][]*epb.Chassis
+}
+
+func newInventoryIndex(inventory []*epb.Chassis) *inventoryIndex {
+	x := &inventoryIndex{
+		bySerial:          map[string][]*epb.Chassis{},
+		byControlCard:     map[string][]*epb.Chassis{},
+		byHardwareAddress: map[string][]*epb.Chassis{},
+	}
+	for _, ch := range inventory {
+		x.add(ch)
+	}
+	return x
+}
+
+// keys calls f with each index and key of the chassis.
+func (x *inventoryIndex) keys(ch *epb.Chassis, f func(idx map[string][]*epb.Chassis, key string)) {
+	if s := ch.GetSerialNumber(); s != "" {
+		f(x.bySerial, s)
+	}
+	if a := normalizeHardwareAddress(ch.GetDhcpConfig().GetHardwareAddress()); a != "" {
+		f(x.byHardwareAddress, a)
+	}
+	for _, cc := range ch.GetControllerCards() {
+		if s := cc.GetSerialNumber(); s != "" {
+			f(x.byControlCard, s)
+		}
+		if a := normalizeHardwareAddress(cc.GetDhcpConfig().GetHardwareAddress()); a != "" {
+			f(x.byHardwareAddress, a)
+		}
+	}
+}
+
+// add indexes the chassis.
+func (x *inventoryIndex) add(ch *epb.Chassis) {
+	x.keys(ch, func(idx map[string][]*epb.Chassis, key string) {
+		for _, o := range idx[key] {
+			if o == ch {
+				return
+			}
+		}
+		idx[key] = append(idx[key], ch)
+	})
+}
+
+// remove drops the chassis from the index. It must be called before the identifiers of an indexed
+// chassis are modified.
+func (x *inventoryIndex) remove(ch *epb.Chassis) {
+	x.keys(ch, func(idx map[string][]*epb.Chassis, key string) {
+		list := idx[key]
+		for i, o := range list {
+			if o == ch {
+				list = append(list[:i:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(idx, key)
+		} else {
+			idx[key] = list
+		}
+	})
+}
+
+// first returns the first chassis of the list from the manufacturer. An empty manufacturer matches
+// any chassis if anyManufacturer is set.
+func first(list []*epb.Chassis, manufacturer string, anyManufacturer bool) *epb.Chassis {
+	for _, ch := range list {
+		if ch.GetManufacturer() == manufacturer || (anyManufacturer && manufacturer == "") {
+			return ch
+		}
+	}
+	return nil
+}
+
+// normalizeHardwareAddress returns the canonical form of a MAC address, so that addresses match
+// regardless of case and separators. Addresses which do not parse are only lower cased.
+func normalizeHardwareAddress(addr string) string {
+	if addr == "" {
+		return ""
+	}
+	if hw, err := net.ParseMAC(addr); err == nil {
+		return hw.String()
+	}
+	return strings.ToLower(addr)
+}
+
+// addChassis appends the chassis to the inventory. The caller must hold m.mu.
+func (m *InMemoryEntityManager) addChassis(ch *epb.Chassis) {
+	m.chassisInventory = append(m.chassisInventory, ch)
+	m.index.add(ch)
+	m.inventoryChanged(ch)
+}
+
+// replaceChassis replaces the chassis at position i of the inventory. The caller must hold m.mu.
+func (m *InMemoryEntityManager) replaceChassis(i int, ch *epb.Chassis) {
+	old := m.chassisInventory[i]
+	m.index.remove(old)
+	m.chassisInventory[i] = ch
+	m.index.add(ch)
+	m.inventoryChanged(old, ch)
+}
+
+// removeChassis removes the chassis at position i of the inventory. The caller must hold m.mu.
+func (m *InMemoryEntityManager) removeChassis(i int) {
+	old := m.chassisInventory[i]
+	m.index.remove(old)
+	m.chassisInventory = append(m.chassisInventory[:i], m.chassisInventory[i+1:]...)
+	m.inventoryChanged(old)
+}
+
+// position returns the position of the chassis in the inventory, or -1. The caller must hold m.mu.
+func (m *InMemoryEntityManager) position(ch *epb.Chassis) int {
+	for i, o := range m.chassisInventory {
+		if o == ch {
+			return i
+		}
+	}
+	return -1
+}
+
+// LookupByHardwareAddress returns a copy of the chassis with the provided hardware address in the
+// DHCP config of the chassis or of one of its control cards.
+func (m *InMemoryEntityManager) LookupByHardwareAddress(addr string) (*epb.Chassis, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := m.index.byHardwareAddress[normalizeHardwareAddress(addr)]
+	if len(list) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no chassis with hardware address %q", addr)
+	}
+	return proto.Clone(list[0]).(*epb.Chassis), nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// controlCardView returns a copy of the chassis with the overrides of the control card with the
+// provided serial applied, which is the configuration served to that card. If the chassis has no
+// such card, the copy is unchanged.
+func controlCardView(ch *epb.Chassis, serial string) *epb.Chassis {
+	view := proto.Clone(ch).(*epb.Chassis)
+	var cc *epb.ControlCard
+	for _, c := range ch.GetControllerCards() {
+		if c.GetSerialNumber() == serial {
+			cc = c
+			break
+		}
+	}
+	if cc == nil {
+		return view
+	}
+	if cc.GetSoftwareImage() != nil {
+		view.SoftwareImage = proto.Clone(cc.GetSoftwareImage()).(*bpb.SoftwareImage)
+	}
+	if cc.GetBootloaderPasswordHash() != "" {
+		// The hash of the card also replaces the password of the chassis.
+		view.BootloaderPasswordHash = cc.GetBootloaderPasswordHash()
+		view.BootloaderPassword = nil
+	}
+	if cc.GetBootConfig() == nil && cc.GetGnsiConfig() == nil {
+		return view
+	}
+	if view.Config == nil {
+		view.Config = &epb.Config{}
+	}
+	if cc.GetBootConfig() != nil {
+		if view.Config.BootConfig == nil {
+			view.Config.BootConfig = &epb.BootConfig{}
+		}
+		// Merging replaces the set files and the metadata and bootloader config keys.
+		proto.Merge(view.Config.BootConfig, cc.GetBootConfig())
+	}
+	if cc.GetGnsiConfig() != nil {
+		if view.Config.GnsiConfig == nil {
+			view.Config.GnsiConfig = &epb.GNSIConfig{}
+		}
+		mergeGNSIConfig(view.Config.GnsiConfig, proto.Clone(cc.GetGnsiConfig()).(*epb.GNSIConfig))
+	}
+	return view
+}
+
+// mergeGNSIConfig replaces each policy of dst which is set in src, either inline or as a file.
+// The inline policy and the file of a policy are replaced together, since the inline policy
+// takes precedence over the file.
+func mergeGNSIConfig(dst, src *epb.GNSIConfig) {
+	if src.GetAuthzUpload() != nil || src.GetAuthzUploadFile() != "" {
+		dst.AuthzUpload = src.GetAuthzUpload()
+		dst.AuthzUploadFile = src.GetAuthzUploadFile()
+	}
+	if src.GetPathzUpload() != nil || src.GetPathzUploadFile() != "" {
+		dst.PathzUpload = src.GetPathzUpload()
+		dst.PathzUploadFile = src.GetPathzUploadFile()
+	}
+	if src.GetCertzUpload() != nil || src.GetCertzUploadFile() != "" {
+		dst.CertzUpload = src.GetCertzUpload()
+		dst.CertzUploadFile = src.GetCertzUploadFile()
+	}
+	if src.GetCredentials() != nil || src.GetCredentialsFile() != "" {
+		dst.Credentials = src.GetCredentials()
+		dst.CredentialsFile = src.GetCredentialsFile()
+	}
+	// Users and roles add to those of the chassis.
+	dst.Users = append(dst.Users, src.GetUsers()...)
+	dst.Roles = append(dst.Roles, src.GetRoles()...)
+}
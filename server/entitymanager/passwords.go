@@ -0,0 +1,225 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/openconfig/bootz/server/secrets"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// generatedPasswordLength is the number of characters of generated bootloader passwords.
+const generatedPasswordLength = 20
+
+// passwordHasher computes the hashes of the bootloader and user passwords of the inventory. Hashes are
+// salted, so they are computed once per chassis and password, and reused afterwards. The zero
+// value hashes passwords read from files only.
+type passwordHasher struct {
+	// store holds the secrets passwords may refer to.
+	store *secrets.Store
+	// escrow records generated passwords by chassis.
+	escrow *secrets.Store
+
+	mu     sync.Mutex
+	hashes map[passwordKey]string
+}
+
+// passwordKey identifies a password of a chassis.
+type passwordKey struct {
+	chassis string
+	// password is the encoding of the password source.
+	password string
+}
+
+// WithSecretStore lets bootloader passwords refer to the secrets of the store.
+func WithSecretStore(s *secrets.Store) Option {
+	return func(m *InMemoryEntityManager) {
+		m.passwords.store = s
+	}
+}
+
+// WithPasswordEscrow records the bootloader passwords generated for chassis to the store, and
+// reuses the passwords already recorded there.
+func WithPasswordEscrow(s *secrets.Store) Option {
+	return func(m *InMemoryEntityManager) {
+		m.passwords.escrow = s
+	}
+}
+
+// escrowName returns the name of the escrowed password of a chassis, <manufacturer>/<serial>.
+func escrowName(ch *epb.Chassis) string {
+	serial := ch.GetSerialNumber()
+	if serial == "" && len(ch.GetControllerCards()) > 0 {
+		serial = ch.GetControllerCards()[0].GetSerialNumber()
+	}
+	return ch.GetManufacturer() + "/" + serial
+}
+
+// bootloaderHash returns the bootloader password hash to serve to the chassis: the hash of its
+// bootloader password if it has one, or else its precomputed hash.
+func (h *passwordHasher) bootloaderHash(ch *epb.Chassis) (string, error) {
+	pw := ch.GetBootloaderPassword()
+	if pw == nil {
+		return ch.GetBootloaderPasswordHash(), nil
+	}
+	src, err := proto.MarshalOptions{Deterministic: true}.Marshal(pw)
+	if err != nil {
+		return "", err
+	}
+	scheme := secrets.SHA512Crypt
+	if pw.GetScheme() == epb.PasswordHashScheme_PASSWORD_HASH_SCHEME_SHA256_CRYPT {
+		scheme = secrets.SHA256Crypt
+	}
+	key := passwordKey{chassis: escrowName(ch), password: string(src)}
+	hash, err := h.hash(key, scheme, func() (string, error) { return h.password(ch, pw) })
+	if err != nil {
+		return "", fmt.Errorf("invalid bootloader password of chassis %s: %v", key.chassis, err)
+	}
+	return hash, nil
+}
+
+// checkPassword checks that the bootloader password of the chassis can be hashed. Generated
+// passwords are only checked for an escrow, so that they are not escrowed for chassis which are
+// not in the inventory.
+func (h *passwordHasher) checkPassword(ch *epb.Chassis) error {
+	if ch.GetBootloaderPassword().GetGenerate() {
+		if h.escrow == nil {
+			return fmt.Errorf("invalid bootloader password of chassis %s: generated passwords require a password escrow", escrowName(ch))
+		}
+		return nil
+	}
+	_, err := h.bootloaderHash(ch)
+	return err
+}
+
+// hash returns the hash of the password identified by key, computing it from the plaintext
+// returned by password the first time. password is called with h.mu held.
+func (h *passwordHasher) hash(key passwordKey, scheme secrets.Scheme, password func() (string, error)) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if hash, ok := h.hashes[key]; ok {
+		return hash, nil
+	}
+	plain, err := password()
+	if err != nil {
+		return "", err
+	}
+	hash, err := secrets.Crypt(scheme, plain)
+	if err != nil {
+		return "", err
+	}
+	if h.hashes == nil {
+		h.hashes = map[passwordKey]string{}
+	}
+	h.hashes[key] = hash
+	return hash, nil
+}
+
+// readPasswordFile returns the password held by the file, without trailing newlines.
+func readPasswordFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	password := strings.TrimRight(string(data), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("password file %s is empty", path)
+	}
+	return password, nil
+}
+
+// secret returns the secret of the secret store with the provided name.
+func (h *passwordHasher) secret(name string) (string, error) {
+	if h.store == nil {
+		return "", fmt.Errorf("secret %q requires a secret store", name)
+	}
+	v, ok := h.store.Get(name)
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	return v, nil
+}
+
+// password returns the plaintext of the bootloader password of the chassis. The caller must hold
+// h.mu.
+func (h *passwordHasher) password(ch *epb.Chassis, pw *epb.BootloaderPassword) (string, error) {
+	switch src := pw.GetSource().(type) {
+	case *epb.BootloaderPassword_PasswordFile:
+		return readPasswordFile(src.PasswordFile)
+	case *epb.BootloaderPassword_Secret:
+		return h.secret(src.Secret)
+	case *epb.BootloaderPassword_Generate:
+		if !src.Generate {
+			break
+		}
+		if h.escrow == nil {
+			return "", errors.New("generated passwords require a password escrow")
+		}
+		name := escrowName(ch)
+		if password, ok := h.escrow.Get(name); ok {
+			return password, nil
+		}
+		password, err := secrets.RandomString(generatedPasswordLength)
+		if err != nil {
+			return "", err
+		}
+		if err := h.escrow.Set(name, password); err != nil {
+			return "", fmt.Errorf("unable to escrow the generated password: %v", err)
+		}
+		log.Infof("Generated the bootloader password of chassis %v", name)
+		return password, nil
+	}
+	return "", errors.New("no password source")
+}
+
+// preloadPasswords hashes the bootloader passwords of the inventory, so that missing secrets are
+// reported when the inventory is loaded, and generated passwords are escrowed before they are
+// served.
+func (m *InMemoryEntityManager) preloadPasswords() error {
+	for _, ch := range m.chassisInventory {
+		if _, err := m.passwords.bootloaderHash(ch); err != nil {
+			return err
+		}
+	}
+	// Passwords of profiles are hashed for each chassis, but their files and secrets are
+	// checked now. Generated passwords are checked for an escrow.
+	h := &m.passwords
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, p := range m.profiles {
+		pw := p.GetBootloaderPassword()
+		if pw == nil {
+			continue
+		}
+		if pw.GetGenerate() {
+			if h.escrow == nil {
+				return fmt.Errorf("invalid bootloader password of profile %q: generated passwords require a password escrow", p.GetName())
+			}
+			continue
+		}
+		if _, err := h.password(nil, pw); err != nil {
+			return fmt.Errorf("invalid bootloader password of profile %q: %v", p.GetName(), err)
+		}
+	}
+	return nil
+}
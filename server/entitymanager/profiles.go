@@ -0,0 +1,210 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// DefaultProvisionalTTL is the default time a provisional chassis is kept in the inventory without
+// being approved.
+const DefaultProvisionalTTL = 24 * time.Hour
+
+// WithProvisionalTTL sets how long a chassis provisioned from a fallback profile is kept in the
+// inventory without being approved.
+func WithProvisionalTTL(d time.Duration) Option {
+	return func(m *InMemoryEntityManager) {
+		m.provisionalTTL = d
+	}
+}
+
+// validateProfiles checks that profile names are unique and that fallback patterns are valid.
+func validateProfiles(profiles []*epb.Profile) error {
+	names := map[string]bool{}
+	for _, p := range profiles {
+		if p.GetName() == "" {
+			return fmt.Errorf("profile without a name")
+		}
+		if names[p.GetName()] {
+			return fmt.Errorf("duplicate profile %q", p.GetName())
+		}
+		names[p.GetName()] = true
+		for _, f := range p.GetFallback() {
+			if f.GetManufacturer() == "" {
+				return fmt.Errorf("fallback of profile %q has no manufacturer", p.GetName())
+			}
+			for _, pattern := range []string{f.GetPartNumber(), f.GetControlCardPartNumber()} {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return fmt.Errorf("fallback of profile %q has invalid pattern %q: %v", p.GetName(), pattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchPattern reports whether the part number matches the pattern. Empty patterns match anything.
+func matchPattern(pattern, partNumber string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(pattern, partNumber)
+	return err == nil && ok
+}
+
+// fallbackMatches reports whether the chassis described by desc matches the fallback.
+func fallbackMatches(f *epb.ProfileMatch, desc *bpb.ChassisDescriptor) bool {
+	if f.GetManufacturer() != desc.GetManufacturer() {
+		return false
+	}
+	if !matchPattern(f.GetPartNumber(), desc.GetPartNumber()) {
+		return false
+	}
+	if f.GetControlCardPartNumber() == "" {
+		return true
+	}
+	if len(desc.GetControlCards()) == 0 {
+		return false
+	}
+	for _, cc := range desc.GetControlCards() {
+		if !matchPattern(f.GetControlCardPartNumber(), cc.GetPartNumber()) {
+			return false
+		}
+	}
+	return true
+}
+
+// fallbackProfile returns the first profile with a fallback matching the chassis, or nil. The caller must hold m.mu.
+func (m *InMemoryEntityManager) fallbackProfile(desc *bpb.ChassisDescriptor) *epb.Profile {
+	for _, p := range m.profiles {
+		for _, f := range p.GetFallback() {
+			if fallbackMatches(f, desc) {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+// chassisFromProfile returns an inventory chassis with the provided identity, configured from the profile.
+// It is named after its serial, or the serial of its first control card.
+func chassisFromProfile(p *epb.Profile, manufacturer, serial, partNumber string, cards []*bpb.ControlCard) *epb.Chassis {
+	ch := &epb.Chassis{
+		Name:                   serial,
+		SerialNumber:           serial,
+		PartNumber:             partNumber,
+		Manufacturer:           manufacturer,
+		BootloaderPasswordHash: p.GetBootloaderPasswordHash(),
+		BootloaderPassword:     proto.Clone(p.GetBootloaderPassword()).(*epb.BootloaderPassword),
+		BootMode:               p.GetBootMode(),
+		SoftwareImage:          proto.Clone(p.GetSoftwareImage()).(*bpb.SoftwareImage),
+		Config:                 proto.Clone(p.GetConfig()).(*epb.Config),
+		Profile:                p.GetName(),
+	}
+	for _, cc := range cards {
+		ch.ControllerCards = append(ch.ControllerCards, &epb.ControlCard{
+			SerialNumber: cc.GetSerialNumber(),
+			PartNumber:   cc.GetPartNumber(),
+		})
+	}
+	if ch.Name == "" && len(ch.ControllerCards) > 0 {
+		ch.Name = ch.ControllerCards[0].GetSerialNumber()
+	}
+	return ch
+}
+
+// provisionalKey returns the key of a provisional chassis: its manufacturer and serial, or the
+// serial of its first control card.
+func provisionalKey(ch *epb.Chassis) service.EntityLookup {
+	serial := ch.GetSerialNumber()
+	if serial == "" && len(ch.GetControllerCards()) > 0 {
+		serial = ch.GetControllerCards()[0].GetSerialNumber()
+	}
+	return service.EntityLookup{Manufacturer: ch.GetManufacturer(), SerialNumber: serial}
+}
+
+// addProvisional adds the chassis described by desc to the inventory, configured from the fallback
+// profile, and records it in its status history. Provisional chassis which were not approved
+// within the provisional TTL are dropped, as is the oldest one beyond the maximum number of
+// discovered devices, so that requests from unknown serials cannot grow the inventory without
+// bound. The caller must hold m.mu.
+func (m *InMemoryEntityManager) addProvisional(ctx context.Context, p *epb.Profile, desc *bpb.ChassisDescriptor, now time.Time) {
+	m.expireProvisional(now)
+	if m.maxDiscovered > 0 && len(m.provisioned) >= m.maxDiscovered {
+		var oldest service.EntityLookup
+		var oldestTime time.Time
+		for k, t := range m.provisioned {
+			if oldestTime.IsZero() || t.Before(oldestTime) {
+				oldest, oldestTime = k, t
+			}
+		}
+		m.dropProvisional(oldest)
+	}
+	ch := chassisFromProfile(p, desc.GetManufacturer(), desc.GetSerialNumber(), desc.GetPartNumber(), desc.GetControlCards())
+	ch.Provisional = true
+	m.addChassis(ch)
+	key := provisionalKey(ch)
+	m.provisioned[key] = now
+	serial := key.SerialNumber
+	msg := fmt.Sprintf("provisioned from fallback profile %v", p.GetName())
+	m.recordTransition(m.chassisStatus(ch), StatusTransition{
+		Serial:  serial,
+		Message: msg,
+		Time:    now,
+		Source:  sourceAddress(ctx),
+	})
+	log.Warningf("%v chassis %v (part %v) is not in the inventory, %v", ch.GetManufacturer(), ch.GetName(), ch.GetPartNumber(), msg)
+}
+
+// expireProvisional drops the provisional chassis which were not approved within the provisional
+// TTL. The caller must hold m.mu.
+func (m *InMemoryEntityManager) expireProvisional(now time.Time) {
+	if m.provisionalTTL <= 0 {
+		return
+	}
+	for k, t := range m.provisioned {
+		if now.Sub(t) > m.provisionalTTL {
+			log.Infof("Provisional %v chassis %v was not approved within %v", k.Manufacturer, k.SerialNumber, m.provisionalTTL)
+			m.dropProvisional(k)
+		}
+	}
+}
+
+// dropProvisional removes the provisional chassis with the key from the inventory, along with its
+// status. The caller must hold m.mu.
+func (m *InMemoryEntityManager) dropProvisional(key service.EntityLookup) {
+	delete(m.provisioned, key)
+	for i, ch := range m.chassisInventory {
+		if ch.GetProvisional() && provisionalKey(ch) == key {
+			m.removeChassis(i)
+			statusKey := service.EntityLookup{Manufacturer: ch.GetManufacturer(), SerialNumber: ch.GetSerialNumber()}
+			delete(m.chassisStatuses, statusKey)
+			delete(m.audits, statusKey)
+			log.Infof("Dropped provisional %v chassis %v from the inventory", key.Manufacturer, key.SerialNumber)
+			return
+		}
+	}
+}
@@ -25,6 +25,119 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PasswordHashScheme int32
+
+const (
+	PasswordHashScheme_PASSWORD_HASH_SCHEME_UNSPECIFIED  PasswordHashScheme = 0
+	PasswordHashScheme_PASSWORD_HASH_SCHEME_SHA512_CRYPT PasswordHashScheme = 1
+	PasswordHashScheme_PASSWORD_HASH_SCHEME_SHA256_CRYPT PasswordHashScheme = 2
+)
+
+// Enum value maps for PasswordHashScheme.
+var (
+	PasswordHashScheme_name = map[int32]string{
+		0: "PASSWORD_HASH_SCHEME_UNSPECIFIED",
+		1: "PASSWORD_HASH_SCHEME_SHA512_CRYPT",
+		2: "PASSWORD_HASH_SCHEME_SHA256_CRYPT",
+	}
+	PasswordHashScheme_value = map[string]int32{
+		"PASSWORD_HASH_SCHEME_UNSPECIFIED":  0,
+		"PASSWORD_HASH_SCHEME_SHA512_CRYPT": 1,
+		"PASSWORD_HASH_SCHEME_SHA256_CRYPT": 2,
+	}
+)
+
+func (x PasswordHashScheme) Enum() *PasswordHashScheme {
+	p := new(PasswordHashScheme)
+	*p = x
+	return p
+}
+
+func (x PasswordHashScheme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PasswordHashScheme) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_entitymanager_proto_entity_proto_enumTypes[0].Descriptor()
+}
+
+func (PasswordHashScheme) Type() protoreflect.EnumType {
+	return &file_server_entitymanager_proto_entity_proto_enumTypes[0]
+}
+
+func (x PasswordHashScheme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PasswordHashScheme.Descriptor instead.
+func (PasswordHashScheme) EnumDescriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{0}
+}
+
+type Fault int32
+
+const (
+	Fault_FAULT_UNSPECIFIED        Fault = 0
+	Fault_FAULT_WRONG_NONCE        Fault = 1
+	Fault_FAULT_CORRUPT_SIGNATURE  Fault = 2
+	Fault_FAULT_EXPIRED_OV         Fault = 3
+	Fault_FAULT_OV_SERIAL_MISMATCH Fault = 4
+	Fault_FAULT_OC_NOT_CHAINED     Fault = 5
+	Fault_FAULT_WRONG_IMAGE_HASH   Fault = 6
+	Fault_FAULT_TRUNCATED_DATA     Fault = 7
+)
+
+// Enum value maps for Fault.
+var (
+	Fault_name = map[int32]string{
+		0: "FAULT_UNSPECIFIED",
+		1: "FAULT_WRONG_NONCE",
+		2: "FAULT_CORRUPT_SIGNATURE",
+		3: "FAULT_EXPIRED_OV",
+		4: "FAULT_OV_SERIAL_MISMATCH",
+		5: "FAULT_OC_NOT_CHAINED",
+		6: "FAULT_WRONG_IMAGE_HASH",
+		7: "FAULT_TRUNCATED_DATA",
+	}
+	Fault_value = map[string]int32{
+		"FAULT_UNSPECIFIED":        0,
+		"FAULT_WRONG_NONCE":        1,
+		"FAULT_CORRUPT_SIGNATURE":  2,
+		"FAULT_EXPIRED_OV":         3,
+		"FAULT_OV_SERIAL_MISMATCH": 4,
+		"FAULT_OC_NOT_CHAINED":     5,
+		"FAULT_WRONG_IMAGE_HASH":   6,
+		"FAULT_TRUNCATED_DATA":     7,
+	}
+)
+
+func (x Fault) Enum() *Fault {
+	p := new(Fault)
+	*p = x
+	return p
+}
+
+func (x Fault) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Fault) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_entitymanager_proto_entity_proto_enumTypes[1].Descriptor()
+}
+
+func (Fault) Type() protoreflect.EnumType {
+	return &file_server_entitymanager_proto_entity_proto_enumTypes[1]
+}
+
+func (x Fault) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Fault.Descriptor instead.
+func (Fault) EnumDescriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{1}
+}
+
 type Options struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -33,6 +146,7 @@ type Options struct {
 	GnsiGlobalConfig *GNSIConfig `protobuf:"bytes,1,opt,name=gnsi_global_config,json=gnsiGlobalConfig,proto3" json:"gnsi_global_config,omitempty"`
 	Bootzserver      string      `protobuf:"bytes,2,opt,name=bootzserver,proto3" json:"bootzserver,omitempty"`
 	ArtifactDir      string      `protobuf:"bytes,3,opt,name=artifact_dir,json=artifactDir,proto3" json:"artifact_dir,omitempty"`
+	Roles            []*Role     `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
 }
 
 func (x *Options) Reset() {
@@ -88,13 +202,22 @@ func (x *Options) GetArtifactDir() string {
 	return ""
 }
 
+func (x *Options) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
 type Entities struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Options *Options   `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
-	Chassis []*Chassis `protobuf:"bytes,2,rep,name=chassis,proto3" json:"chassis,omitempty"`
+	Options  *Options   `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Chassis  []*Chassis `protobuf:"bytes,2,rep,name=chassis,proto3" json:"chassis,omitempty"`
+	Profiles []*Profile `protobuf:"bytes,3,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	Includes []string   `protobuf:"bytes,4,rep,name=includes,proto3" json:"includes,omitempty"`
 }
 
 func (x *Entities) Reset() {
@@ -143,6 +266,178 @@ func (x *Entities) GetChassis() []*Chassis {
 	return nil
 }
 
+func (x *Entities) GetProfiles() []*Profile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+func (x *Entities) GetIncludes() []string {
+	if x != nil {
+		return x.Includes
+	}
+	return nil
+}
+
+type Profile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name                   string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	BootloaderPasswordHash string               `protobuf:"bytes,2,opt,name=bootloader_password_hash,json=bootloaderPasswordHash,proto3" json:"bootloader_password_hash,omitempty"`
+	BootloaderPassword     *BootloaderPassword  `protobuf:"bytes,7,opt,name=bootloader_password,json=bootloaderPassword,proto3" json:"bootloader_password,omitempty"`
+	BootMode               bootz.BootMode       `protobuf:"varint,3,opt,name=boot_mode,json=bootMode,proto3,enum=bootz.proto.BootMode" json:"boot_mode,omitempty"`
+	SoftwareImage          *bootz.SoftwareImage `protobuf:"bytes,4,opt,name=software_image,json=softwareImage,proto3" json:"software_image,omitempty"`
+	Config                 *Config              `protobuf:"bytes,5,opt,name=config,proto3" json:"config,omitempty"`
+	Fallback               []*ProfileMatch      `protobuf:"bytes,6,rep,name=fallback,proto3" json:"fallback,omitempty"`
+}
+
+func (x *Profile) Reset() {
+	*x = Profile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Profile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profile) ProtoMessage() {}
+
+func (x *Profile) ProtoReflect() protoreflect.Message {
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profile.ProtoReflect.Descriptor instead.
+func (*Profile) Descriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Profile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Profile) GetBootloaderPasswordHash() string {
+	if x != nil {
+		return x.BootloaderPasswordHash
+	}
+	return ""
+}
+
+func (x *Profile) GetBootloaderPassword() *BootloaderPassword {
+	if x != nil {
+		return x.BootloaderPassword
+	}
+	return nil
+}
+
+func (x *Profile) GetBootMode() bootz.BootMode {
+	if x != nil {
+		return x.BootMode
+	}
+	return bootz.BootMode(0)
+}
+
+func (x *Profile) GetSoftwareImage() *bootz.SoftwareImage {
+	if x != nil {
+		return x.SoftwareImage
+	}
+	return nil
+}
+
+func (x *Profile) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Profile) GetFallback() []*ProfileMatch {
+	if x != nil {
+		return x.Fallback
+	}
+	return nil
+}
+
+type ProfileMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Manufacturer          string `protobuf:"bytes,1,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	PartNumber            string `protobuf:"bytes,2,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	ControlCardPartNumber string `protobuf:"bytes,3,opt,name=control_card_part_number,json=controlCardPartNumber,proto3" json:"control_card_part_number,omitempty"`
+}
+
+func (x *ProfileMatch) Reset() {
+	*x = ProfileMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProfileMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileMatch) ProtoMessage() {}
+
+func (x *ProfileMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileMatch.ProtoReflect.Descriptor instead.
+func (*ProfileMatch) Descriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProfileMatch) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *ProfileMatch) GetPartNumber() string {
+	if x != nil {
+		return x.PartNumber
+	}
+	return ""
+}
+
+func (x *ProfileMatch) GetControlCardPartNumber() string {
+	if x != nil {
+		return x.ControlCardPartNumber
+	}
+	return ""
+}
+
 type Config struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -155,7 +450,7 @@ type Config struct {
 func (x *Config) Reset() {
 	*x = Config{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[2]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -168,7 +463,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[2]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -181,7 +476,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{2}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Config) GetBootConfig() *BootConfig {
@@ -212,7 +507,7 @@ type BootConfig struct {
 func (x *BootConfig) Reset() {
 	*x = BootConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[3]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -225,7 +520,7 @@ func (x *BootConfig) String() string {
 func (*BootConfig) ProtoMessage() {}
 
 func (x *BootConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[3]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,7 +533,7 @@ func (x *BootConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BootConfig.ProtoReflect.Descriptor instead.
 func (*BootConfig) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{3}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *BootConfig) GetMetadata() *structpb.Struct {
@@ -282,12 +577,14 @@ type GNSIConfig struct {
 	CertzUploadFile string               `protobuf:"bytes,6,opt,name=certz_upload_file,json=certzUploadFile,proto3" json:"certz_upload_file,omitempty"`
 	CredentialsFile string               `protobuf:"bytes,7,opt,name=credentials_file,json=credentialsFile,proto3" json:"credentials_file,omitempty"`
 	Credentials     *bootz.Credentials   `protobuf:"bytes,8,opt,name=credentials,proto3" json:"credentials,omitempty"`
+	Users           []*User              `protobuf:"bytes,9,rep,name=users,proto3" json:"users,omitempty"`
+	Roles           []string             `protobuf:"bytes,10,rep,name=roles,proto3" json:"roles,omitempty"`
 }
 
 func (x *GNSIConfig) Reset() {
 	*x = GNSIConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[4]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -300,7 +597,7 @@ func (x *GNSIConfig) String() string {
 func (*GNSIConfig) ProtoMessage() {}
 
 func (x *GNSIConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[4]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -313,7 +610,7 @@ func (x *GNSIConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GNSIConfig.ProtoReflect.Descriptor instead.
 func (*GNSIConfig) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{4}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GNSIConfig) GetAuthzUploadFile() string {
@@ -372,6 +669,20 @@ func (x *GNSIConfig) GetCredentials() *bootz.Credentials {
 	return nil
 }
 
+func (x *GNSIConfig) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *GNSIConfig) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
 type DHCPConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -386,7 +697,7 @@ type DHCPConfig struct {
 func (x *DHCPConfig) Reset() {
 	*x = DHCPConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[5]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -399,7 +710,7 @@ func (x *DHCPConfig) String() string {
 func (*DHCPConfig) ProtoMessage() {}
 
 func (x *DHCPConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[5]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -412,7 +723,7 @@ func (x *DHCPConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DHCPConfig.ProtoReflect.Descriptor instead.
 func (*DHCPConfig) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{5}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DHCPConfig) GetHardwareAddress() string {
@@ -448,15 +759,19 @@ type ControlCard struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PartNumber   string      `protobuf:"bytes,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
-	SerialNumber string      `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
-	DhcpConfig   *DHCPConfig `protobuf:"bytes,4,opt,name=dhcp_config,json=dhcpConfig,proto3" json:"dhcp_config,omitempty"`
+	PartNumber             string               `protobuf:"bytes,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	SerialNumber           string               `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	DhcpConfig             *DHCPConfig          `protobuf:"bytes,4,opt,name=dhcp_config,json=dhcpConfig,proto3" json:"dhcp_config,omitempty"`
+	SoftwareImage          *bootz.SoftwareImage `protobuf:"bytes,5,opt,name=software_image,json=softwareImage,proto3" json:"software_image,omitempty"`
+	BootloaderPasswordHash string               `protobuf:"bytes,6,opt,name=bootloader_password_hash,json=bootloaderPasswordHash,proto3" json:"bootloader_password_hash,omitempty"`
+	BootConfig             *BootConfig          `protobuf:"bytes,7,opt,name=boot_config,json=bootConfig,proto3" json:"boot_config,omitempty"`
+	GnsiConfig             *GNSIConfig          `protobuf:"bytes,8,opt,name=gnsi_config,json=gnsiConfig,proto3" json:"gnsi_config,omitempty"`
 }
 
 func (x *ControlCard) Reset() {
 	*x = ControlCard{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[6]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -469,7 +784,7 @@ func (x *ControlCard) String() string {
 func (*ControlCard) ProtoMessage() {}
 
 func (x *ControlCard) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[6]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,7 +797,7 @@ func (x *ControlCard) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ControlCard.ProtoReflect.Descriptor instead.
 func (*ControlCard) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{6}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ControlCard) GetPartNumber() string {
@@ -506,6 +821,309 @@ func (x *ControlCard) GetDhcpConfig() *DHCPConfig {
 	return nil
 }
 
+func (x *ControlCard) GetSoftwareImage() *bootz.SoftwareImage {
+	if x != nil {
+		return x.SoftwareImage
+	}
+	return nil
+}
+
+func (x *ControlCard) GetBootloaderPasswordHash() string {
+	if x != nil {
+		return x.BootloaderPasswordHash
+	}
+	return ""
+}
+
+func (x *ControlCard) GetBootConfig() *BootConfig {
+	if x != nil {
+		return x.BootConfig
+	}
+	return nil
+}
+
+func (x *ControlCard) GetGnsiConfig() *GNSIConfig {
+	if x != nil {
+		return x.GnsiConfig
+	}
+	return nil
+}
+
+type BootloaderPassword struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Source:
+	//	*BootloaderPassword_PasswordFile
+	//	*BootloaderPassword_Secret
+	//	*BootloaderPassword_Generate
+	Source isBootloaderPassword_Source `protobuf_oneof:"source"`
+	Scheme PasswordHashScheme          `protobuf:"varint,4,opt,name=scheme,proto3,enum=entity.PasswordHashScheme" json:"scheme,omitempty"`
+}
+
+func (x *BootloaderPassword) Reset() {
+	*x = BootloaderPassword{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BootloaderPassword) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BootloaderPassword) ProtoMessage() {}
+
+func (x *BootloaderPassword) ProtoReflect() protoreflect.Message {
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BootloaderPassword.ProtoReflect.Descriptor instead.
+func (*BootloaderPassword) Descriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{9}
+}
+
+func (m *BootloaderPassword) GetSource() isBootloaderPassword_Source {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+func (x *BootloaderPassword) GetPasswordFile() string {
+	if x, ok := x.GetSource().(*BootloaderPassword_PasswordFile); ok {
+		return x.PasswordFile
+	}
+	return ""
+}
+
+func (x *BootloaderPassword) GetSecret() string {
+	if x, ok := x.GetSource().(*BootloaderPassword_Secret); ok {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *BootloaderPassword) GetGenerate() bool {
+	if x, ok := x.GetSource().(*BootloaderPassword_Generate); ok {
+		return x.Generate
+	}
+	return false
+}
+
+func (x *BootloaderPassword) GetScheme() PasswordHashScheme {
+	if x != nil {
+		return x.Scheme
+	}
+	return PasswordHashScheme_PASSWORD_HASH_SCHEME_UNSPECIFIED
+}
+
+type isBootloaderPassword_Source interface {
+	isBootloaderPassword_Source()
+}
+
+type BootloaderPassword_PasswordFile struct {
+	PasswordFile string `protobuf:"bytes,1,opt,name=password_file,json=passwordFile,proto3,oneof"`
+}
+
+type BootloaderPassword_Secret struct {
+	Secret string `protobuf:"bytes,2,opt,name=secret,proto3,oneof"`
+}
+
+type BootloaderPassword_Generate struct {
+	Generate bool `protobuf:"varint,3,opt,name=generate,proto3,oneof"`
+}
+
+func (*BootloaderPassword_PasswordFile) isBootloaderPassword_Source() {}
+
+func (*BootloaderPassword_Secret) isBootloaderPassword_Source() {}
+
+func (*BootloaderPassword_Generate) isBootloaderPassword_Source() {}
+
+type User struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Account              string   `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	AuthorizedKeys       []string `protobuf:"bytes,2,rep,name=authorized_keys,json=authorizedKeys,proto3" json:"authorized_keys,omitempty"`
+	AuthorizedPrincipals []string `protobuf:"bytes,3,rep,name=authorized_principals,json=authorizedPrincipals,proto3" json:"authorized_principals,omitempty"`
+	// Types that are assignable to Password:
+	//	*User_PasswordHash
+	//	*User_PasswordFile
+	//	*User_PasswordSecret
+	Password isUser_Password `protobuf_oneof:"password"`
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *User) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *User) GetAuthorizedKeys() []string {
+	if x != nil {
+		return x.AuthorizedKeys
+	}
+	return nil
+}
+
+func (x *User) GetAuthorizedPrincipals() []string {
+	if x != nil {
+		return x.AuthorizedPrincipals
+	}
+	return nil
+}
+
+func (m *User) GetPassword() isUser_Password {
+	if m != nil {
+		return m.Password
+	}
+	return nil
+}
+
+func (x *User) GetPasswordHash() string {
+	if x, ok := x.GetPassword().(*User_PasswordHash); ok {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+func (x *User) GetPasswordFile() string {
+	if x, ok := x.GetPassword().(*User_PasswordFile); ok {
+		return x.PasswordFile
+	}
+	return ""
+}
+
+func (x *User) GetPasswordSecret() string {
+	if x, ok := x.GetPassword().(*User_PasswordSecret); ok {
+		return x.PasswordSecret
+	}
+	return ""
+}
+
+type isUser_Password interface {
+	isUser_Password()
+}
+
+type User_PasswordHash struct {
+	PasswordHash string `protobuf:"bytes,4,opt,name=password_hash,json=passwordHash,proto3,oneof"`
+}
+
+type User_PasswordFile struct {
+	PasswordFile string `protobuf:"bytes,5,opt,name=password_file,json=passwordFile,proto3,oneof"`
+}
+
+type User_PasswordSecret struct {
+	PasswordSecret string `protobuf:"bytes,6,opt,name=password_secret,json=passwordSecret,proto3,oneof"`
+}
+
+func (*User_PasswordHash) isUser_Password() {}
+
+func (*User_PasswordFile) isUser_Password() {}
+
+func (*User_PasswordSecret) isUser_Password() {}
+
+type Role struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Users []*User `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *Role) Reset() {
+	*x = Role{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Role) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Role) ProtoMessage() {}
+
+func (x *Role) ProtoReflect() protoreflect.Message {
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Role.ProtoReflect.Descriptor instead.
+func (*Role) Descriptor() ([]byte, []int) {
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Role) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Role) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
 type Chassis struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -516,17 +1134,21 @@ type Chassis struct {
 	PartNumber             string               `protobuf:"bytes,3,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
 	Manufacturer           string               `protobuf:"bytes,4,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
 	BootloaderPasswordHash string               `protobuf:"bytes,5,opt,name=bootloader_password_hash,json=bootloaderPasswordHash,proto3" json:"bootloader_password_hash,omitempty"`
+	BootloaderPassword     *BootloaderPassword  `protobuf:"bytes,16,opt,name=bootloader_password,json=bootloaderPassword,proto3" json:"bootloader_password,omitempty"`
 	BootMode               bootz.BootMode       `protobuf:"varint,6,opt,name=boot_mode,json=bootMode,proto3,enum=bootz.proto.BootMode" json:"boot_mode,omitempty"`
 	SoftwareImage          *bootz.SoftwareImage `protobuf:"bytes,7,opt,name=software_image,json=softwareImage,proto3" json:"software_image,omitempty"`
 	ControllerCards        []*ControlCard       `protobuf:"bytes,8,rep,name=controller_cards,json=controllerCards,proto3" json:"controller_cards,omitempty"`
 	Config                 *Config              `protobuf:"bytes,9,opt,name=config,proto3" json:"config,omitempty"`
 	DhcpConfig             *DHCPConfig          `protobuf:"bytes,12,opt,name=dhcp_config,json=dhcpConfig,proto3" json:"dhcp_config,omitempty"`
+	Faults                 []Fault              `protobuf:"varint,13,rep,packed,name=faults,proto3,enum=entity.Fault" json:"faults,omitempty"`
+	Profile                string               `protobuf:"bytes,14,opt,name=profile,proto3" json:"profile,omitempty"`
+	Provisional            bool                 `protobuf:"varint,15,opt,name=provisional,proto3" json:"provisional,omitempty"`
 }
 
 func (x *Chassis) Reset() {
 	*x = Chassis{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[7]
+		mi := &file_server_entitymanager_proto_entity_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -539,7 +1161,7 @@ func (x *Chassis) String() string {
 func (*Chassis) ProtoMessage() {}
 
 func (x *Chassis) ProtoReflect() protoreflect.Message {
-	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[7]
+	mi := &file_server_entitymanager_proto_entity_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -552,7 +1174,7 @@ func (x *Chassis) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chassis.ProtoReflect.Descriptor instead.
 func (*Chassis) Descriptor() ([]byte, []int) {
-	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{7}
+	return file_server_entitymanager_proto_entity_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Chassis) GetSerialNumber() string {
@@ -590,6 +1212,13 @@ func (x *Chassis) GetBootloaderPasswordHash() string {
 	return ""
 }
 
+func (x *Chassis) GetBootloaderPassword() *BootloaderPassword {
+	if x != nil {
+		return x.BootloaderPassword
+	}
+	return nil
+}
+
 func (x *Chassis) GetBootMode() bootz.BootMode {
 	if x != nil {
 		return x.BootMode
@@ -625,6 +1254,27 @@ func (x *Chassis) GetDhcpConfig() *DHCPConfig {
 	return nil
 }
 
+func (x *Chassis) GetFaults() []Fault {
+	if x != nil {
+		return x.Faults
+	}
+	return nil
+}
+
+func (x *Chassis) GetProfile() string {
+	if x != nil {
+		return x.Profile
+	}
+	return ""
+}
+
+func (x *Chassis) GetProvisional() bool {
+	if x != nil {
+		return x.Provisional
+	}
+	return false
+}
+
 var File_server_entitymanager_proto_entity_proto protoreflect.FileDescriptor
 
 var file_server_entitymanager_proto_entity_proto_rawDesc = []byte{
@@ -642,7 +1292,7 @@ var file_server_entitymanager_proto_entity_proto_rawDesc = []byte{
 	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66,
 	0x69, 0x67, 0x2f, 0x67, 0x6e, 0x73, 0x69, 0x2f, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x2f, 0x70, 0x61,
 	0x74, 0x68, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2f, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x90, 0x01, 0x0a,
+	0x2f, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb4, 0x01, 0x0a,
 	0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x40, 0x0a, 0x12, 0x67, 0x6e, 0x73, 0x69,
 	0x5f, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x47, 0x4e,
@@ -651,81 +1301,170 @@ var file_server_entitymanager_proto_entity_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x7a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c,
 	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0b, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x69, 0x72, 0x22,
-	0x60, 0x0a, 0x08, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x07, 0x6f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x73, 0x73, 0x69,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x2e, 0x43, 0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x52, 0x07, 0x63, 0x68, 0x61, 0x73, 0x73, 0x69,
-	0x73, 0x22, 0x72, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x0b, 0x62,
-	0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x33, 0x0a, 0x0b, 0x67, 0x6e, 0x73, 0x69, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x47,
-	0x4e, 0x53, 0x49, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x67, 0x6e, 0x73, 0x69, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xdb, 0x01, 0x0a, 0x0a, 0x42, 0x6f, 0x6f, 0x74, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52,
-	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x12, 0x76, 0x65, 0x6e,
-	0x64, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x63, 0x5f, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0c, 0x6f, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x44, 0x0a,
-	0x11, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63,
-	0x74, 0x52, 0x10, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x22, 0xba, 0x03, 0x0a, 0x0a, 0x47, 0x4e, 0x53, 0x49, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61,
-	0x75, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x3f,
-	0x0a, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69, 0x2e, 0x61, 0x75, 0x74, 0x68,
-	0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x52, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12,
-	0x2a, 0x0a, 0x11, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f,
-	0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x61, 0x74, 0x68,
-	0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x70,
-	0x61, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69, 0x2e, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x2e, 0x76,
-	0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
-	0x0b, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x3f, 0x0a, 0x0c,
-	0x63, 0x65, 0x72, 0x74, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69, 0x2e, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x2e,
-	0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2a, 0x0a,
-	0x11, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x66, 0x69,
-	0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x55,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x72, 0x65,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
-	0x46, 0x69, 0x6c, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x61, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6f, 0x6f, 0x74,
-	0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69,
-	0x61, 0x6c, 0x73, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
-	0x22, 0x92, 0x01, 0x0a, 0x0a, 0x44, 0x48, 0x43, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
-	0x29, 0x0a, 0x10, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x68, 0x61, 0x72, 0x64, 0x77,
-	0x61, 0x72, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70,
-	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x69, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x74,
-	0x65, 0x77, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x61, 0x74, 0x65,
-	0x77, 0x61, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x73,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x22, 0x88, 0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
-	0x6c, 0x43, 0x61, 0x72, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75,
-	0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74,
-	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c,
-	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
-	0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x0b, 0x64,
-	0x68, 0x63, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x48, 0x43, 0x50, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x64, 0x68, 0x63, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x22, 0xd5, 0x03, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x12, 0x23, 0x0a, 0x0d,
+	0x28, 0x09, 0x52, 0x0b, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x69, 0x72, 0x12,
+	0x22, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x6f,
+	0x6c, 0x65, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73,
+	0x12, 0x29, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x43, 0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x52, 0x07, 0x63,
+	0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x73, 0x22,
+	0xf5, 0x02, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x38, 0x0a, 0x18, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x16, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x4b, 0x0a, 0x13, 0x62, 0x6f, 0x6f,
+	0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e,
+	0x42, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x52, 0x12, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61,
+	0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x32, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x74,
+	0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x08, 0x62, 0x6f, 0x6f, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x73, 0x6f,
+	0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x53, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x0d,
+	0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x30, 0x0a, 0x08, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x08, 0x66,
+	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x22, 0x8c, 0x01, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x6e, 0x75,
+	0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b,
+	0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x37, 0x0a,
+	0x18, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x70, 0x61,
+	0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x15, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x61, 0x72, 0x64, 0x50, 0x61, 0x72, 0x74,
+	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x72, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x33, 0x0a, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42,
+	0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x0b, 0x67, 0x6e, 0x73, 0x69, 0x5f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x2e, 0x47, 0x4e, 0x53, 0x49, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a,
+	0x67, 0x6e, 0x73, 0x69, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xdb, 0x01, 0x0a, 0x0a, 0x42,
+	0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74,
+	0x72, 0x75, 0x63, 0x74, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2c,
+	0x0a, 0x12, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x76, 0x65, 0x6e, 0x64,
+	0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e,
+	0x6f, 0x63, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x63, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x44, 0x0a, 0x11, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72,
+	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x10, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xf4, 0x03, 0x0a, 0x0a, 0x47, 0x4e, 0x53,
+	0x49, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x75, 0x74, 0x68, 0x7a,
+	0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69,
+	0x2e, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x3f, 0x0a, 0x0c, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69, 0x2e, 0x70, 0x61,
+	0x74, 0x68, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x12, 0x3f, 0x0a, 0x0c, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6e, 0x73, 0x69, 0x2e, 0x63,
+	0x65, 0x72, 0x74, 0x7a, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x65, 0x72, 0x74, 0x7a, 0x5f, 0x75, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63,
+	0x65, 0x72, 0x74, 0x7a, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x29,
+	0x0a, 0x10, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x61, 0x6c, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x63, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x0b, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x22, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x22,
+	0x92, 0x01, 0x0a, 0x0a, 0x44, 0x48, 0x43, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29,
+	0x0a, 0x10, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61,
+	0x72, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69,
+	0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x67, 0x61, 0x74, 0x65,
+	0x77, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x22, 0xef, 0x02, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x43, 0x61, 0x72, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x74, 0x4e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65,
+	0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x0b, 0x64, 0x68,
+	0x63, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44, 0x48, 0x43, 0x50, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x52, 0x0a, 0x64, 0x68, 0x63, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x41, 0x0a, 0x0e, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x52, 0x0d, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x12, 0x38, 0x0a, 0x18, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72,
+	0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72,
+	0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x12, 0x33, 0x0a, 0x0b,
+	0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x62, 0x6f, 0x6f, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x12, 0x33, 0x0a, 0x0b, 0x67, 0x6e, 0x73, 0x69, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e,
+	0x47, 0x4e, 0x53, 0x49, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x67, 0x6e, 0x73, 0x69,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xb1, 0x01, 0x0a, 0x12, 0x42, 0x6f, 0x6f, 0x74, 0x6c,
+	0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x25, 0x0a,
+	0x0d, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x1c,
+	0x0a, 0x08, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x00, 0x52, 0x08, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a, 0x06,
+	0x73, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61,
+	0x73, 0x68, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x83, 0x02, 0x0a, 0x04, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x27, 0x0a,
+	0x0f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a,
+	0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x33, 0x0a, 0x15, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x69, 0x7a, 0x65, 0x64, 0x5f, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65,
+	0x64, 0x50, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x73, 0x12, 0x25, 0x0a, 0x0d, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x25, 0x0a, 0x0d, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x0f, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x0e, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
+	0x22, 0x3e, 0x0a, 0x04, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x05,
+	0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73,
+	0x22, 0x85, 0x05, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x73, 0x73, 0x69, 0x73, 0x12, 0x23, 0x0a, 0x0d,
 	0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65,
 	0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
@@ -737,24 +1476,58 @@ var file_server_entitymanager_proto_entity_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
 	0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x62, 0x6f,
 	0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64,
-	0x48, 0x61, 0x73, 0x68, 0x12, 0x32, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x6d, 0x6f, 0x64,
-	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08,
-	0x62, 0x6f, 0x6f, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x73, 0x6f, 0x66, 0x74,
-	0x77, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
-	0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x73, 0x6f,
-	0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x10, 0x63,
-	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18,
-	0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x43,
-	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x61, 0x72, 0x64, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x43, 0x61, 0x72, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x63,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x65, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x0b, 0x64, 0x68, 0x63, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x2e, 0x44, 0x48, 0x43, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x64, 0x68,
-	0x63, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x4b, 0x0a, 0x13, 0x62, 0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64,
+	0x65, 0x72, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x6c,
+	0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x52, 0x12, 0x62,
+	0x6f, 0x6f, 0x74, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x12, 0x32, 0x0a, 0x09, 0x62, 0x6f, 0x6f, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x42, 0x6f, 0x6f, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x62, 0x6f, 0x6f,
+	0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72,
+	0x65, 0x5f, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x62, 0x6f, 0x6f, 0x74, 0x7a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x6f, 0x66, 0x74,
+	0x77, 0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x0d, 0x73, 0x6f, 0x66, 0x74, 0x77,
+	0x61, 0x72, 0x65, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x10, 0x63, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x43, 0x61, 0x72, 0x64, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x6c, 0x65, 0x72, 0x43, 0x61, 0x72, 0x64, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x33, 0x0a, 0x0b, 0x64, 0x68, 0x63, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x44,
+	0x48, 0x43, 0x50, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0a, 0x64, 0x68, 0x63, 0x70, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x25, 0x0a, 0x06, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x0d, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x46,
+	0x61, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x2a, 0x88, 0x01, 0x0a, 0x12, 0x50, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x65, 0x12,
+	0x24, 0x0a, 0x20, 0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52, 0x44, 0x5f, 0x48, 0x41, 0x53, 0x48,
+	0x5f, 0x53, 0x43, 0x48, 0x45, 0x4d, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x25, 0x0a, 0x21, 0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52,
+	0x44, 0x5f, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x53, 0x43, 0x48, 0x45, 0x4d, 0x45, 0x5f, 0x53, 0x48,
+	0x41, 0x35, 0x31, 0x32, 0x5f, 0x43, 0x52, 0x59, 0x50, 0x54, 0x10, 0x01, 0x12, 0x25, 0x0a, 0x21,
+	0x50, 0x41, 0x53, 0x53, 0x57, 0x4f, 0x52, 0x44, 0x5f, 0x48, 0x41, 0x53, 0x48, 0x5f, 0x53, 0x43,
+	0x48, 0x45, 0x4d, 0x45, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x5f, 0x43, 0x52, 0x59, 0x50,
+	0x54, 0x10, 0x02, 0x2a, 0xd6, 0x01, 0x0a, 0x05, 0x46, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x15, 0x0a,
+	0x11, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x57, 0x52,
+	0x4f, 0x4e, 0x47, 0x5f, 0x4e, 0x4f, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x46,
+	0x41, 0x55, 0x4c, 0x54, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x55, 0x50, 0x54, 0x5f, 0x53, 0x49, 0x47,
+	0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x46, 0x41, 0x55, 0x4c,
+	0x54, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x5f, 0x4f, 0x56, 0x10, 0x03, 0x12, 0x1c,
+	0x0a, 0x18, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x4f, 0x56, 0x5f, 0x53, 0x45, 0x52, 0x49, 0x41,
+	0x4c, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x04, 0x12, 0x18, 0x0a, 0x14,
+	0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x4f, 0x43, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x43, 0x48, 0x41,
+	0x49, 0x4e, 0x45, 0x44, 0x10, 0x05, 0x12, 0x1a, 0x0a, 0x16, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f,
+	0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x48, 0x41, 0x53, 0x48,
+	0x10, 0x06, 0x12, 0x18, 0x0a, 0x14, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x5f, 0x54, 0x52, 0x55, 0x4e,
+	0x43, 0x41, 0x54, 0x45, 0x44, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x10, 0x07, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -769,47 +1542,70 @@ func file_server_entitymanager_proto_entity_proto_rawDescGZIP() []byte {
 	return file_server_entitymanager_proto_entity_proto_rawDescData
 }
 
-var file_server_entitymanager_proto_entity_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_server_entitymanager_proto_entity_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_server_entitymanager_proto_entity_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_server_entitymanager_proto_entity_proto_goTypes = []interface{}{
-	(*Options)(nil),             // 0: entity.Options
-	(*Entities)(nil),            // 1: entity.Entities
-	(*Config)(nil),              // 2: entity.Config
-	(*BootConfig)(nil),          // 3: entity.BootConfig
-	(*GNSIConfig)(nil),          // 4: entity.GNSIConfig
-	(*DHCPConfig)(nil),          // 5: entity.DHCPConfig
-	(*ControlCard)(nil),         // 6: entity.ControlCard
-	(*Chassis)(nil),             // 7: entity.Chassis
-	(*structpb.Struct)(nil),     // 8: google.protobuf.Struct
-	(*authz.UploadRequest)(nil), // 9: gnsi.authz.v1.UploadRequest
-	(*pathz.UploadRequest)(nil), // 10: gnsi.pathz.v1.UploadRequest
-	(*certz.UploadRequest)(nil), // 11: gnsi.certz.v1.UploadRequest
-	(*bootz.Credentials)(nil),   // 12: bootz.proto.Credentials
-	(bootz.BootMode)(0),         // 13: bootz.proto.BootMode
-	(*bootz.SoftwareImage)(nil), // 14: bootz.proto.SoftwareImage
+	(PasswordHashScheme)(0),     // 0: entity.PasswordHashScheme
+	(Fault)(0),                  // 1: entity.Fault
+	(*Options)(nil),             // 2: entity.Options
+	(*Entities)(nil),            // 3: entity.Entities
+	(*Profile)(nil),             // 4: entity.Profile
+	(*ProfileMatch)(nil),        // 5: entity.ProfileMatch
+	(*Config)(nil),              // 6: entity.Config
+	(*BootConfig)(nil),          // 7: entity.BootConfig
+	(*GNSIConfig)(nil),          // 8: entity.GNSIConfig
+	(*DHCPConfig)(nil),          // 9: entity.DHCPConfig
+	(*ControlCard)(nil),         // 10: entity.ControlCard
+	(*BootloaderPassword)(nil),  // 11: entity.BootloaderPassword
+	(*User)(nil),                // 12: entity.User
+	(*Role)(nil),                // 13: entity.Role
+	(*Chassis)(nil),             // 14: entity.Chassis
+	(bootz.BootMode)(0),         // 15: bootz.proto.BootMode
+	(*bootz.SoftwareImage)(nil), // 16: bootz.proto.SoftwareImage
+	(*structpb.Struct)(nil),     // 17: google.protobuf.Struct
+	(*authz.UploadRequest)(nil), // 18: gnsi.authz.v1.UploadRequest
+	(*pathz.UploadRequest)(nil), // 19: gnsi.pathz.v1.UploadRequest
+	(*certz.UploadRequest)(nil), // 20: gnsi.certz.v1.UploadRequest
+	(*bootz.Credentials)(nil),   // 21: bootz.proto.Credentials
 }
 var file_server_entitymanager_proto_entity_proto_depIdxs = []int32{
-	4,  // 0: entity.Options.gnsi_global_config:type_name -> entity.GNSIConfig
-	0,  // 1: entity.Entities.options:type_name -> entity.Options
-	7,  // 2: entity.Entities.chassis:type_name -> entity.Chassis
-	3,  // 3: entity.Config.boot_config:type_name -> entity.BootConfig
-	4,  // 4: entity.Config.gnsi_config:type_name -> entity.GNSIConfig
-	8,  // 5: entity.BootConfig.metadata:type_name -> google.protobuf.Struct
-	8,  // 6: entity.BootConfig.bootloader_config:type_name -> google.protobuf.Struct
-	9,  // 7: entity.GNSIConfig.authz_upload:type_name -> gnsi.authz.v1.UploadRequest
-	10, // 8: entity.GNSIConfig.pathz_upload:type_name -> gnsi.pathz.v1.UploadRequest
-	11, // 9: entity.GNSIConfig.certz_upload:type_name -> gnsi.certz.v1.UploadRequest
-	12, // 10: entity.GNSIConfig.credentials:type_name -> bootz.proto.Credentials
-	5,  // 11: entity.ControlCard.dhcp_config:type_name -> entity.DHCPConfig
-	13, // 12: entity.Chassis.boot_mode:type_name -> bootz.proto.BootMode
-	14, // 13: entity.Chassis.software_image:type_name -> bootz.proto.SoftwareImage
-	6,  // 14: entity.Chassis.controller_cards:type_name -> entity.ControlCard
-	2,  // 15: entity.Chassis.config:type_name -> entity.Config
-	5,  // 16: entity.Chassis.dhcp_config:type_name -> entity.DHCPConfig
-	17, // [17:17] is the sub-list for method output_type
-	17, // [17:17] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	8,  // 0: entity.Options.gnsi_global_config:type_name -> entity.GNSIConfig
+	13, // 1: entity.Options.roles:type_name -> entity.Role
+	2,  // 2: entity.Entities.options:type_name -> entity.Options
+	14, // 3: entity.Entities.chassis:type_name -> entity.Chassis
+	4,  // 4: entity.Entities.profiles:type_name -> entity.Profile
+	11, // 5: entity.Profile.bootloader_password:type_name -> entity.BootloaderPassword
+	15, // 6: entity.Profile.boot_mode:type_name -> bootz.proto.BootMode
+	16, // 7: entity.Profile.software_image:type_name -> bootz.proto.SoftwareImage
+	6,  // 8: entity.Profile.config:type_name -> entity.Config
+	5,  // 9: entity.Profile.fallback:type_name -> entity.ProfileMatch
+	7,  // 10: entity.Config.boot_config:type_name -> entity.BootConfig
+	8,  // 11: entity.Config.gnsi_config:type_name -> entity.GNSIConfig
+	17, // 12: entity.BootConfig.metadata:type_name -> google.protobuf.Struct
+	17, // 13: entity.BootConfig.bootloader_config:type_name -> google.protobuf.Struct
+	18, // 14: entity.GNSIConfig.authz_upload:type_name -> gnsi.authz.v1.UploadRequest
+	19, // 15: entity.GNSIConfig.pathz_upload:type_name -> gnsi.pathz.v1.UploadRequest
+	20, // 16: entity.GNSIConfig.certz_upload:type_name -> gnsi.certz.v1.UploadRequest
+	21, // 17: entity.GNSIConfig.credentials:type_name -> bootz.proto.Credentials
+	12, // 18: entity.GNSIConfig.users:type_name -> entity.User
+	9,  // 19: entity.ControlCard.dhcp_config:type_name -> entity.DHCPConfig
+	16, // 20: entity.ControlCard.software_image:type_name -> bootz.proto.SoftwareImage
+	7,  // 21: entity.ControlCard.boot_config:type_name -> entity.BootConfig
+	8,  // 22: entity.ControlCard.gnsi_config:type_name -> entity.GNSIConfig
+	0,  // 23: entity.BootloaderPassword.scheme:type_name -> entity.PasswordHashScheme
+	12, // 24: entity.Role.users:type_name -> entity.User
+	11, // 25: entity.Chassis.bootloader_password:type_name -> entity.BootloaderPassword
+	15, // 26: entity.Chassis.boot_mode:type_name -> bootz.proto.BootMode
+	16, // 27: entity.Chassis.software_image:type_name -> bootz.proto.SoftwareImage
+	10, // 28: entity.Chassis.controller_cards:type_name -> entity.ControlCard
+	6,  // 29: entity.Chassis.config:type_name -> entity.Config
+	9,  // 30: entity.Chassis.dhcp_config:type_name -> entity.DHCPConfig
+	1,  // 31: entity.Chassis.faults:type_name -> entity.Fault
+	32, // [32:32] is the sub-list for method output_type
+	32, // [32:32] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_server_entitymanager_proto_entity_proto_init() }
@@ -843,7 +1639,7 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Config); i {
+			switch v := v.(*Profile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -855,7 +1651,7 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BootConfig); i {
+			switch v := v.(*ProfileMatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -867,7 +1663,7 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GNSIConfig); i {
+			switch v := v.(*Config); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -879,7 +1675,7 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DHCPConfig); i {
+			switch v := v.(*BootConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -891,7 +1687,7 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ControlCard); i {
+			switch v := v.(*GNSIConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -903,6 +1699,66 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 		file_server_entitymanager_proto_entity_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DHCPConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_entitymanager_proto_entity_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ControlCard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_entitymanager_proto_entity_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BootloaderPassword); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_entitymanager_proto_entity_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_entitymanager_proto_entity_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Role); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_server_entitymanager_proto_entity_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Chassis); i {
 			case 0:
 				return &v.state
@@ -915,18 +1771,29 @@ func file_server_entitymanager_proto_entity_proto_init() {
 			}
 		}
 	}
+	file_server_entitymanager_proto_entity_proto_msgTypes[9].OneofWrappers = []interface{}{
+		(*BootloaderPassword_PasswordFile)(nil),
+		(*BootloaderPassword_Secret)(nil),
+		(*BootloaderPassword_Generate)(nil),
+	}
+	file_server_entitymanager_proto_entity_proto_msgTypes[10].OneofWrappers = []interface{}{
+		(*User_PasswordHash)(nil),
+		(*User_PasswordFile)(nil),
+		(*User_PasswordSecret)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_server_entitymanager_proto_entity_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_server_entitymanager_proto_entity_proto_goTypes,
 		DependencyIndexes: file_server_entitymanager_proto_entity_proto_depIdxs,
+		EnumInfos:         file_server_entitymanager_proto_entity_proto_enumTypes,
 		MessageInfos:      file_server_entitymanager_proto_entity_proto_msgTypes,
 	}.Build()
 	File_server_entitymanager_proto_entity_proto = out.File
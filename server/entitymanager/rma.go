@@ -0,0 +1,186 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	log "github.com/golang/glog"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	admpb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// OVGenerator returns an ownership voucher for the control card serial.
+type OVGenerator func(serial string) ([]byte, error)
+
+// WithOVGenerator sets the generator used for the ownership vouchers of approved devices, and of
+// replacement control cards when none is provided with the replacement.
+func WithOVGenerator(g OVGenerator) Option {
+	return func(m *InMemoryEntityManager) {
+		m.ovGenerator = g
+	}
+}
+
+// ControlCardReplacement describes the RMA replacement of a control card.
+type ControlCardReplacement struct {
+	// Manufacturer of the chassis, to disambiguate serials. Optional.
+	Manufacturer string
+	OldSerial    string
+	NewSerial    string
+	// OwnershipVoucher is the OV of the new control card. If unset, the OV already known for the
+	// serial is used, or one is generated.
+	OwnershipVoucher []byte
+	// HardwareAddress is the MAC address of the management interface of the new control card,
+	// which replaces the one of the old card in its DHCP config.
+	HardwareAddress string
+}
+
+// ReplaceControlCard swaps a control card of a chassis for a replacement card in the same slot,
+// which keeps the part number, DHCP config and overrides of the old card. The old card's status and OV are
+// retired, and the replacement is recorded in the history of the chassis and of the old card, whose
+// history is kept.
+func (m *InMemoryEntityManager) ReplaceControlCard(ctx context.Context, r *ControlCardReplacement) (*epb.Chassis, error) {
+	if r.OldSerial == "" || r.NewSerial == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "old and new control card serials are required")
+	}
+	if r.OldSerial == r.NewSerial {
+		return nil, status.Errorf(codes.InvalidArgument, "replacement control card has the same serial %v", r.OldSerial)
+	}
+	if len(r.OwnershipVoucher) != 0 {
+		ov, err := ownershipvoucher.Unmarshal(r.OwnershipVoucher, nil)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ownership voucher: %v", err)
+		}
+		if got := ov.OV.SerialNumber; got != r.NewSerial {
+			return nil, status.Errorf(codes.InvalidArgument, "ownership voucher is issued for serial %v, not %v", got, r.NewSerial)
+		}
+	}
+	ov, err := m.replacementOV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chassis, slot, err := m.replacedCard(r)
+	if err != nil {
+		return nil, err
+	}
+	// The chassis may be shared with snapshots, so the replacement is made on a copy.
+	replaced := proto.Clone(chassis).(*epb.Chassis)
+	// The replacement keeps the config and overrides of the slot.
+	replacement := replaced.GetControllerCards()[slot]
+	replacement.SerialNumber = r.NewSerial
+	if replacement.GetDhcpConfig() != nil {
+		// The hardware address belongs to the old card.
+		replacement.DhcpConfig.HardwareAddress = r.HardwareAddress
+	} else if r.HardwareAddress != "" {
+		replacement.DhcpConfig = &epb.DHCPConfig{HardwareAddress: r.HardwareAddress}
+	}
+	m.replaceChassis(m.position(chassis), replaced)
+
+	m.updateOVs(service.OVList{r.NewSerial: ov}, r.OldSerial)
+	delete(m.controlCardStatuses, r.OldSerial)
+	m.controlCardStatuses[r.NewSerial] = bpb.ControlCardState_CONTROL_CARD_STATUS_UNSPECIFIED
+
+	cs := m.chassisStatus(replaced)
+	delete(cs.ControlCards, r.OldSerial)
+	now, source := time.Now(), sourceAddress(ctx)
+	msg := fmt.Sprintf("control card %v replaced by %v", r.OldSerial, r.NewSerial)
+	m.recordTransition(cs, StatusTransition{
+		Serial:  r.NewSerial,
+		Message: msg,
+		Time:    now,
+		Source:  source,
+	})
+	cs.ControlCardHistory[r.OldSerial] = appendBounded(cs.ControlCardHistory[r.OldSerial], StatusTransition{
+		Serial:  r.OldSerial,
+		Message: msg,
+		Time:    now,
+		Source:  source,
+	}, m.historySize)
+	m.events.Publish(&admpb.Event{
+		Type:               admpb.EventType_EVENT_TYPE_CONTROL_CARD_REPLACED,
+		Timestamp:          timestamppb.New(now),
+		Manufacturer:       replaced.GetManufacturer(),
+		ChassisSerial:      replaced.GetSerialNumber(),
+		ControlCardSerials: []string{r.OldSerial, r.NewSerial},
+		Source:             source,
+		Message:            msg,
+	})
+	log.Infof("Chassis %v: %v", replaced.GetSerialNumber(), msg)
+	return proto.Clone(replaced).(*epb.Chassis), nil
+}
+
+// replacedCard returns the chassis holding the old control card of the replacement, and the
+// position of the card in the chassis. The caller must hold m.mu.
+func (m *InMemoryEntityManager) replacedCard(r *ControlCardReplacement) (*epb.Chassis, int, error) {
+	if m.secArtifacts == nil {
+		return nil, 0, status.Errorf(codes.Internal, "security artifact is missing")
+	}
+	if ch := first(m.index.byControlCard[r.NewSerial], r.Manufacturer, true); ch != nil {
+		return nil, 0, status.Errorf(codes.AlreadyExists, "control card %v is already in chassis %v", r.NewSerial, ch.GetSerialNumber())
+	}
+	chassis := first(m.index.byControlCard[r.OldSerial], r.Manufacturer, true)
+	if chassis == nil {
+		return nil, 0, status.Errorf(codes.NotFound, "no chassis with control card %v", r.OldSerial)
+	}
+	for i, cc := range chassis.GetControllerCards() {
+		if cc.GetSerialNumber() == r.OldSerial {
+			return chassis, i, nil
+		}
+	}
+	return nil, 0, status.Errorf(codes.NotFound, "no chassis with control card %v", r.OldSerial)
+}
+
+// replacementOV returns the OV of the new control card of the replacement: the one provided with
+// it, the one already known for the serial, or a generated one. It must be called without holding
+// m.mu.
+func (m *InMemoryEntityManager) replacementOV(r *ControlCardReplacement) ([]byte, error) {
+	m.mu.Lock()
+	_, _, err := m.replacedCard(r)
+	var known []byte
+	if err == nil {
+		known = m.secArtifacts.OV[r.NewSerial]
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	ov := r.OwnershipVoucher
+	if len(ov) == 0 {
+		ov = known
+	}
+	if len(ov) == 0 && m.ovGenerator != nil {
+		if ov, err = m.ovGenerator(r.NewSerial); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to generate ownership voucher for %v: %v", r.NewSerial, err)
+		}
+	}
+	if len(ov) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "no ownership voucher for control card %v", r.NewSerial)
+	}
+	return ov, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entitymanager
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// Snapshot is a consistent view of the inventory at a version. Snapshots are shared between
+// readers, so neither the snapshot nor its chassis may be modified.
+type Snapshot struct {
+	// Version is incremented by each change of the inventory.
+	Version uint64
+	// Chassis holds copies of the chassis of the inventory, in inventory order.
+	Chassis []*epb.Chassis
+}
+
+// snapshotState tracks the version of the inventory and the copies its snapshots are built from.
+// It is guarded by the mutex of the entity manager.
+type snapshotState struct {
+	version uint64
+	// current is the snapshot of the current version, if one was taken.
+	current *Snapshot
+	// frozen maps the chassis of the inventory to their copy in snapshots, so that only the
+	// chassis which changed are copied again.
+	frozen map[*epb.Chassis]*epb.Chassis
+	// changed is closed when the version is incremented.
+	changed chan struct{}
+}
+
+// inventoryChanged increments the version of the inventory after the provided chassis were added,
+// removed or modified. The caller must hold m.mu.
+func (m *InMemoryEntityManager) inventoryChanged(chassis ...*epb.Chassis) {
+	s := &m.snapshots
+	s.version++
+	s.current = nil
+	for _, ch := range chassis {
+		delete(s.frozen, ch)
+	}
+	if s.changed != nil {
+		close(s.changed)
+		s.changed = nil
+	}
+}
+
+// snapshot returns the snapshot of the current version. The caller must hold m.mu.
+func (m *InMemoryEntityManager) snapshot() *Snapshot {
+	s := &m.snapshots
+	if s.current != nil {
+		return s.current
+	}
+	frozen := make(map[*epb.Chassis]*epb.Chassis, len(m.chassisInventory))
+	snap := &Snapshot{
+		Version: s.version,
+		Chassis: make([]*epb.Chassis, len(m.chassisInventory)),
+	}
+	for i, ch := range m.chassisInventory {
+		c, ok := s.frozen[ch]
+		if !ok {
+			c = proto.Clone(ch).(*epb.Chassis)
+		}
+		frozen[ch] = c
+		snap.Chassis[i] = c
+	}
+	// Copies of removed chassis are dropped along with the old map.
+	s.frozen = frozen
+	s.current = snap
+	return snap
+}
+
+// Snapshot returns a consistent view of the current inventory.
+func (m *InMemoryEntityManager) Snapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot()
+}
+
+// WatchSnapshot blocks until the version of the inventory is greater than the provided version,
+// then returns a snapshot of the inventory. It returns the error of the context if it is done
+// first.
+func (m *InMemoryEntityManager) WatchSnapshot(ctx context.Context, version uint64) (*Snapshot, error) {
+	for {
+		m.mu.Lock()
+		if m.snapshots.version > version {
+			snap := m.snapshot()
+			m.mu.Unlock()
+			return snap, nil
+		}
+		if m.snapshots.changed == nil {
+			m.snapshots.changed = make(chan struct{})
+		}
+		changed := m.snapshots.changed
+		m.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events distributes bootstrap events to watchers.
+//
+// Events are kept in a bounded buffer and numbered in publication order. Watchers
+// consume them with a cursor, so they can resume after a reconnect without missing
+// events as long as the events are still buffered.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+// DefaultBufferSize is the default number of events kept for watchers to resume from.
+const DefaultBufferSize = 4096
+
+// Bus buffers published events for watchers.
+type Bus struct {
+	// epoch identifies this bus in cursors, so cursors of a previous server run are rejected.
+	epoch string
+	size  int
+	now   func() time.Time
+
+	mu sync.Mutex
+	// events holds the buffered events in sequence order.
+	events []*apb.Event
+	// last is the sequence number of the last published event.
+	last uint64
+	// published is closed and replaced on every publication.
+	published chan struct{}
+}
+
+// NewBus returns a bus which buffers the last size events. A size below 1 buffers the last event.
+func NewBus(size int) *Bus {
+	if size < 1 {
+		size = 1
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	return &Bus{
+		epoch:     hex.EncodeToString(b),
+		size:      size,
+		now:       time.Now,
+		published: make(chan struct{}),
+	}
+}
+
+// Publish stamps the event with its sequence number, cursor and time, and makes it available to watchers.
+// Publishing to a nil bus is a no-op.
+func (b *Bus) Publish(e *apb.Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last++
+	e.Sequence = b.last
+	e.Cursor = b.cursor(b.last)
+	if e.Timestamp == nil {
+		e.Timestamp = timestamppb.New(b.now())
+	}
+	b.events = append(b.events, e)
+	if len(b.events) > b.size {
+		b.events = append([]*apb.Event{}, b.events[len(b.events)-b.size:]...)
+	}
+	close(b.published)
+	b.published = make(chan struct{})
+}
+
+func (b *Bus) cursor(seq uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// Cursor returns the cursor of the last published event. Watching from it only yields new events.
+func (b *Bus) Cursor() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cursor(b.last)
+}
+
+// OldestCursor returns the cursor to watch from to get every buffered event.
+func (b *Bus) OldestCursor() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) == 0 {
+		return b.cursor(b.last)
+	}
+	return b.cursor(b.events[0].GetSequence() - 1)
+}
+
+// parseCursor returns the sequence number of the event the cursor points to.
+func (b *Bus) parseCursor(cursor string) (uint64, error) {
+	epoch, seq, ok := strings.Cut(cursor, "-")
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "malformed cursor %q", cursor)
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "malformed cursor %q", cursor)
+	}
+	if epoch != b.epoch {
+		return 0, status.Errorf(codes.OutOfRange, "cursor %q is from another server run", cursor)
+	}
+	return n, nil
+}
+
+// Since returns the buffered events after the cursor, and a channel which is closed when
+// further events are published. It returns OutOfRange if events after the cursor were
+// already dropped from the buffer or if the cursor is from another server run.
+func (b *Bus) Since(cursor string) ([]*apb.Event, <-chan struct{}, error) {
+	after, err := b.parseCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if after > b.last {
+		return nil, nil, status.Errorf(codes.OutOfRange, "cursor %q is ahead of the last event %d", cursor, b.last)
+	}
+	if after == b.last {
+		return nil, b.published, nil
+	}
+	oldest := b.events[0].GetSequence()
+	if after+1 < oldest {
+		return nil, nil, status.Errorf(codes.OutOfRange, "events after cursor %q were dropped, the oldest buffered event is %d", cursor, oldest)
+	}
+	return append([]*apb.Event{}, b.events[after+1-oldest:]...), b.published, nil
+}
+
+// Filter selects the events a watcher is interested in.
+type Filter struct {
+	Manufacturer string
+	// Serial matches the chassis serial or any of the control card serials of an event.
+	Serial string
+	Types  []apb.EventType
+}
+
+// Match reports whether the event passes the filter.
+func (f *Filter) Match(e *apb.Event) bool {
+	if f.Manufacturer != "" && e.GetManufacturer() != f.Manufacturer {
+		return false
+	}
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			found = found || t == e.GetType()
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Serial == "" || e.GetChassisSerial() == f.Serial {
+		return true
+	}
+	for _, s := range e.GetControlCardSerials() {
+		if s == f.Serial {
+			return true
+		}
+	}
+	return false
+}
+
+// Watch calls send for each event after the cursor which passes the filter, until the context
+// is done or send fails. If the cursor is empty, only events published after the call are sent.
+func (b *Bus) Watch(ctx context.Context, cursor string, f *Filter, send func(*apb.Event) error) error {
+	if cursor == "" {
+		cursor = b.Cursor()
+	}
+	for {
+		events, published, err := b.Since(cursor)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			cursor = e.GetCursor()
+			if !f.Match(e) {
+				continue
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+		if len(events) > 0 {
+			continue
+		}
+		select {
+		case <-published:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+func sequences(events []*apb.Event) []uint64 {
+	var seqs []uint64
+	for _, e := range events {
+		seqs = append(seqs, e.GetSequence())
+	}
+	return seqs
+}
+
+func TestSince(t *testing.T) {
+	b := NewBus(3)
+	start := b.Cursor()
+	for i := 0; i < 2; i++ {
+		b.Publish(&apb.Event{Type: apb.EventType_EVENT_TYPE_DATA_SERVED})
+	}
+	got, _, err := b.Since(start)
+	if err != nil {
+		t.Fatalf("Since() err = %v", err)
+	}
+	if want := []uint64{1, 2}; !cmp.Equal(sequences(got), want) {
+		t.Errorf("Since() = %v, want %v", sequences(got), want)
+	}
+	if got[0].GetTimestamp() == nil || got[0].GetCursor() == "" {
+		t.Errorf("Since() event %v has no timestamp or cursor", got[0])
+	}
+
+	// Resuming from the cursor of an event yields the following events only.
+	mid := got[0].GetCursor()
+	got, _, err = b.Since(mid)
+	if err != nil {
+		t.Fatalf("Since() err = %v", err)
+	}
+	if want := []uint64{2}; !cmp.Equal(sequences(got), want) {
+		t.Errorf("Since(%v) = %v, want %v", mid, sequences(got), want)
+	}
+
+	// Caught up watchers are notified of the next event.
+	got, published, err := b.Since(b.Cursor())
+	if err != nil || len(got) != 0 {
+		t.Fatalf("Since(last) = %v, %v, want no events", got, err)
+	}
+	b.Publish(&apb.Event{})
+	select {
+	case <-published:
+	default:
+		t.Errorf("publication channel not closed after Publish()")
+	}
+
+	// Two more events overflow the buffer of 3, dropping events 1 and 2.
+	b.Publish(&apb.Event{})
+	b.Publish(&apb.Event{})
+	if _, _, err := b.Since(mid); status.Code(err) != codes.OutOfRange {
+		t.Errorf("Since() of dropped events err = %v, want OutOfRange", err)
+	}
+	// Watchers which fell behind resume from the oldest buffered event.
+	got, _, err = b.Since(b.OldestCursor())
+	if err != nil {
+		t.Fatalf("Since(OldestCursor()) err = %v", err)
+	}
+	if want := []uint64{3, 4, 5}; !cmp.Equal(sequences(got), want) {
+		t.Errorf("Since(OldestCursor()) = %v, want %v", sequences(got), want)
+	}
+	if _, _, err := b.Since(NewBus(3).Cursor()); status.Code(err) != codes.OutOfRange {
+		t.Errorf("Since() with a cursor of another bus err = %v, want OutOfRange", err)
+	}
+	if _, _, err := b.Since("garbage"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Since() with a malformed cursor err = %v, want InvalidArgument", err)
+	}
+}
+
+// TestSmallBus tests that a bus of size zero buffers the last event instead of none.
+func TestSmallBus(t *testing.T) {
+	b := NewBus(0)
+	start := b.Cursor()
+	b.Publish(&apb.Event{})
+	b.Publish(&apb.Event{})
+	if _, _, err := b.Since(start); status.Code(err) != codes.OutOfRange {
+		t.Errorf("Since() of dropped events err = %v, want OutOfRange", err)
+	}
+	got, _, err := b.Since(b.OldestCursor())
+	if err != nil {
+		t.Fatalf("Since(OldestCursor()) err = %v", err)
+	}
+	if want := []uint64{2}; !cmp.Equal(sequences(got), want) {
+		t.Errorf("Since(OldestCursor()) = %v, want %v", sequences(got), want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	e := &apb.Event{
+		Type:               apb.EventType_EVENT_TYPE_STATUS_REPORTED,
+		Manufacturer:       "Cisco",
+		ChassisSerial:      "123",
+		ControlCardSerials: []string{"123A", "123B"},
+	}
+	tests := []struct {
+		desc   string
+		filter *Filter
+		want   bool
+	}{
+		{"Empty filter", &Filter{}, true},
+		{"Chassis serial", &Filter{Serial: "123"}, true},
+		{"Control card serial", &Filter{Serial: "123B", Manufacturer: "Cisco"}, true},
+		{"Other serial", &Filter{Serial: "456"}, false},
+		{"Other manufacturer", &Filter{Manufacturer: "Arista"}, false},
+		{"Matching type", &Filter{Types: []apb.EventType{apb.EventType_EVENT_TYPE_DATA_SERVED, apb.EventType_EVENT_TYPE_STATUS_REPORTED}}, true},
+		{"Other type", &Filter{Types: []apb.EventType{apb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED}}, false},
+	}
+	for _, test := range tests {
+		if got := test.filter.Match(e); got != test.want {
+			t.Errorf("%s: Match() = %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	b := NewBus(10)
+	b.Publish(&apb.Event{ChassisSerial: "old"})
+	cursor := b.Cursor()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errStop := errors.New("stop")
+	var got []string
+	done := make(chan error)
+	go func() {
+		done <- b.Watch(ctx, cursor, &Filter{Serial: "123"}, func(e *apb.Event) error {
+			got = append(got, e.GetMessage())
+			if len(got) == 2 {
+				return errStop
+			}
+			return nil
+		})
+	}()
+	b.Publish(&apb.Event{ChassisSerial: "other"})
+	b.Publish(&apb.Event{ChassisSerial: "123", Message: "first"})
+	b.Publish(&apb.Event{ChassisSerial: "456", Message: "filtered"})
+	b.Publish(&apb.Event{ChassisSerial: "123", Message: "second"})
+	if err := <-done; err != errStop {
+		t.Fatalf("Watch() err = %v, want %v", err, errStop)
+	}
+	if want := []string{"first", "second"}; !cmp.Equal(got, want) {
+		t.Errorf("Watch() sent %v, want %v", got, want)
+	}
+
+	// Resuming from a cursor replays the buffered events after it.
+	got = nil
+	err := b.Watch(ctx, cursor, &Filter{}, func(e *apb.Event) error {
+		got = append(got, e.GetChassisSerial())
+		if e.GetMessage() == "first" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Fatalf("Watch() err = %v, want %v", err, errStop)
+	}
+	if got[0] != "other" || got[len(got)-1] != "123" {
+		t.Errorf("Watch() from cursor sent %v, want the events after the cursor", got)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package faults wraps an entity manager to serve deliberately broken bootstrap responses.
+//
+// It is used for negative testing, i.e. to check that devices reject bad data. Faults
+// are configured per chassis, either from the inventory or at runtime, and each fault
+// corrupts a specific part of the GetBootstrapDataResponse.
+package faults
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/openconfig/bootz/common/certserial"
+	ownercertificate "github.com/openconfig/bootz/common/owner_certificate"
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
+	"github.com/openconfig/bootz/common/signature"
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	log "github.com/golang/glog"
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+// Injector is an entity manager that corrupts the responses of the wrapped entity manager
+// according to the faults configured for each chassis.
+// Faults that target the signed artifacts (nonce, signature, OV, OC and serialized data)
+// only apply to requests that include a nonce, since insecure responses are not signed.
+type Injector struct {
+	service.EntityManager
+
+	mu sync.Mutex
+	// faults maps a chassis or control card serial number to the faults to inject.
+	faults map[string][]epb.Fault
+	// security artifacts used to mint broken OVs.
+	secArtifacts *service.SecurityArtifacts
+	// rogue ownership certificate which is not signed by the PDC. Created on first use.
+	rogueOC    *x509.Certificate
+	rogueOCKey crypto.PrivateKey
+}
+
+// New returns an Injector wrapping the provided entity manager.
+func New(em service.EntityManager, sa *service.SecurityArtifacts) *Injector {
+	return &Injector{
+		EntityManager: em,
+		faults:        map[string][]epb.Fault{},
+		secArtifacts:  sa,
+	}
+}
+
+// SetFaults replaces the faults injected for the chassis or control card with the provided serial.
+// Calling SetFaults without faults clears them.
+func (i *Injector) SetFaults(serial string, faults ...epb.Fault) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(faults) == 0 {
+		delete(i.faults, serial)
+		log.Infof("Cleared faults for %v", serial)
+		return
+	}
+	i.faults[serial] = append([]epb.Fault{}, faults...)
+	log.Infof("Injecting faults %v for %v", faults, serial)
+}
+
+// Faults returns the faults injected for the chassis or control card with the provided serial.
+func (i *Injector) Faults(serial string) []epb.Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]epb.Fault{}, i.faults[serial]...)
+}
+
+// AllFaults returns the faults injected for each chassis or control card serial.
+func (i *Injector) AllFaults() map[string][]epb.Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := make(map[string][]epb.Fault, len(i.faults))
+	for serial, f := range i.faults {
+		out[serial] = append([]epb.Fault{}, f...)
+	}
+	return out
+}
+
+// LoadInventory sets the faults directives found in the inventory. The faults of each chassis
+// are registered under the chassis serial and the serials of its control cards.
+func (i *Injector) LoadInventory(chassis []*epb.Chassis) {
+	for _, ch := range chassis {
+		if len(ch.GetFaults()) == 0 {
+			continue
+		}
+		i.SetFaults(ch.GetSerialNumber(), ch.GetFaults()...)
+		for _, cc := range ch.GetControllerCards() {
+			i.SetFaults(cc.GetSerialNumber(), ch.GetFaults()...)
+		}
+	}
+}
+
+// faultsFor returns the set of faults for the first of the provided serials which has any.
+func (i *Injector) faultsFor(serials ...string) map[epb.Fault]bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	set := map[epb.Fault]bool{}
+	for _, s := range serials {
+		if s == "" {
+			continue
+		}
+		if f, ok := i.faults[s]; ok {
+			for _, v := range f {
+				set[v] = true
+			}
+			break
+		}
+	}
+	return set
+}
+
+// GetBootstrapData fetches the bootstrap data from the wrapped entity manager and corrupts the image hash if requested.
+func (i *Injector) GetBootstrapData(ctx context.Context, lookup *service.EntityLookup, controllerCard *bpb.ControlCard) (*bpb.BootstrapDataResponse, error) {
+	resp, err := i.EntityManager.GetBootstrapData(ctx, lookup, controllerCard)
+	if err != nil {
+		return nil, err
+	}
+	if i.faultsFor(lookup.SerialNumber, controllerCard.GetSerialNumber())[epb.Fault_FAULT_WRONG_IMAGE_HASH] {
+		resp = proto.Clone(resp).(*bpb.BootstrapDataResponse)
+		if resp.IntendedImage == nil {
+			resp.IntendedImage = &bpb.SoftwareImage{}
+		}
+		hash := sha256.Sum256([]byte(resp.GetIntendedImage().GetOsImageHash()))
+		resp.IntendedImage.OsImageHash = hex.EncodeToString(hash[:])
+		log.Infof("Injected fault %v for %v", epb.Fault_FAULT_WRONG_IMAGE_HASH, resp.GetSerialNum())
+	}
+	return resp, nil
+}
+
+// Sign signs the response with the wrapped entity manager and corrupts the signed artifacts if requested.
+func (i *Injector) Sign(ctx context.Context, resp *bpb.GetBootstrapDataResponse, chassis *service.EntityLookup, controllerCard string) error {
+	f := i.faultsFor(chassis.SerialNumber, controllerCard)
+	if f[epb.Fault_FAULT_WRONG_NONCE] {
+		if err := wrongNonce(resp); err != nil {
+			return err
+		}
+	}
+	if err := i.EntityManager.Sign(ctx, resp, chassis, controllerCard); err != nil {
+		return err
+	}
+	if f[epb.Fault_FAULT_OC_NOT_CHAINED] {
+		if err := i.rogueSign(resp); err != nil {
+			return err
+		}
+	}
+	if f[epb.Fault_FAULT_EXPIRED_OV] {
+		now := time.Now()
+		ov, err := i.ownershipVoucher(controllerCard, now.AddDate(-2, 0, 0), now.AddDate(-1, 0, 0))
+		if err != nil {
+			return err
+		}
+		resp.OwnershipVoucher = ov
+	}
+	if f[epb.Fault_FAULT_OV_SERIAL_MISMATCH] {
+		now := time.Now()
+		ov, err := i.ownershipVoucher(controllerCard+"-MISMATCH", now, now.AddDate(1, 0, 0))
+		if err != nil {
+			return err
+		}
+		resp.OwnershipVoucher = ov
+	}
+	if f[epb.Fault_FAULT_TRUNCATED_DATA] {
+		data := resp.GetSerializedBootstrapData()
+		resp.SerializedBootstrapData = data[:len(data)/2]
+	}
+	if f[epb.Fault_FAULT_CORRUPT_SIGNATURE] {
+		sig, err := base64.StdEncoding.DecodeString(resp.GetResponseSignature())
+		if err != nil || len(sig) == 0 {
+			sig = []byte("corrupt")
+		}
+		sig[0] ^= 0xff
+		resp.ResponseSignature = base64.StdEncoding.EncodeToString(sig)
+	}
+	for fault := range f {
+		log.Infof("Injected fault %v for %v", fault, controllerCard)
+	}
+	return nil
+}
+
+// wrongNonce replaces the nonce in the serialized bootstrap data with a random one.
+func wrongNonce(resp *bpb.GetBootstrapDataResponse) error {
+	signed := &bpb.BootstrapDataSigned{}
+	if err := proto.Unmarshal(resp.GetSerializedBootstrapData(), signed); err != nil {
+		return status.Errorf(codes.Internal, "unable to unmarshal serialized bootstrap data: %v", err)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	signed.Nonce = base64.StdEncoding.EncodeToString(b)
+	data, err := proto.Marshal(signed)
+	if err != nil {
+		return err
+	}
+	resp.SignedResponse = signed
+	resp.SerializedBootstrapData = data
+	return nil
+}
+
+// ownershipVoucher mints an OV signed by the vendor CA with the provided validity.
+func (i *Injector) ownershipVoucher(serial string, createdOn, expiresOn time.Time) ([]byte, error) {
+	if i.secArtifacts == nil {
+		return nil, status.Errorf(codes.Internal, "security artifact is missing")
+	}
+	return ownershipvoucher.NewWithValidity(serial, i.secArtifacts.PDC, i.secArtifacts.VendorCA, i.secArtifacts.VendorCAPrivateKey, createdOn, expiresOn)
+}
+
+// rogueSign signs the response with an ownership certificate which does not chain to the PDC.
+func (i *Injector) rogueSign(resp *bpb.GetBootstrapDataResponse) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.rogueOC == nil {
+		oc, ocKey, err := rogueOwnerCertificate()
+		if err != nil {
+			return err
+		}
+		i.rogueOC, i.rogueOCKey = oc, ocKey
+	}
+	sig, err := signature.Sign(i.rogueOCKey, resp.GetSerializedBootstrapData())
+	if err != nil {
+		return err
+	}
+	ocCMS, err := ownercertificate.GenerateCMS(i.rogueOC, i.rogueOCKey)
+	if err != nil {
+		return err
+	}
+	resp.ResponseSignature = sig
+	resp.OwnershipCertificate = ocCMS
+	return nil
+}
+
+// rogueOwnerCertificate returns a self-signed ownership certificate, which chains to no pinned
+// domain cert.
+func rogueOwnerCertificate() (*x509.Certificate, crypto.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := certserial.Random()
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "Rogue Owner Certificate", Organization: []string{"Rogue"}},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
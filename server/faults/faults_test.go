@@ -0,0 +1,193 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package faults
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/bootz/conformance"
+	"github.com/openconfig/bootz/server/entitymanager"
+	"github.com/openconfig/bootz/server/service"
+	artifacts "github.com/openconfig/bootz/testdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
+)
+
+var chassis = &bpb.ChassisDescriptor{
+	Manufacturer: "Cisco",
+	PartNumber:   "123",
+	ControlCards: []*bpb.ControlCard{
+		{SerialNumber: "123A", PartNumber: "123A", Slot: 1},
+		{SerialNumber: "123B", PartNumber: "123B", Slot: 2},
+	},
+}
+
+func TestSetFaults(t *testing.T) {
+	inj := New(nil, nil)
+	inj.LoadInventory([]*epb.Chassis{{
+		SerialNumber:    "123",
+		ControllerCards: []*epb.ControlCard{{SerialNumber: "123A"}},
+		Faults:          []epb.Fault{epb.Fault_FAULT_WRONG_NONCE},
+	}, {
+		SerialNumber: "456",
+	}})
+	want := []epb.Fault{epb.Fault_FAULT_WRONG_NONCE}
+	for _, serial := range []string{"123", "123A"} {
+		if got := inj.Faults(serial); !cmp.Equal(got, want) {
+			t.Errorf("Faults(%q) = %v, want %v", serial, got, want)
+		}
+	}
+	if got := inj.Faults("456"); len(got) != 0 {
+		t.Errorf("Faults(%q) = %v, want none", "456", got)
+	}
+
+	inj.SetFaults("123A", epb.Fault_FAULT_CORRUPT_SIGNATURE, epb.Fault_FAULT_EXPIRED_OV)
+	if got := inj.faultsFor("", "123A"); !got[epb.Fault_FAULT_CORRUPT_SIGNATURE] || !got[epb.Fault_FAULT_EXPIRED_OV] || got[epb.Fault_FAULT_WRONG_NONCE] {
+		t.Errorf("faultsFor(%q) = %v, want corrupt signature and expired OV", "123A", got)
+	}
+	inj.SetFaults("123A")
+	if got := inj.Faults("123A"); len(got) != 0 {
+		t.Errorf("Faults(%q) after clearing = %v, want none", "123A", got)
+	}
+}
+
+func TestInjector(t *testing.T) {
+	sa, err := artifacts.GenerateSecurityArtifacts([]string{"123A", "123B"}, "Google", "Cisco")
+	if err != nil {
+		t.Fatalf("unable to generate server artifacts: %v", err)
+	}
+	em, err := entitymanager.New("../../testdata/inventory.prototxt", sa)
+	if err != nil {
+		t.Fatalf("unable to create entity manager: %v", err)
+	}
+	inj := New(em, sa)
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{*sa.TLSKeypair},
+	})))
+	bpb.RegisterBootstrapServer(s, service.New(inj))
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	go s.Serve(lis)
+	defer s.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	if err != nil {
+		t.Fatalf("unable to dial: %v", err)
+	}
+	defer conn.Close()
+	client := bpb.NewBootstrapClient(conn)
+	vendorCA := x509.NewCertPool()
+	vendorCA.AddCert(sa.VendorCA)
+
+	tests := []struct {
+		desc  string
+		fault epb.Fault
+		// The conformance checks of which at least one must fail.
+		wantFailed []string
+	}{{
+		desc:       "Wrong nonce",
+		fault:      epb.Fault_FAULT_WRONG_NONCE,
+		wantFailed: []string{"nonce_echo"},
+	}, {
+		desc:       "Corrupt signature",
+		fault:      epb.Fault_FAULT_CORRUPT_SIGNATURE,
+		wantFailed: []string{"response_signature"},
+	}, {
+		desc:       "Expired OV",
+		fault:      epb.Fault_FAULT_EXPIRED_OV,
+		wantFailed: []string{"ownership_voucher"},
+	}, {
+		desc:       "OV serial mismatch",
+		fault:      epb.Fault_FAULT_OV_SERIAL_MISMATCH,
+		wantFailed: []string{"ownership_voucher"},
+	}, {
+		desc:       "OC not chained to PDC",
+		fault:      epb.Fault_FAULT_OC_NOT_CHAINED,
+		wantFailed: []string{"ownership_certificate_chain"},
+	}, {
+		desc:       "Truncated data",
+		fault:      epb.Fault_FAULT_TRUNCATED_DATA,
+		wantFailed: []string{"serialized_bootstrap_data", "response_signature"},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			inj.SetFaults("123A", test.fault)
+			defer inj.SetFaults("123A")
+			report, err := conformance.Run(context.Background(), client, &conformance.Config{
+				Descriptor: chassis,
+				VendorCA:   vendorCA,
+			})
+			if err != nil {
+				t.Fatalf("conformance.Run() err = %v, want nil", err)
+			}
+			failed := map[string]bool{}
+			for _, r := range report.Results {
+				if r.Outcome == conformance.Fail {
+					failed[r.Name] = true
+				}
+			}
+			found := false
+			for _, name := range test.wantFailed {
+				found = found || failed[name]
+			}
+			if !found {
+				t.Errorf("conformance checks %v passed with fault %v, want failure", test.wantFailed, test.fault)
+			}
+		})
+	}
+
+	t.Run("Wrong image hash", func(t *testing.T) {
+		ctx := context.Background()
+		lookup := &service.EntityLookup{Manufacturer: "Cisco", SerialNumber: "123"}
+		cc := &bpb.ControlCard{SerialNumber: "123A"}
+		want, err := em.GetBootstrapData(ctx, lookup, cc)
+		if err != nil {
+			t.Fatalf("GetBootstrapData() err = %v, want nil", err)
+		}
+		inj.SetFaults("123", epb.Fault_FAULT_WRONG_IMAGE_HASH)
+		defer inj.SetFaults("123")
+		got, err := inj.GetBootstrapData(ctx, lookup, cc)
+		if err != nil {
+			t.Fatalf("GetBootstrapData() err = %v, want nil", err)
+		}
+		if got.GetIntendedImage().GetOsImageHash() == want.GetIntendedImage().GetOsImageHash() {
+			t.Errorf("GetBootstrapData() image hash = %v, want a different hash", got.GetIntendedImage().GetOsImageHash())
+		}
+	})
+
+	t.Run("No faults", func(t *testing.T) {
+		report, err := conformance.Run(context.Background(), client, &conformance.Config{
+			Descriptor: chassis,
+			VendorCA:   vendorCA,
+		})
+		if err != nil {
+			t.Fatalf("conformance.Run() err = %v, want nil", err)
+		}
+		for _, r := range report.Results {
+			if r.Outcome == conformance.Fail {
+				t.Errorf("conformance check %v failed without faults: %v", r.Name, r.Message)
+			}
+		}
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/bootz/server/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const unixPrefix = "unix://"
+
+// listenerSpec describes an address the server binds to and how connections to it are secured.
+type listenerSpec struct {
+	// network is either "tcp" or "unix".
+	network string
+	// address is a host:port pair for tcp, or a file path for unix sockets.
+	address string
+	// tls is whether connections are secured with TLS.
+	tls bool
+	// admin is whether the Admin service is served alongside the Bootstrap service.
+	admin bool
+	// certFile and keyFile optionally hold a PEM encoded serving keypair.
+	// If unset, the keypair generated from the security artifacts is used.
+	certFile string
+	keyFile  string
+}
+
+func (l *listenerSpec) String() string {
+	addr := l.address
+	if l.network == "unix" {
+		addr = unixPrefix + addr
+	}
+	opts := []string{addr, "tls=" + strconv.FormatBool(l.tls), "admin=" + strconv.FormatBool(l.admin)}
+	if l.certFile != "" {
+		opts = append(opts, "cert="+l.certFile, "key="+l.keyFile)
+	}
+	return strings.Join(opts, ",")
+}
+
+// parseListenerSpec parses a listener of the form <address>[,tls=<bool>][,admin=<bool>][,cert=<file>,key=<file>].
+// The address is either host:port, where the host may be an IPv4 or bracketed IPv6 address,
+// a hostname or empty for the wildcard address, or unix://<path> for a unix socket.
+// TLS is enabled by default for tcp listeners and disabled for unix sockets.
+// The Admin service is enabled by default for unix sockets only.
+func parseListenerSpec(s string) (*listenerSpec, error) {
+	parts := strings.Split(s, ",")
+	l := &listenerSpec{network: "tcp", tls: true}
+	if path, ok := strings.CutPrefix(parts[0], unixPrefix); ok {
+		if path == "" {
+			return nil, fmt.Errorf("listener %q: empty unix socket path", s)
+		}
+		l.network, l.address, l.tls, l.admin = "unix", path, false, true
+	} else {
+		if _, _, err := net.SplitHostPort(parts[0]); err != nil {
+			return nil, fmt.Errorf("listener %q: %v", s, err)
+		}
+		l.address = parts[0]
+	}
+	for _, opt := range parts[1:] {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			return nil, fmt.Errorf("listener %q: option %q is not of the form key=value", s, opt)
+		}
+		switch k {
+		case "tls":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("listener %q: invalid tls value %q", s, v)
+			}
+			l.tls = b
+		case "admin":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("listener %q: invalid admin value %q", s, v)
+			}
+			l.admin = b
+		case "cert":
+			l.certFile = v
+		case "key":
+			l.keyFile = v
+		default:
+			return nil, fmt.Errorf("listener %q: unknown option %q", s, k)
+		}
+	}
+	if (l.certFile == "") != (l.keyFile == "") {
+		return nil, fmt.Errorf("listener %q: cert and key must be set together", s)
+	}
+	if l.certFile != "" && !l.tls {
+		return nil, fmt.Errorf("listener %q: cert and key require tls", s)
+	}
+	return l, nil
+}
+
+// listenFlag is a repeatable flag holding listener specs.
+type listenFlag []*listenerSpec
+
+func (f *listenFlag) String() string {
+	var s []string
+	for _, l := range *f {
+		s = append(s, l.String())
+	}
+	return strings.Join(s, " ")
+}
+
+func (f *listenFlag) Set(v string) error {
+	l, err := parseListenerSpec(v)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, l)
+	return nil
+}
+
+// listen binds the listener and returns the gRPC server options to secure it.
+// TLS secured listeners use a copy of the provided TLS config, with the listener's keypair if it has one.
+func (l *listenerSpec) listen(tlsConfig *tls.Config) (net.Listener, []grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if l.tls {
+		conf := tlsConfig.Clone()
+		if l.certFile != "" {
+			kp, err := tls.LoadX509KeyPair(l.certFile, l.keyFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to load keypair for listener %v: %v", l, err)
+			}
+			conf.Certificates = []tls.Certificate{kp}
+			// Devices are told to trust the chain they are served rather than the generated one.
+			opts = append(opts, grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				return handler(service.WithServingChain(ctx, kp.Certificate), req)
+			}))
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(conf)))
+	}
+	if l.network == "unix" {
+		// Remove a stale socket left behind by a previous run.
+		if fi, err := os.Stat(l.address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(l.address)
+		}
+	}
+	lis, err := net.Listen(l.network, l.address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listening on %v: %v", l, err)
+	}
+	return lis, opts, nil
+}
+
+// bootzURL returns the bootz:// URL to advertise for the first TLS secured tcp listener.
+// If the listener is bound to the wildcard address, the address of the provided interface is used.
+// Loopback listeners are skipped, as devices on the interface cannot reach them.
+func bootzURL(specs []*listenerSpec, listeners []net.Listener, intf string) (string, error) {
+	var loopback *listenerSpec
+	for i, l := range specs {
+		if l.network != "tcp" || !l.tls {
+			continue
+		}
+		host, _, err := net.SplitHostPort(l.address)
+		if err != nil {
+			return "", err
+		}
+		ip := net.ParseIP(host)
+		if host == "localhost" || ip != nil && ip.IsLoopback() {
+			if loopback == nil {
+				loopback = l
+			}
+			continue
+		}
+		_, port, err := net.SplitHostPort(listeners[i].Addr().String())
+		if err != nil {
+			return "", err
+		}
+		if host == "" || ip != nil && ip.IsUnspecified() {
+			ip, err := interfaceIP(intf)
+			if err != nil {
+				return "", fmt.Errorf("unable to determine address for wildcard listener %v: %v", l, err)
+			}
+			host = ip.String()
+		}
+		return "bootz://" + net.JoinHostPort(host, port), nil
+	}
+	if loopback != nil {
+		return "", fmt.Errorf("listener %v is bound to loopback, which devices on %v cannot reach", loopback, intf)
+	}
+	return "", fmt.Errorf("no TLS secured tcp listener to advertise")
+}
+
+// interfaceIP returns the first IPv4 address of the interface, or its first global unicast IPv6 address.
+func interfaceIP(intf string) (net.IP, error) {
+	i, err := net.InterfaceByName(intf)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := i.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	var v6 net.IP
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if v4 := ipNet.IP.To4(); v4 != nil {
+			return v4, nil
+		}
+		if v6 == nil && ipNet.IP.IsGlobalUnicast() {
+			v6 = ipNet.IP
+		}
+	}
+	if v6 == nil {
+		return nil, fmt.Errorf("interface %v has no usable address", intf)
+	}
+	return v6, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scheme is a crypt password hash scheme.
+type Scheme int
+
+// Supported schemes.
+const (
+	SHA512Crypt Scheme = iota
+	SHA256Crypt
+)
+
+const (
+	// DefaultRounds is the number of rounds of hashes which do not specify one.
+	DefaultRounds = 5000
+	minRounds     = 1000
+	maxRounds     = 999999999
+	maxSaltLen    = 16
+	// cryptAlphabet is the base64 alphabet of crypt hashes, also used for salts and generated
+	// passwords.
+	cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// crypt holds the parameters of a SHA crypt scheme.
+type crypt struct {
+	id      string
+	newHash func() hash.Hash
+	// order lists the bytes of the digest in the order they are encoded, by groups of three.
+	order []int
+}
+
+var schemes = map[Scheme]*crypt{
+	SHA512Crypt: {
+		id:      "6",
+		newHash: sha512.New,
+		order: []int{
+			0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4, 47, 5, 26, 6, 27, 48, 28, 49, 7,
+			50, 8, 29, 9, 30, 51, 31, 52, 10, 53, 11, 32, 12, 33, 54, 34, 55, 13, 56, 14, 35, 15, 36, 57,
+			37, 58, 16, 59, 17, 38, 18, 39, 60, 40, 61, 19, 62, 20, 41, 63,
+		},
+	},
+	SHA256Crypt: {
+		id:      "5",
+		newHash: sha256.New,
+		order: []int{
+			0, 10, 20, 21, 1, 11, 12, 22, 2, 3, 13, 23, 24, 4, 14, 15, 25, 5, 6, 16, 26, 27, 7, 17,
+			18, 28, 8, 9, 19, 29, 31, 30,
+		},
+	},
+}
+
+// Crypt returns the crypt hash of the password in the provided scheme, e.g. $6$<salt>$<hash> for
+// SHA-512 crypt, with a random salt and the default number of rounds.
+func Crypt(s Scheme, password string) (string, error) {
+	salt, err := RandomString(maxSaltLen)
+	if err != nil {
+		return "", err
+	}
+	return CryptWithSalt(s, password, salt, DefaultRounds)
+}
+
+// CryptWithSalt returns the crypt hash of the password in the provided scheme, with the provided
+// salt and number of rounds. Salts are truncated to 16 characters, and rounds are clamped to the
+// range allowed by the scheme.
+func CryptWithSalt(s Scheme, password, salt string, rounds int) (string, error) {
+	return cryptWithSalt(s, password, salt, rounds, rounds != DefaultRounds)
+}
+
+// cryptWithSalt returns the crypt hash of the password. The number of rounds is part of the hash
+// if customRounds is set.
+func cryptWithSalt(s Scheme, password, salt string, rounds int, customRounds bool) (string, error) {
+	c, ok := schemes[s]
+	if !ok {
+		return "", fmt.Errorf("unknown password hash scheme %d", s)
+	}
+	if len(salt) > maxSaltLen {
+		salt = salt[:maxSaltLen]
+	}
+	if rounds < minRounds {
+		rounds = minRounds
+	}
+	if rounds > maxRounds {
+		rounds = maxRounds
+	}
+	digest := c.digest([]byte(password), []byte(salt), rounds)
+
+	var b strings.Builder
+	b.WriteString("$" + c.id + "$")
+	if customRounds {
+		b.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	b.WriteString(salt + "$")
+	order := c.order
+	for ; len(order) >= 3; order = order[3:] {
+		encode(&b, uint(digest[order[0]])<<16|uint(digest[order[1]])<<8|uint(digest[order[2]]), 4)
+	}
+	// The last byte or two are encoded on their own.
+	switch len(order) {
+	case 1:
+		encode(&b, uint(digest[order[0]]), 2)
+	case 2:
+		encode(&b, uint(digest[order[0]])<<8|uint(digest[order[1]]), 3)
+	}
+	return b.String(), nil
+}
+
+// encode writes n characters of the crypt base64 encoding of w, least significant bits first.
+func encode(b *strings.Builder, w uint, n int) {
+	for ; n > 0; n-- {
+		b.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
+
+// digest computes the SHA crypt digest of the password, following
+// https://www.akkadia.org/drepper/SHA-crypt.txt.
+func (c *crypt) digest(password, salt []byte, rounds int) []byte {
+	h := c.newHash()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(password)
+	alt := h.Sum(nil)
+
+	h = c.newHash()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(repeat(alt, len(password)))
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(alt)
+		} else {
+			h.Write(password)
+		}
+	}
+	a := h.Sum(nil)
+
+	h = c.newHash()
+	for i := 0; i < len(password); i++ {
+		h.Write(password)
+	}
+	p := repeat(h.Sum(nil), len(password))
+
+	h = c.newHash()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(salt)
+	}
+	s := repeat(h.Sum(nil), len(salt))
+
+	for i := 0; i < rounds; i++ {
+		h = c.newHash()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(a)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(a)
+		} else {
+			h.Write(p)
+		}
+		a = h.Sum(a[:0])
+	}
+	return a
+}
+
+// repeat returns n bytes of b repeated.
+func repeat(b []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, b[:min(len(b), n-len(out))]...)
+	}
+	return out
+}
+
+// Verify reports whether a SHA crypt hash, as returned by Crypt, is the hash of the password.
+func Verify(hashed, password string) bool {
+	parts := strings.Split(hashed, "$")
+	if len(parts) < 4 || parts[0] != "" {
+		return false
+	}
+	var s Scheme
+	switch parts[1] {
+	case "6":
+		s = SHA512Crypt
+	case "5":
+		s = SHA256Crypt
+	default:
+		return false
+	}
+	rounds, customRounds := DefaultRounds, false
+	salt := parts[2]
+	if r, ok := strings.CutPrefix(parts[2], "rounds="); ok && len(parts) == 5 {
+		n, err := strconv.Atoi(r)
+		if err != nil {
+			return false
+		}
+		rounds, customRounds, salt = n, true, parts[3]
+	}
+	got, err := cryptWithSalt(s, password, salt, rounds, customRounds)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(hashed)) == 1
+}
+
+// RandomString returns a random string of n characters of the crypt alphabet, suitable for salts
+// and generated passwords.
+func RandomString(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(cryptAlphabet)))
+	for i := range b {
+		v, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = cryptAlphabet[v.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCryptWithSalt(t *testing.T) {
+	// Test vectors of https://www.akkadia.org/drepper/SHA-crypt.txt.
+	tests := []struct {
+		scheme   Scheme
+		password string
+		salt     string
+		rounds   int
+		want     string
+	}{{
+		scheme:   SHA512Crypt,
+		password: "Hello world!",
+		salt:     "saltstring",
+		rounds:   DefaultRounds,
+		want:     "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+	}, {
+		scheme:   SHA512Crypt,
+		password: "Hello world!",
+		salt:     "saltstringsaltstring",
+		rounds:   10000,
+		want:     "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+	}, {
+		scheme:   SHA512Crypt,
+		password: "we have a short salt string but not a short password",
+		salt:     "roundstoolow",
+		rounds:   10,
+		want:     "$6$rounds=1000$roundstoolow$yjTuW7RnC.d35QcVTFIb6uvh/7IQ1.GFtFN3i/.jwmeWEhzjf4uD/OPCb4jRl6atJGYhLst8IyR6YAtTrriMU1",
+	}, {
+		scheme:   SHA256Crypt,
+		password: "Hello world!",
+		salt:     "saltstring",
+		rounds:   DefaultRounds,
+		want:     "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+	}, {
+		scheme:   SHA256Crypt,
+		password: "Hello world!",
+		salt:     "saltstringsaltstring",
+		rounds:   10000,
+		want:     "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA",
+	}}
+	for _, test := range tests {
+		got, err := CryptWithSalt(test.scheme, test.password, test.salt, test.rounds)
+		if err != nil {
+			t.Fatalf("CryptWithSalt(%q, %q, %d) err = %v", test.password, test.salt, test.rounds, err)
+		}
+		if got != test.want {
+			t.Errorf("CryptWithSalt(%q, %q, %d) = %q, want %q", test.password, test.salt, test.rounds, got, test.want)
+		}
+		if !Verify(test.want, test.password) {
+			t.Errorf("Verify(%q, %q) = false, want true", test.want, test.password)
+		}
+		if Verify(test.want, test.password+"!") {
+			t.Errorf("Verify(%q) of a wrong password = true, want false", test.want)
+		}
+	}
+
+	hashed, err := Crypt(SHA512Crypt, "secret")
+	if err != nil {
+		t.Fatalf("Crypt() err = %v", err)
+	}
+	if !strings.HasPrefix(hashed, "$6$") || !Verify(hashed, "secret") {
+		t.Errorf("Crypt() = %q, want a SHA-512 crypt hash of the password", hashed)
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{1}, KeySize)
+	path := filepath.Join(dir, "secrets.json")
+
+	s, err := Open(path, key, SecretStore)
+	if err != nil {
+		t.Fatalf("Open() of a missing file err = %v", err)
+	}
+	if err := s.Set("core1", "hunter2"); err != nil {
+		t.Fatalf("Set() err = %v", err)
+	}
+	if err := s.Set("core2", "correct horse"); err != nil {
+		t.Fatalf("Set() err = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() err = %v", err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Errorf("store file holds a secret in clear: %s", data)
+	}
+
+	s, err = Open(path, key, SecretStore)
+	if err != nil {
+		t.Fatalf("Open() err = %v", err)
+	}
+	if got, ok := s.Get("core1"); !ok || got != "hunter2" {
+		t.Errorf("Get(core1) = %q, %v, want hunter2", got, ok)
+	}
+	if err := s.Delete("core2"); err != nil {
+		t.Fatalf("Delete() err = %v", err)
+	}
+	if diff := cmp.Diff([]string{"core1"}, s.Names()); diff != "" {
+		t.Errorf("Names() differs (-want +got):\n%s", diff)
+	}
+
+	if _, err := Open(path, bytes.Repeat([]byte{2}, KeySize), SecretStore); err == nil || !strings.Contains(err.Error(), "unable to decrypt") {
+		t.Errorf("Open() with the wrong key err = %v, want unable to decrypt", err)
+	}
+	// A secret store cannot be opened as a password escrow, e.g. if the flags are swapped.
+	if _, err := Open(path, key, PasswordEscrow); err == nil || !strings.Contains(err.Error(), "unable to decrypt") {
+		t.Errorf("Open() of a secret store as a password escrow err = %v, want unable to decrypt", err)
+	}
+	if _, err := Open(path, key[:16], SecretStore); err == nil {
+		t.Errorf("Open() with a short key succeeded")
+	}
+
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte(hex.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	if got, err := LoadKey(keyFile); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("LoadKey() of a hex key = %x, %v, want %x", got, err, key)
+	}
+	if err := os.WriteFile(keyFile, []byte("short"), 0o600); err != nil {
+		t.Fatalf("WriteFile() err = %v", err)
+	}
+	if _, err := LoadKey(keyFile); err == nil {
+		t.Errorf("LoadKey() of an invalid key succeeded")
+	}
+}
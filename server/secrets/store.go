@@ -0,0 +1,215 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secrets implements a local store of secrets encrypted at rest, and the crypt hashes of
+// bootloader passwords.
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/openconfig/bootz/server/atomicfile"
+)
+
+// KeySize is the size of the keys of stores, in bytes.
+const KeySize = 32
+
+// storeVersion is the version of the file format of stores. It is authenticated along with the
+// secrets.
+const storeVersion = 1
+
+// storeFile is the content of a store file. The secrets are a JSON object of names to values,
+// encrypted with AES-256-GCM.
+type storeFile struct {
+	Version    int    `json:"version"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Purpose is what a store file is used for. It is authenticated along with the secrets, so that
+// a file is only opened for the purpose it was written for.
+type Purpose string
+
+const (
+	// SecretStore holds the secrets bootloader passwords of the inventory may refer to.
+	SecretStore Purpose = "secret store"
+	// PasswordEscrow records the bootloader passwords generated for chassis.
+	PasswordEscrow Purpose = "password escrow"
+)
+
+// Store is a set of named secrets, stored encrypted in a file. It is safe for concurrent use.
+type Store struct {
+	path    string
+	purpose Purpose
+	aead    cipher.AEAD
+
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// LoadKey reads a store key from a file, holding either the raw 32 byte key or its hex or base64
+// encoding.
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == KeySize {
+		return data, nil
+	}
+	text := string(bytes.TrimSpace(data))
+	if key, err := hex.DecodeString(text); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid key in %s: want %d bytes, raw or hex or base64 encoded", path, KeySize)
+}
+
+// Open opens the store in the file at path, decrypting it with the key. The file must have been
+// written for the same purpose. If the file does not exist, the store is empty and the file is
+// created when a secret is first set.
+func Open(path string, key []byte, purpose Purpose) (*Store, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key: want %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, purpose: purpose, aead: aead, secrets: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid secret store %s: %v", path, err)
+	}
+	if f.Version != storeVersion {
+		return nil, fmt.Errorf("invalid secret store %s: unsupported version %d", path, f.Version)
+	}
+	if len(f.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid secret store %s: invalid nonce", path)
+	}
+	plain, err := aead.Open(nil, f.Nonce, f.Ciphertext, additionalData(purpose))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt %s %s, are the key and purpose right? %v", purpose, path, err)
+	}
+	if err := json.Unmarshal(plain, &s.secrets); err != nil {
+		return nil, fmt.Errorf("invalid secret store %s: %v", path, err)
+	}
+	return s, nil
+}
+
+// additionalData is the data authenticated along with the secrets, e.g. "bootz secret store v1".
+// The path is not bound, so that store files may be moved or opened by relative paths.
+func additionalData(purpose Purpose) []byte {
+	return []byte(fmt.Sprintf("bootz %s v%d", purpose, storeVersion))
+}
+
+// Get returns the secret with the provided name.
+func (s *Store) Get(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.secrets[name]
+	return v, ok
+}
+
+// Names returns the names of the secrets, sorted.
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.secrets))
+	for n := range s.secrets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set sets the secret with the provided name and writes the store.
+func (s *Store) Set(name, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, had := s.secrets[name]
+	s.secrets[name] = value
+	if err := s.write(); err != nil {
+		if had {
+			s.secrets[name] = old
+		} else {
+			delete(s.secrets, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the secret with the provided name, if any, and writes the store.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.secrets[name]
+	if !ok {
+		return nil
+	}
+	delete(s.secrets, name)
+	if err := s.write(); err != nil {
+		s.secrets[name] = old
+		return err
+	}
+	return nil
+}
+
+// write encrypts the secrets with a new nonce and replaces the file of the store. The caller must
+// hold s.mu.
+func (s *Store) write() error {
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(storeFile{
+		Version:    storeVersion,
+		Nonce:      nonce,
+		Ciphertext: s.aead.Seal(nil, nonce, plain, additionalData(s.purpose)),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The file is replaced atomically, so that a failed write does not lose the secrets.
+	return atomicfile.WriteFile(s.path, append(data, '\n'), 0o600)
+}
@@ -20,90 +20,410 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"flag"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	log "github.com/golang/glog"
 	"github.com/openconfig/bootz/dhcp"
+	"github.com/openconfig/bootz/server/admin"
+	"github.com/openconfig/bootz/server/certs"
 	"github.com/openconfig/bootz/server/entitymanager"
+	"github.com/openconfig/bootz/server/events"
+	"github.com/openconfig/bootz/server/faults"
+	"github.com/openconfig/bootz/server/secrets"
 	"github.com/openconfig/bootz/server/service"
+	"github.com/openconfig/bootz/server/webhook"
 	artifacts "github.com/openconfig/bootz/testdata"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
 )
 
 var (
-	port            = flag.String("port", "15006", "The port to start the Bootz server on localhost")
+	port            = flag.String("port", "15006", "The port to start the Bootz server on localhost, or on the address of --dhcp_intf if set. Only used if no --listen flag is provided.")
 	dhcpIntf        = flag.String("dhcp_intf", "", "Network interface to use for dhcp server.")
-	inventoryConfig = flag.String("inv_config", "../testdata/inventory_local.prototxt", "Devices' config files to be loaded by inventory manager")
+	inventoryConfig = flag.String("inv_config", "../testdata/inventory_local.prototxt", "Devices' config file to be loaded by inventory manager, in prototext, JSON (.json) or YAML (.yaml, .yml) format")
 	generateOVsFor  = flag.String("generate_ovs_for", "123A,123B", "Comma-separated list of control card serial numbers to generate OVs for.")
+	tlsHosts        = flag.String("tls_hosts", "", "Comma-separated list of additional hostnames and IPs the TLS serving certificate is valid for. The hosts of the listeners are always included.")
+	clientCABundle  = flag.String("client_ca_bundle", "", "Path to a PEM file with the vendor CA certificates that issue device IDevIDs. The generated vendor CA is always trusted.")
+	clientAuth      = flag.String("client_auth", "request", "Whether devices must present an IDevID client certificate: none, request (verify if presented) or require.")
+	idevidDir       = flag.String("idevid_dir", "", "If set, IDevIDs signed by the generated vendor CA are written to this directory for the serials in --generate_ovs_for, for use by the client emulator.")
+	sessionTimeout  = flag.Duration("session_timeout", service.DefaultSessionTimeout, "How long a device has to report success after fetching bootstrap data before its bootstrap session times out.")
+	webhookURLs     = flag.String("webhook_url", "", "Comma-separated list of URLs bootstrap status changes and rejected requests are posted to.")
+	webhookSecret   = flag.String("webhook_secret_file", "", "Path to a file with the key used to sign webhook payloads.")
+	webhookQueueDir = flag.String("webhook_queue_dir", "", "Directory pending webhook deliveries are persisted to, so that they survive restarts.")
+	webhookAttempts = flag.Int("webhook_max_attempts", 20, "Number of failed attempts after which a webhook delivery is dropped. 0 retries without limit.")
+	webhookPending  = flag.Int("webhook_max_pending", 10000, "Number of webhook deliveries kept pending per URL, beyond which the oldest is dropped. 0 keeps all of them.")
+	nonceMinBytes   = flag.Int("nonce_min_bytes", service.DefaultNonceMinBytes, "Minimum number of bytes of a base64 decoded nonce. 0 disables the nonce format checks.")
+	nonceWindow     = flag.Duration("nonce_replay_window", service.DefaultNonceReplayWindow, "How long the nonces of a chassis are remembered. Requests reusing a nonce within the window are rejected. 0 disables replay detection.")
+	provisionalTTL  = flag.Duration("provisional_ttl", entitymanager.DefaultProvisionalTTL, "How long a chassis served a fallback profile is kept in the inventory without being approved.")
+	faultInjection  = flag.Bool("fault_injection", false, "Whether to corrupt the responses served to chassis according to the faults in the inventory. Only for negative testing.")
+	secretStore     = flag.String("secret_store", "", "Path to the encrypted secret store bootloader passwords of the inventory may refer to.")
+	secretKeyFile   = flag.String("secret_key_file", "", "Path to the file with the 32 byte key of the secret store and password escrow, raw or hex or base64 encoded.")
+	passwordEscrow  = flag.String("password_escrow", "", "Path to the encrypted file generated bootloader passwords are recorded to, with the key of --secret_key_file.")
+	issuingCADir    = flag.String("issuing_ca_dir", "", "If set, devices get a TLS certificate at bootstrap, issued by the CA in this directory. The CA is generated if the directory has none.")
+	certValidity    = flag.Duration("cert_validity", certs.DefaultValidity, "Validity of the certificates issued to devices.")
+	certRenewBefore = flag.Duration("cert_renew_before", certs.DefaultRenewBefore, "Devices get a new certificate when they bootstrap within this time of the expiry of their certificate.")
+	listen          listenFlag
 )
 
+func init() {
+	flag.Var(&listen, "listen", "Address to serve on, of the form <address>[,tls=<bool>][,admin=<bool>][,cert=<file>,key=<file>]. The address is host:port or unix://<path>. May be repeated.")
+}
+
 type server struct {
-	serv *grpc.Server
-	lis  net.Listener
+	servs []*grpc.Server
+	lis   []net.Listener
+	svc   *service.Service
+	bus   *events.Bus
+	hooks *webhook.Dispatcher
+	done  chan struct{}
+	// stopOnce guards the shutdown of the listeners and background tasks.
+	stopOnce sync.Once
 }
 
+// sessionSweepInterval is how often bootstrap sessions are checked for timeouts.
+const sessionSweepInterval = time.Minute
+
+// Start serves on all listeners and returns when any of them fails, after stopping the others.
 func (s *server) Start() error {
-	return s.serv.Serve(s.lis)
+	go s.sweepSessions()
+	if s.hooks != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-s.done
+			cancel()
+		}()
+		s.hooks.Start(ctx, s.bus)
+	}
+	errCh := make(chan error, len(s.lis))
+	for i := range s.lis {
+		serv, lis := s.servs[i], s.lis[i]
+		go func() {
+			errCh <- serv.Serve(lis)
+		}()
+	}
+	err := <-errCh
+	// Do not keep serving on a subset of the listeners.
+	s.shutdown(false)
+	return err
 }
 
+// Stop gracefully stops the server.
 func (s *server) Stop() {
-	s.serv.GracefulStop()
+	s.shutdown(true)
+}
+
+func (s *server) shutdown(graceful bool) {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		for _, serv := range s.servs {
+			if graceful {
+				serv.GracefulStop()
+			} else {
+				serv.Stop()
+			}
+		}
+	})
+}
+
+// sweepSessions periodically times out bootstrap sessions, so that watchers learn about
+// silent devices without waiting for further device activity.
+func (s *server) sweepSessions() {
+	t := time.NewTicker(sessionSweepInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.svc.ExpireSessions()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// secretOptions opens the secret store and password escrow set by flags.
+func secretOptions() ([]entitymanager.Option, error) {
+	if *secretStore == "" && *passwordEscrow == "" {
+		return nil, nil
+	}
+	if *secretKeyFile == "" {
+		return nil, fmt.Errorf("--secret_key_file is required with --secret_store and --password_escrow")
+	}
+	key, err := secrets.LoadKey(*secretKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	var opts []entitymanager.Option
+	if *secretStore != "" {
+		s, err := secrets.Open(*secretStore, key, secrets.SecretStore)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, entitymanager.WithSecretStore(s))
+	}
+	if *passwordEscrow != "" {
+		s, err := secrets.Open(*passwordEscrow, key, secrets.PasswordEscrow)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, entitymanager.WithPasswordEscrow(s))
+	}
+	return opts, nil
 }
 
 // newServer creates a new Bootz gRPC server from flags.
 func newServer() (*server, error) {
-	if *port == "" {
-		return nil, fmt.Errorf("no port selected. specify with the --port flag")
+	specs := listen
+	if len(specs) == 0 {
+		if *port == "" {
+			return nil, fmt.Errorf("no port selected. specify with the --port or --listen flag")
+		}
+		host := "localhost"
+		if *dhcpIntf != "" {
+			// Devices on the DHCP interface cannot reach a loopback listener.
+			ip, err := interfaceIP(*dhcpIntf)
+			if err != nil {
+				return nil, fmt.Errorf("unable to determine the address to listen on: %v", err)
+			}
+			host = ip.String()
+		}
+		l, err := parseListenerSpec(net.JoinHostPort(host, *port))
+		if err != nil {
+			return nil, err
+		}
+		specs = listenFlag{l}
 	}
 
 	log.Infof("Setting up server security artifacts: OC, OVs, PDC, VendorCA")
 	serials := strings.Split(*generateOVsFor, ",")
 
-	sa, err := artifacts.GenerateSecurityArtifacts(serials, "Google", "Cisco")
+	sa, err := artifacts.GenerateSecurityArtifacts(serials, "Google", "Cisco", servingHosts(specs)...)
 	if err != nil {
 		return nil, err
 	}
 
 	log.Infof("Setting up entities")
-	em, err := entitymanager.New(*inventoryConfig, sa)
+	bus := events.NewBus(events.DefaultBufferSize)
+	generateOV := func(serial string) ([]byte, error) {
+		return artifacts.NewOwnershipVoucher(serial, sa.PDC, sa.VendorCA, sa.VendorCAPrivateKey)
+	}
+	emOpts := []entitymanager.Option{entitymanager.WithEvents(bus), entitymanager.WithOVGenerator(generateOV), entitymanager.WithProvisionalTTL(*provisionalTTL)}
+	secretOpts, err := secretOptions()
+	if err != nil {
+		return nil, err
+	}
+	emOpts = append(emOpts, secretOpts...)
+	if *issuingCADir != "" {
+		issuer, err := certs.Open(*issuingCADir, *certValidity, *certRenewBefore)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open the issuing CA: %v", err)
+		}
+		emOpts = append(emOpts, entitymanager.WithCertIssuer(issuer))
+	}
+	em, err := entitymanager.New(*inventoryConfig, sa, emOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to initiate inventory manager %v", err)
 	}
 
+	var sem service.EntityManager = em
+	var admOpts []admin.Option
+	if *faultInjection {
+		log.Warningf("Fault injection enabled, the server will serve deliberately broken responses")
+		inj := faults.New(em, sa)
+		inj.LoadInventory(em.Snapshot().Chassis)
+		sem = inj
+		admOpts = append(admOpts, admin.WithFaultInjector(inj))
+	}
+	policy := service.NoncePolicy{MinBytes: *nonceMinBytes, ReplayWindow: *nonceWindow}
+	c := service.New(sem, service.WithSessionTimeout(*sessionTimeout), service.WithEvents(bus), service.WithDiscoverer(em),
+		service.WithNoncePolicy(policy), service.WithAuditor(em), service.WithCommitter(em))
+	adm := admin.New(em, bus, admOpts...)
+
+	tlsConfig, err := serverTLSConfig(sa)
+	if err != nil {
+		return nil, err
+	}
+	if *idevidDir != "" {
+		if err := writeIDevIDs(sa, serials, *idevidDir); err != nil {
+			return nil, fmt.Errorf("unable to write IDevIDs: %v", err)
+		}
+	}
+
+	hooks, err := newWebhooks()
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up webhooks: %v", err)
+	}
+
+	log.Infof("Creating server...")
+	s := &server{svc: c, bus: bus, hooks: hooks, done: make(chan struct{})}
+	for _, l := range specs {
+		lis, opts, err := l.listen(tlsConfig)
+		if err != nil {
+			s.close()
+			return nil, err
+		}
+		gs := grpc.NewServer(opts...)
+		bpb.RegisterBootstrapServer(gs, c)
+		if l.admin {
+			apb.RegisterAdminServer(gs, adm)
+		}
+		s.servs = append(s.servs, gs)
+		s.lis = append(s.lis, lis)
+		log.Infof("Server ready and listening on %s (tls=%v, admin=%v)", lis.Addr(), l.tls, l.admin)
+	}
+
 	if *dhcpIntf != "" {
-		if err := startDhcpServer(em); err != nil {
+		url, err := bootzURL(specs, s.lis, *dhcpIntf)
+		if err != nil {
+			s.close()
+			return nil, fmt.Errorf("unable to derive bootz url: %v", err)
+		}
+		log.Infof("Advertising %v over DHCP", url)
+		if err := s.startDhcpServer(em, url); err != nil {
+			s.close()
 			return nil, fmt.Errorf("unable to start dhcp server %v", err)
 		}
 	}
+	log.Infof("=============================================================================")
+	return s, nil
+}
 
-	c := service.New(em)
-
-	trustBundle := x509.NewCertPool()
-	trustBundle.AddCert(sa.TrustAnchor)
+// newWebhooks returns the webhook dispatcher configured by flags, or nil if no webhook is configured.
+func newWebhooks() (*webhook.Dispatcher, error) {
+	if *webhookURLs == "" {
+		return nil, nil
+	}
+	cfg := webhook.Config{
+		URLs:        strings.Split(*webhookURLs, ","),
+		QueueDir:    *webhookQueueDir,
+		MaxAttempts: *webhookAttempts,
+		MaxPending:  *webhookPending,
+	}
+	if *webhookSecret == "" {
+		return nil, fmt.Errorf("--webhook_secret_file is required with --webhook_url")
+	}
+	secret, err := os.ReadFile(*webhookSecret)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Secret = bytes.TrimSpace(secret)
+	if len(cfg.Secret) == 0 {
+		return nil, fmt.Errorf("webhook secret file %v is empty", *webhookSecret)
+	}
+	log.Infof("Posting bootstrap events to %v", cfg.URLs)
+	return webhook.New(cfg)
+}
 
-	tls := &tls.Config{
+// serverTLSConfig returns the TLS config shared by the TLS secured listeners.
+// Client certificates are verified against the vendor CAs that issue IDevIDs.
+func serverTLSConfig(sa *service.SecurityArtifacts) (*tls.Config, error) {
+	conf := &tls.Config{
 		Certificates: []tls.Certificate{*sa.TLSKeypair},
-		RootCAs:      trustBundle,
 	}
-	log.Infof("Creating server...")
-	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(tls)))
-	bpb.RegisterBootstrapServer(s, c)
+	switch *clientAuth {
+	case "none":
+		return conf, nil
+	case "request":
+		conf.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown --client_auth value %q", *clientAuth)
+	}
+	conf.ClientCAs = x509.NewCertPool()
+	conf.ClientCAs.AddCert(sa.VendorCA)
+	if *clientCABundle != "" {
+		bundle, err := os.ReadFile(*clientCABundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read client CA bundle: %v", err)
+		}
+		if !conf.ClientCAs.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in client CA bundle %s", *clientCABundle)
+		}
+	}
+	log.Infof("Client certificates are verified against the IDevID CAs (client_auth=%v)", *clientAuth)
+	return conf, nil
+}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", *port))
-	if err != nil {
-		return nil, fmt.Errorf("error listening on port: %v", err)
+// writeIDevIDs mints an IDevID for each serial with the generated vendor CA and writes
+// <serial>.pem and <serial>.key to the directory, along with the vendor CA in vendor_ca.pem.
+func writeIDevIDs(sa *service.SecurityArtifacts, serials []string, dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor_ca.pem"), artifacts.CertificatePEM(sa.VendorCA), 0600); err != nil {
+		return err
+	}
+	for _, serial := range serials {
+		cert, key, err := artifacts.NewIDevID(serial, sa.VendorCA.Subject.Organization[0], sa.VendorCA, sa.VendorCAPrivateKey)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, serial+".pem"), artifacts.CertificatePEM(cert), 0600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, serial+".key"), artifacts.PrivateKeyPEM(key), 0600); err != nil {
+			return err
+		}
+		log.Infof("Wrote IDevID for %v to %v", serial, dir)
+	}
+	return nil
+}
+
+// servingHosts returns the hostnames and IPs the TLS serving certificate must be valid for.
+func servingHosts(specs []*listenerSpec) []string {
+	hosts := append([]string{}, artifacts.DefaultTLSHosts...)
+	if *tlsHosts != "" {
+		hosts = append(hosts, strings.Split(*tlsHosts, ",")...)
+	}
+	for _, l := range specs {
+		if l.network != "tcp" || !l.tls {
+			continue
+		}
+		host, _, err := net.SplitHostPort(l.address)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+			if *dhcpIntf == "" {
+				continue
+			}
+			ip, err := interfaceIP(*dhcpIntf)
+			if err != nil {
+				continue
+			}
+			host = ip.String()
+		}
+		hosts = append(hosts, host)
+	}
+	seen := map[string]bool{}
+	var uniq []string
+	for _, h := range hosts {
+		if h = strings.TrimSpace(h); h != "" && !seen[h] {
+			seen[h] = true
+			uniq = append(uniq, h)
+		}
+	}
+	return uniq
+}
+
+// close releases the listeners of a server which was not started.
+func (s *server) close() {
+	for _, lis := range s.lis {
+		lis.Close()
 	}
-	log.Infof("Server ready and listening on %s", lis.Addr())
-	log.Infof("=============================================================================")
-	return &server{serv: s, lis: lis}, nil
 }
 
 func main() {
@@ -123,24 +443,82 @@ func main() {
 	}
 }
 
-func startDhcpServer(em *entitymanager.InMemoryEntityManager) error {
-	conf := &dhcp.Config{
+// startDhcpServer starts the DHCP server and regenerates its config on each change of the
+// inventory, until the server stops.
+func (s *server) startDhcpServer(em *entitymanager.InMemoryEntityManager, bootzURL string) error {
+	snap := em.Snapshot()
+	entries := dhcpEntries(snap)
+	if err := dhcp.Start(dhcpConfig(entries, bootzURL)); err != nil {
+		return err
+	}
+	go s.watchDhcpConfig(em, bootzURL, snap.Version, entries)
+	return nil
+}
+
+// watchDhcpConfig restarts the DHCP server with the entries of each new inventory snapshot
+// which changes them, and stops it when the server stops.
+func (s *server) watchDhcpConfig(em *entitymanager.InMemoryEntityManager, bootzURL string, version uint64, entries map[string]*dhcp.Entry) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-s.done
+		cancel()
+	}()
+	for {
+		snap, err := em.WatchSnapshot(ctx, version)
+		if err != nil {
+			dhcp.Stop()
+			return
+		}
+		version = snap.Version
+		next := dhcpEntries(snap)
+		if sameDhcpEntries(entries, next) {
+			continue
+		}
+		if err := dhcp.Reload(dhcpConfig(next, bootzURL)); err != nil {
+			log.Errorf("Unable to reload the DHCP server for inventory version %d: %v", version, err)
+			continue
+		}
+		entries = next
+		log.Infof("Reloaded the DHCP server with %d entries for inventory version %d", len(entries), version)
+	}
+}
+
+func dhcpConfig(entries map[string]*dhcp.Entry, bootzURL string) *dhcp.Config {
+	return &dhcp.Config{
 		Interface:  *dhcpIntf,
-		AddressMap: make(map[string]*dhcp.Entry),
+		AddressMap: entries,
+		BootzURL:   bootzURL,
 	}
+}
 
-	for _, c := range em.GetChassisInventory() {
+// dhcpEntries returns the DHCP entries of the chassis of the snapshot, keyed by hardware address
+// or, if unset, by serial.
+func dhcpEntries(snap *entitymanager.Snapshot) map[string]*dhcp.Entry {
+	entries := map[string]*dhcp.Entry{}
+	for _, c := range snap.Chassis {
 		if dhcpConf := c.GetDhcpConfig(); dhcpConf != nil {
 			key := dhcpConf.GetHardwareAddress()
 			if key == "" {
 				key = c.GetSerialNumber()
 			}
-			conf.AddressMap[key] = &dhcp.Entry{
+			entries[key] = &dhcp.Entry{
 				IP: dhcpConf.GetIpAddress(),
 				Gw: dhcpConf.GetGateway(),
 			}
 		}
 	}
+	return entries
+}
 
-	return dhcp.Start(conf)
+func sameDhcpEntries(a, b map[string]*dhcp.Entry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, e := range a {
+		if f, ok := b[k]; !ok || *e != *f {
+			return false
+		}
+	}
+	return true
 }
@@ -15,8 +15,27 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"net"
+	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/bootz/dhcp"
+	"github.com/openconfig/bootz/server/entitymanager"
+	artifacts "github.com/openconfig/bootz/testdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+	epb "github.com/openconfig/bootz/server/entitymanager/proto/entity"
 )
 
 // TestStartup tests that a gRPC server can be created with the default flags.
@@ -27,3 +46,358 @@ func TestStartup(t *testing.T) {
 		t.Fatalf("newServer() err = %v, want nil", err)
 	}
 }
+
+func TestParseListenerSpec(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      string
+		want    *listenerSpec
+		wantErr bool
+	}{{
+		desc: "IPv4",
+		in:   "10.0.0.1:15006",
+		want: &listenerSpec{network: "tcp", address: "10.0.0.1:15006", tls: true},
+	}, {
+		desc: "IPv6 wildcard",
+		in:   "[::]:15006",
+		want: &listenerSpec{network: "tcp", address: "[::]:15006", tls: true},
+	}, {
+		desc: "Wildcard with keypair",
+		in:   ":15006,cert=server.pem,key=server.key",
+		want: &listenerSpec{network: "tcp", address: ":15006", tls: true, certFile: "server.pem", keyFile: "server.key"},
+	}, {
+		desc: "Unix socket",
+		in:   "unix:///tmp/bootz.sock",
+		want: &listenerSpec{network: "unix", address: "/tmp/bootz.sock", admin: true},
+	}, {
+		desc: "Plaintext tcp",
+		in:   "localhost:15006,tls=false",
+		want: &listenerSpec{network: "tcp", address: "localhost:15006"},
+	}, {
+		desc: "Unix socket without admin",
+		in:   "unix:///tmp/bootz.sock,admin=false",
+		want: &listenerSpec{network: "unix", address: "/tmp/bootz.sock"},
+	}, {
+		desc: "Admin over tcp",
+		in:   "localhost:15007,admin=true",
+		want: &listenerSpec{network: "tcp", address: "localhost:15007", tls: true, admin: true},
+	}, {
+		desc:    "Missing port",
+		in:      "10.0.0.1",
+		wantErr: true,
+	}, {
+		desc:    "Unknown option",
+		in:      "localhost:15006,foo=bar",
+		wantErr: true,
+	}, {
+		desc:    "Cert without key",
+		in:      "localhost:15006,cert=server.pem",
+		wantErr: true,
+	}, {
+		desc:    "Empty unix path",
+		in:      "unix://",
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := parseListenerSpec(test.in)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseListenerSpec(%q) err = %v, want error %v", test.in, err, test.wantErr)
+			}
+			if !cmp.Equal(got, test.want, cmp.AllowUnexported(listenerSpec{})) {
+				t.Errorf("parseListenerSpec(%q) diff (-got +want):\n%s", test.in, cmp.Diff(got, test.want, cmp.AllowUnexported(listenerSpec{})))
+			}
+		})
+	}
+}
+
+func TestBootzURL(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer lis.Close()
+	_, port, _ := net.SplitHostPort(lis.Addr().String())
+	tests := []struct {
+		desc    string
+		specs   []*listenerSpec
+		want    string
+		wantErr bool
+	}{{
+		desc:  "IPv4 listener",
+		specs: []*listenerSpec{{network: "tcp", address: "10.0.0.1:" + port, tls: true}},
+		want:  "bootz://10.0.0.1:" + port,
+	}, {
+		desc:  "IPv6 listener",
+		specs: []*listenerSpec{{network: "tcp", address: "[2001:db8::1]:" + port, tls: true}},
+		want:  "bootz://[2001:db8::1]:" + port,
+	}, {
+		desc: "Skips unix and plaintext listeners",
+		specs: []*listenerSpec{
+			{network: "unix", address: "/tmp/bootz.sock"},
+			{network: "tcp", address: "localhost:1234"},
+			{network: "tcp", address: "bootz.example.com:" + port, tls: true},
+		},
+		want: "bootz://bootz.example.com:" + port,
+	}, {
+		desc: "Skips loopback listeners",
+		specs: []*listenerSpec{
+			{network: "tcp", address: "localhost:1234", tls: true},
+			{network: "tcp", address: "[::1]:1234", tls: true},
+			{network: "tcp", address: "10.0.0.1:" + port, tls: true},
+		},
+		want: "bootz://10.0.0.1:" + port,
+	}, {
+		desc:    "Only loopback listeners",
+		specs:   []*listenerSpec{{network: "tcp", address: "127.0.0.1:" + port, tls: true}},
+		wantErr: true,
+	}, {
+		desc:    "No TLS listener",
+		specs:   []*listenerSpec{{network: "unix", address: "/tmp/bootz.sock"}},
+		wantErr: true,
+	}, {
+		desc:    "Wildcard on unknown interface",
+		specs:   []*listenerSpec{{network: "tcp", address: ":" + port, tls: true}},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			listeners := make([]net.Listener, len(test.specs))
+			for i := range listeners {
+				listeners[i] = lis
+			}
+			got, err := bootzURL(test.specs, listeners, "no-such-intf0")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("bootzURL() err = %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("bootzURL() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestMultipleListeners tests that the server serves on several listeners at once.
+func TestMultipleListeners(t *testing.T) {
+	flag.Parse()
+	sock := filepath.Join(t.TempDir(), "bootz.sock")
+	defer func(orig listenFlag) { listen = orig }(listen)
+	listen = nil
+	for _, l := range []string{"localhost:0", "unix://" + sock} {
+		if err := listen.Set(l); err != nil {
+			t.Fatalf("listen.Set(%q) err = %v", l, err)
+		}
+	}
+	s, err := newServer()
+	if err != nil {
+		t.Fatalf("newServer() err = %v, want nil", err)
+	}
+	go s.Start()
+	defer s.Stop()
+
+	creds := []grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	targets := []string{s.lis[0].Addr().String(), "unix://" + sock}
+	for i, target := range targets {
+		conn, err := grpc.Dial(target, creds[i])
+		if err != nil {
+			t.Fatalf("grpc.Dial(%q) err = %v", target, err)
+		}
+		defer conn.Close()
+		_, err = bpb.NewBootstrapClient(conn).ReportStatus(context.Background(), &bpb.ReportStatusRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ReportStatus() over %q err = %v, want InvalidArgument", target, err)
+		}
+		// The Admin service is only served on the unix socket.
+		_, err = apb.NewAdminClient(conn).ListChassis(context.Background(), &apb.ListChassisRequest{})
+		if wantCode := []codes.Code{codes.Unimplemented, codes.OK}[i]; status.Code(err) != wantCode {
+			t.Errorf("ListChassis() over %q err = %v, want %v", target, err, wantCode)
+		}
+	}
+}
+
+// TestListenerFailure tests that the server stops serving on all listeners when one fails.
+func TestListenerFailure(t *testing.T) {
+	flag.Parse()
+	defer func(orig listenFlag) { listen = orig }(listen)
+	listen = nil
+	for _, l := range []string{"localhost:0", "localhost:0"} {
+		if err := listen.Set(l); err != nil {
+			t.Fatalf("listen.Set(%q) err = %v", l, err)
+		}
+	}
+	s, err := newServer()
+	if err != nil {
+		t.Fatalf("newServer() err = %v, want nil", err)
+	}
+	defer s.Stop()
+	s.lis[0].Close()
+	if err := s.Start(); err == nil {
+		t.Fatalf("Start() with a closed listener err = nil, want error")
+	}
+	if conn, err := net.Dial("tcp", s.lis[1].Addr().String()); err == nil {
+		conn.Close()
+		t.Errorf("Dial() of the other listener succeeded after Start() failed, want error")
+	}
+}
+
+func TestServingHosts(t *testing.T) {
+	defer func(orig string) { *tlsHosts = orig }(*tlsHosts)
+	*tlsHosts = "bootz.example.com,10.0.0.1"
+	specs := []*listenerSpec{
+		{network: "tcp", address: "192.0.2.1:15006", tls: true},
+		{network: "tcp", address: "[2001:db8::1]:15006", tls: true},
+		{network: "tcp", address: "localhost:15006", tls: true},
+		{network: "tcp", address: "198.51.100.1:15006"},
+		{network: "unix", address: "/tmp/bootz.sock"},
+	}
+	want := []string{"localhost", "127.0.0.1", "::1", "bootz.example.com", "10.0.0.1", "192.0.2.1", "2001:db8::1"}
+	if got := servingHosts(specs); !cmp.Equal(got, want) {
+		t.Errorf("servingHosts() = %v, want %v", got, want)
+	}
+}
+
+// TestServingCertificate tests that the TLS serving certificate is distinct from the trust anchor and chains to it.
+func TestServingCertificate(t *testing.T) {
+	sa, err := artifacts.GenerateSecurityArtifacts(nil, "Google", "Cisco", "bootz.example.com", "192.0.2.1")
+	if err != nil {
+		t.Fatalf("GenerateSecurityArtifacts() err = %v", err)
+	}
+	if got := len(sa.TLSKeypair.Certificate); got != 2 {
+		t.Fatalf("TLS keypair has %d certificates, want leaf and intermediate", got)
+	}
+	leaf, err := x509.ParseCertificate(sa.TLSKeypair.Certificate[0])
+	if err != nil {
+		t.Fatalf("unable to parse leaf certificate: %v", err)
+	}
+	if leaf.IsCA || leaf.Equal(sa.TrustAnchor) {
+		t.Errorf("serving certificate %q must be a leaf distinct from the trust anchor", leaf.Subject)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(sa.TrustAnchor)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(sa.TLSIntermediate)
+	for _, host := range []string{"bootz.example.com", "192.0.2.1"} {
+		if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, Intermediates: intermediates}); err != nil {
+			t.Errorf("serving certificate does not verify for %q: %v", host, err)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: "other.example.com", Roots: roots, Intermediates: intermediates}); err == nil {
+		t.Errorf("serving certificate verifies for other.example.com, want error")
+	}
+}
+
+// TestMutualTLS tests that the server verifies the IDevID presented by the device.
+func TestMutualTLS(t *testing.T) {
+	flag.Parse()
+	dir := t.TempDir()
+	defer func(orig listenFlag, auth, idevids string) { listen, *clientAuth, *idevidDir = orig, auth, idevids }(listen, *clientAuth, *idevidDir)
+	listen = nil
+	if err := listen.Set("localhost:0"); err != nil {
+		t.Fatalf("listen.Set() err = %v", err)
+	}
+	*clientAuth = "require"
+	*idevidDir = dir
+	s, err := newServer()
+	if err != nil {
+		t.Fatalf("newServer() err = %v, want nil", err)
+	}
+	go s.Start()
+	defer s.Stop()
+
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			PartNumber:   "123",
+			ControlCards: []*bpb.ControlCard{
+				{SerialNumber: "123A", PartNumber: "123A", Slot: 1},
+				{SerialNumber: "123B", PartNumber: "123B", Slot: 2},
+			},
+		},
+		ControlCardState: &bpb.ControlCardState{
+			SerialNumber: "123A",
+			Status:       bpb.ControlCardState_CONTROL_CARD_STATUS_NOT_INITIALIZED,
+		},
+	}
+	tests := []struct {
+		desc     string
+		idevid   string
+		wantCode codes.Code
+	}{{
+		desc:     "IDevID of the active control card",
+		idevid:   "123A",
+		wantCode: codes.OK,
+	}, {
+		desc:     "IDevID of another control card",
+		idevid:   "123B",
+		wantCode: codes.PermissionDenied,
+	}, {
+		desc:     "No IDevID",
+		wantCode: codes.Unavailable,
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			conf := &tls.Config{InsecureSkipVerify: true}
+			if test.idevid != "" {
+				kp, err := tls.LoadX509KeyPair(filepath.Join(dir, test.idevid+".pem"), filepath.Join(dir, test.idevid+".key"))
+				if err != nil {
+					t.Fatalf("unable to load IDevID: %v", err)
+				}
+				conf.Certificates = []tls.Certificate{kp}
+			}
+			conn, err := grpc.Dial(s.lis[0].Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(conf)))
+			if err != nil {
+				t.Fatalf("grpc.Dial() err = %v", err)
+			}
+			defer conn.Close()
+			client := bpb.NewBootstrapClient(conn)
+			_, err = client.GetBootstrapData(context.Background(), req)
+			if got := status.Code(err); got != test.wantCode {
+				t.Errorf("GetBootstrapData() err = %v, want code %v", err, test.wantCode)
+			}
+			if test.wantCode != codes.OK {
+				return
+			}
+			// The IDevID binds status reports to the chassis of the device.
+			for serial, wantCode := range map[string]codes.Code{"123B": codes.OK, "456": codes.PermissionDenied} {
+				_, err := client.ReportStatus(context.Background(), &bpb.ReportStatusRequest{
+					Status: bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS,
+					States: []*bpb.ControlCardState{{SerialNumber: serial, Status: bpb.ControlCardState_CONTROL_CARD_STATUS_INITIALIZED}},
+				})
+				if got := status.Code(err); got != wantCode {
+					t.Errorf("ReportStatus() for %v err = %v, want code %v", serial, err, wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestDhcpEntries(t *testing.T) {
+	snap := &entitymanager.Snapshot{Chassis: []*epb.Chassis{
+		{SerialNumber: "123", DhcpConfig: &epb.DHCPConfig{HardwareAddress: "00:11:22:33:44:55", IpAddress: "10.0.0.2/24", Gateway: "10.0.0.1"}},
+		{SerialNumber: "456", DhcpConfig: &epb.DHCPConfig{IpAddress: "10.0.0.3/24"}},
+		{SerialNumber: "789"},
+	}}
+	got := dhcpEntries(snap)
+	want := map[string]*dhcp.Entry{
+		"00:11:22:33:44:55": {IP: "10.0.0.2/24", Gw: "10.0.0.1"},
+		"456":               {IP: "10.0.0.3/24"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("dhcpEntries() differs (-want +got):\n%s", diff)
+	}
+	if !sameDhcpEntries(want, got) {
+		t.Errorf("sameDhcpEntries() = false for equal entries")
+	}
+	// A changed address requires a reload, as does a removed chassis.
+	snap.Chassis[1].DhcpConfig.IpAddress = "10.0.0.4/24"
+	if sameDhcpEntries(want, dhcpEntries(snap)) {
+		t.Errorf("sameDhcpEntries() = true after changing an address")
+	}
+	snap.Chassis = snap.Chassis[:1]
+	if sameDhcpEntries(want, dhcpEntries(snap)) {
+		t.Errorf("sameDhcpEntries() = true after removing a chassis")
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	log "github.com/golang/glog"
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+const (
+	// DefaultNonceMinBytes is the default minimum number of random bytes of a nonce, matching the
+	// 128 bit nonces of the client emulator.
+	DefaultNonceMinBytes = 16
+	// DefaultNonceReplayWindow is the default time the nonces of a chassis are remembered to detect
+	// replays.
+	DefaultNonceReplayWindow = 24 * time.Hour
+
+	// ErrorDomain is the domain of the ErrorInfo details of the errors returned by the service.
+	ErrorDomain = "bootz.openconfig.net"
+	// ReasonNonceReplayed is the ErrorInfo reason of the errors returned for reused nonces.
+	ReasonNonceReplayed = "NONCE_REPLAYED"
+)
+
+// NoncePolicy sets the requirements on the nonces of GetBootstrapData requests. Requests without a
+// nonce are not affected by the policy.
+type NoncePolicy struct {
+	// MinBytes is the minimum number of bytes of a nonce once base64 decoded. Zero disables the
+	// format checks.
+	MinBytes int
+	// ReplayWindow is how long a nonce is remembered for a chassis. A chassis reusing a nonce
+	// within the window is rejected. Zero disables replay detection.
+	ReplayWindow time.Duration
+}
+
+// WithNoncePolicy enforces the nonce policy on GetBootstrapData requests.
+func WithNoncePolicy(p NoncePolicy) Option {
+	return func(s *Service) {
+		s.noncePolicy = p
+	}
+}
+
+// Auditor records security decisions about the requests of a chassis in its bootstrap history.
+type Auditor interface {
+	Audit(ctx context.Context, lookup *EntityLookup, serial, message string)
+}
+
+// WithAuditor records nonce policy decisions with the provided auditor.
+func WithAuditor(a Auditor) Option {
+	return func(s *Service) {
+		s.auditor = a
+	}
+}
+
+// nonceEncodings are the base64 encodings accepted for nonces.
+var nonceEncodings = []*base64.Encoding{
+	base64.StdEncoding,
+	base64.RawStdEncoding,
+	base64.URLEncoding,
+	base64.RawURLEncoding,
+}
+
+// checkNonceFormat checks that the nonce is base64 encoded and long enough.
+func (p NoncePolicy) checkNonceFormat(nonce string) error {
+	if p.MinBytes <= 0 {
+		return nil
+	}
+	var v violations
+	var decoded []byte
+	for _, enc := range nonceEncodings {
+		if b, err := enc.DecodeString(nonce); err == nil {
+			decoded = b
+			break
+		}
+	}
+	switch {
+	case decoded == nil:
+		v.add("nonce", "is not base64 encoded")
+	case len(decoded) < p.MinBytes:
+		v.add("nonce", "has %d bytes, want at least %d", len(decoded), p.MinBytes)
+	}
+	return v.err("GetBootstrapDataRequest")
+}
+
+// nonceFingerprint identifies a nonce in logs and history without recording it.
+func nonceFingerprint(nonce string) string {
+	h := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(h[:4])
+}
+
+// nonceKey returns the key the nonces of a chassis are remembered by: the inventory chassis the
+// request resolved to, whatever serial the request describes. If the entity manager does not
+// identify the chassis, the described serial is used, or the active control card of a modular
+// chassis which does not describe its serial.
+func nonceKey(chassis *ChassisEntity, lookup *EntityLookup, activeSerial string) EntityLookup {
+	if chassis.SerialNumber != "" {
+		return EntityLookup{Manufacturer: chassis.Manufacturer, SerialNumber: chassis.SerialNumber}
+	}
+	key := *lookup
+	if key.SerialNumber == "" {
+		key.SerialNumber = activeSerial
+	}
+	return key
+}
+
+// reserveNonce records the nonce for the chassis, and fails if the chassis already used it
+// within the replay window. Expired nonces of the chassis are forgotten.
+func (s *Service) reserveNonce(key EntityLookup, nonce string) error {
+	if s.noncePolicy.ReplayWindow <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	seen := s.nonces[key]
+	for n, t := range seen {
+		if now.Sub(t) >= s.noncePolicy.ReplayWindow {
+			delete(seen, n)
+		}
+	}
+	if t, ok := seen[nonce]; ok {
+		st := status.Newf(codes.AlreadyExists, "nonce %v was already used by chassis %v at %v", nonceFingerprint(nonce), key.SerialNumber, t.Format(time.RFC3339))
+		if ds, err := st.WithDetails(&errdetails.ErrorInfo{
+			Reason: ReasonNonceReplayed,
+			Domain: ErrorDomain,
+			Metadata: map[string]string{
+				"manufacturer":   key.Manufacturer,
+				"chassis_serial": key.SerialNumber,
+				"first_used":     t.Format(time.RFC3339),
+			},
+		}); err == nil {
+			st = ds
+		}
+		return st.Err()
+	}
+	if seen == nil {
+		seen = map[string]time.Time{}
+		s.nonces[key] = seen
+	}
+	seen[nonce] = now
+	return nil
+}
+
+// releaseNonce forgets the nonce of the chassis, so that a device can retry a request which
+// failed after its nonce was reserved.
+func (s *Service) releaseNonce(key EntityLookup, nonce string) {
+	if s.noncePolicy.ReplayWindow <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.nonces[key], nonce)
+}
+
+// audit records a decision about the request in the bootstrap history of its chassis.
+func (s *Service) audit(ctx context.Context, lookup *EntityLookup, req *bpb.GetBootstrapDataRequest, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	log.Infof("Chassis %v: %v", lookup.SerialNumber, msg)
+	if s.auditor == nil {
+		return
+	}
+	serial := req.GetControlCardState().GetSerialNumber()
+	if serial == "" {
+		serial = lookup.SerialNumber
+	}
+	s.auditor.Audit(ctx, lookup, serial, msg)
+}
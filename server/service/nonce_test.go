@@ -0,0 +1,197 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+func TestCheckNonceFormat(t *testing.T) {
+	random := make([]byte, 16)
+	for i := range random {
+		random[i] = byte(i * 17)
+	}
+	tests := []struct {
+		desc    string
+		policy  NoncePolicy
+		nonce   string
+		wantErr string
+	}{{
+		desc:   "standard encoding",
+		policy: NoncePolicy{MinBytes: 16},
+		nonce:  base64.StdEncoding.EncodeToString(random),
+	}, {
+		desc:   "unpadded URL encoding",
+		policy: NoncePolicy{MinBytes: 16},
+		nonce:  base64.RawURLEncoding.EncodeToString(random),
+	}, {
+		desc:    "not base64",
+		policy:  NoncePolicy{MinBytes: 16},
+		nonce:   "not a nonce!",
+		wantErr: "not base64 encoded",
+	}, {
+		desc:    "too short",
+		policy:  NoncePolicy{MinBytes: 16},
+		nonce:   base64.StdEncoding.EncodeToString(random[:8]),
+		wantErr: "has 8 bytes, want at least 16",
+	}, {
+		desc:  "format checks disabled",
+		nonce: "not a nonce!",
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			err := test.policy.checkNonceFormat(test.nonce)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkNonceFormat() err = %v, want nil", err)
+				}
+				return
+			}
+			if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("checkNonceFormat() err = %v, want InvalidArgument containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// fakeAuditor records the audited messages of each control card.
+type fakeAuditor struct {
+	messages map[string][]string
+}
+
+func (a *fakeAuditor) Audit(_ context.Context, _ *EntityLookup, serial, message string) {
+	a.messages[serial] = append(a.messages[serial], message)
+}
+
+// replayReason returns the ErrorInfo reason of err, if any.
+func replayReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestNonceReplay(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	auditor := &fakeAuditor{messages: map[string][]string{}}
+	s := New(fakeEntityManager{}, WithClock(clock.now), WithAuditor(auditor), WithNoncePolicy(NoncePolicy{
+		MinBytes:     DefaultNonceMinBytes,
+		ReplayWindow: time.Hour,
+	}))
+	ctx := peerContext("192.0.2.1")
+	nonce := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	request := func(serial, nonce string) error {
+		_, err := s.GetBootstrapData(ctx, &bpb.GetBootstrapDataRequest{
+			ChassisDescriptor: &bpb.ChassisDescriptor{
+				Manufacturer: "Cisco",
+				PartNumber:   "PN",
+				SerialNumber: serial,
+			},
+			Nonce: nonce,
+		})
+		return err
+	}
+
+	if err := request("A", nonce); err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	err := request("A", nonce)
+	if status.Code(err) != codes.AlreadyExists || replayReason(err) != ReasonNonceReplayed {
+		t.Errorf("GetBootstrapData() with a replayed nonce err = %v, want AlreadyExists with reason %v", err, ReasonNonceReplayed)
+	}
+	if err := request("B", nonce); err != nil {
+		t.Errorf("GetBootstrapData() with the nonce of another chassis err = %v", err)
+	}
+	if err := request("A", "short"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetBootstrapData() with a short nonce err = %v, want InvalidArgument", err)
+	}
+	clock.t = clock.t.Add(time.Hour)
+	if err := request("A", nonce); err != nil {
+		t.Errorf("GetBootstrapData() with a nonce past the replay window err = %v", err)
+	}
+
+	var got []string
+	for _, m := range auditor.messages["A"] {
+		got = append(got, strings.Fields(m)[2])
+	}
+	want := []string{"accepted", "rejected:", "rejected:", "accepted"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("audited decisions diff (-want, +got):\n%s", diff)
+	}
+}
+
+// cardEntityManager resolves chassis 123 by its serial or by its control card 123A, like the
+// inventory does.
+type cardEntityManager struct {
+	fakeEntityManager
+}
+
+func (cardEntityManager) ResolveChassis(_ context.Context, lookup *EntityLookup, ccSerial string) (*ChassisEntity, error) {
+	if lookup.SerialNumber != "123" && ccSerial != "123A" {
+		return nil, status.Errorf(codes.NotFound, "unknown chassis %v", lookup.SerialNumber)
+	}
+	return &ChassisEntity{Manufacturer: "Cisco", SerialNumber: "123"}, nil
+}
+
+func TestNonceReplayChangedSerial(t *testing.T) {
+	s := New(cardEntityManager{}, WithNoncePolicy(NoncePolicy{
+		MinBytes:     DefaultNonceMinBytes,
+		ReplayWindow: time.Hour,
+	}))
+	ctx := peerContext("192.0.2.1")
+	nonce := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	request := func(serial string) error {
+		_, err := s.GetBootstrapData(ctx, &bpb.GetBootstrapDataRequest{
+			ChassisDescriptor: &bpb.ChassisDescriptor{
+				Manufacturer: "Cisco",
+				PartNumber:   "PN",
+				SerialNumber: serial,
+				ControlCards: []*bpb.ControlCard{{SerialNumber: "123A", PartNumber: "PN-A"}},
+			},
+			ControlCardState: &bpb.ControlCardState{SerialNumber: "123A"},
+			Nonce:            nonce,
+		})
+		return err
+	}
+
+	if err := request("123"); err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	// The control card resolves to chassis 123 whatever serial the request describes.
+	if err := request("999"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetBootstrapData() describing another chassis err = %v, want PermissionDenied", err)
+	}
+	if err := request(""); status.Code(err) != codes.AlreadyExists || replayReason(err) != ReasonNonceReplayed {
+		t.Errorf("GetBootstrapData() replaying the nonce without a chassis serial err = %v, want AlreadyExists with reason %v", err, ReasonNonceReplayed)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if got := len(s.nonces); got != 1 {
+		t.Errorf("nonces are remembered for %d chassis, want 1", got)
+	}
+}
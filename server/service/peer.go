@@ -0,0 +1,156 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// PeerCertificate returns the verified client certificate of the caller, or nil if the
+// connection is not secured with mutual TLS.
+func PeerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
+
+// authenticatedKey is the context key marking authenticated bootstrap requests.
+type authenticatedKey struct{}
+
+// withAuthenticated marks the bootstrap request of the context as authenticated.
+func withAuthenticated(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authenticatedKey{}, true)
+}
+
+// Authenticated reports whether the bootstrap request of the context was authenticated, either by
+// the IDevID of the device or by a nonce, whose response is signed for the owner of the device.
+func Authenticated(ctx context.Context) bool {
+	ok, _ := ctx.Value(authenticatedKey{}).(bool)
+	return ok
+}
+
+// servingChainKey is the context key holding the certificate chain a listener serves.
+type servingChainKey struct{}
+
+// WithServingChain records the DER encoded certificate chain, from the leaf, that the listener of
+// the request serves, for listeners which do not serve the chain of the security artifacts.
+func WithServingChain(ctx context.Context, chain [][]byte) context.Context {
+	return context.WithValue(ctx, servingChainKey{}, chain)
+}
+
+// ServingChain returns the certificate chain recorded by WithServingChain, or nil if the request
+// was served the chain of the security artifacts.
+func ServingChain(ctx context.Context) [][]byte {
+	chain, _ := ctx.Value(servingChainKey{}).([][]byte)
+	return chain
+}
+
+// verifyIDevID checks that the IDevID presented by the caller, if any, belongs to the active
+// control card or to the chassis described in the request.
+func verifyIDevID(ctx context.Context, activeSerial, chassisSerial string) error {
+	cert := PeerCertificate(ctx)
+	if cert == nil {
+		return nil
+	}
+	serial := cert.Subject.SerialNumber
+	if serial == "" {
+		return status.Errorf(codes.PermissionDenied, "client certificate %q has no serial number", cert.Subject)
+	}
+	if serial != activeSerial && serial != chassisSerial {
+		return status.Errorf(codes.PermissionDenied, "client certificate serial %q matches neither the active control card %q nor the chassis %q", serial, activeSerial, chassisSerial)
+	}
+	return nil
+}
+
+// peerHost returns the host of the caller's address, which identifies the device across connections.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// caller identifies the chassis a device belongs to.
+type caller struct {
+	lookup   *EntityLookup
+	ccSerial string
+}
+
+// identifyCaller returns the chassis of the caller. The IDevID presented over mutual TLS takes
+// precedence over the chassis of the session named by the session ID metadata, and then of the
+// pending bootstrap session from the caller's address. Devices behind NAT or a relay share an
+// address, so a caller without a session ID is refused while sessions of more than one chassis or
+// control card are pending from its address. An IDevID is resolved by its serial alone, as for
+// verifyIDevID: its organization is the legal name of the vendor, which need not match the
+// manufacturer of the inventory.
+func (s *Service) identifyCaller(ctx context.Context) (*caller, error) {
+	if cert := PeerCertificate(ctx); cert != nil {
+		serial := cert.Subject.SerialNumber
+		if serial == "" {
+			return nil, status.Errorf(codes.PermissionDenied, "client certificate %q has no serial number", cert.Subject)
+		}
+		return &caller{lookup: &EntityLookup{SerialNumber: serial}, ccSerial: serial}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id := sessionID(ctx); id != "" {
+		sess, ok := s.sessionsByID[id]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "unknown bootstrap session %v", id)
+		}
+		lookup := sess.Chassis
+		return &caller{lookup: &lookup, ccSerial: sess.ActiveSerial}, nil
+	}
+	host := peerHost(ctx)
+	s.expireSessions(s.now())
+	var sess *Session
+	for _, v := range s.sessionsByID {
+		if host == "" || v.Peer != host || v.State != SessionPending {
+			continue
+		}
+		if sess != nil && (sess.Chassis != v.Chassis || sess.ActiveSerial != v.ActiveSerial) {
+			return nil, status.Errorf(codes.PermissionDenied, "bootstrap sessions of several devices are pending from %v, the caller must send its session ID", host)
+		}
+		if sess == nil || v.Created.After(sess.Created) {
+			sess = v
+		}
+	}
+	if sess == nil {
+		sess = s.sessionsByPeer[host]
+	}
+	if sess == nil {
+		return nil, status.Errorf(codes.PermissionDenied, "caller has neither an IDevID nor a bootstrap session")
+	}
+	lookup := sess.Chassis
+	return &caller{lookup: &lookup, ccSerial: sess.ActiveSerial}, nil
+}
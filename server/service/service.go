@@ -20,7 +20,10 @@ import (
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+	"sync"
+	"time"
 
+	"github.com/openconfig/bootz/server/events"
 	"github.com/openconfig/gnmi/errlist"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,6 +31,7 @@ import (
 
 	log "github.com/golang/glog"
 	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
 )
 
 // OVList is a mapping of control card serial number to ownership voucher.
@@ -47,12 +51,16 @@ type SecurityArtifacts struct {
 	// The Vendor CA represents a certificate authority on the vendor side. This CA signs Ownership Vouchers which are verified by the device.
 	VendorCA           *x509.Certificate
 	VendorCAPrivateKey crypto.PrivateKey
-	// The Trust Anchor is a self signed CA used to generate the TLS certificate.
+	// The Trust Anchor is a self signed CA at the root of the TLS certificate chain.
 	TrustAnchor           *x509.Certificate
 	TrustAnchorPrivateKey crypto.PrivateKey
+	// The TLS Intermediate is a CA signed by the Trust Anchor which issues the TLS serving certificate.
+	TLSIntermediate           *x509.Certificate
+	TLSIntermediatePrivateKey crypto.PrivateKey
 	// Ownership Vouchers are a list of PKCS7 messages signed by the Vendor CA. There is one per control card.
 	OV OVList
-	// The TLSKeypair is a TLS certificate used to secure connections between device and server. It is derived from the Trust Anchor.
+	// The TLSKeypair is a TLS certificate used to secure connections between device and server. It is issued by the
+	// TLS Intermediate and its certificate chain includes the intermediate.
 	TLSKeypair *tls.Certificate
 }
 
@@ -67,50 +75,154 @@ type EntityLookup struct {
 // configured.
 type ChassisEntity struct {
 	BootMode bpb.BootMode
+	// Manufacturer and SerialNumber identify the inventory chassis the lookup resolved to, which
+	// was found by its active control card if the lookup has no serial. They may be left empty by
+	// entity managers which do not track chassis identities.
+	Manufacturer string
+	SerialNumber string
 }
 
 // EntityManager maintains the entities and their states.
 type EntityManager interface {
 	ResolveChassis(context.Context, *EntityLookup, string) (*ChassisEntity, error)
 	GetBootstrapData(context.Context, *EntityLookup, *bpb.ControlCard) (*bpb.BootstrapDataResponse, error)
-	SetStatus(context.Context, *bpb.ReportStatusRequest) error
+	// SetStatus records a status report from a device of the chassis resolved from the lookup and
+	// control card serial. A lookup without manufacturer, from the IDevID of the device, resolves
+	// the chassis or control card serial of any manufacturer. States of control cards outside that
+	// chassis are rejected.
+	SetStatus(context.Context, *EntityLookup, string, *bpb.ReportStatusRequest) error
 	Sign(context.Context, *bpb.GetBootstrapDataResponse, *EntityLookup, string) error
 }
 
+// Discoverer records bootstrap requests from devices which are not in the inventory, so that
+// operators can review and approve them. Discover returns true if it added the device to the
+// inventory, e.g. from a fallback profile, in which case the chassis is resolved again.
+type Discoverer interface {
+	Discover(context.Context, *bpb.GetBootstrapDataRequest) bool
+}
+
+// WithDiscoverer records requests from unknown chassis with the provided discoverer.
+func WithDiscoverer(d Discoverer) Option {
+	return func(s *Service) {
+		s.discoverer = d
+	}
+}
+
+// Committer completes the bootstrap data of a chassis once its response is signed, e.g. by recording
+// the device certificates it holds. Nothing served in a response which fails before is committed.
+type Committer interface {
+	Commit(context.Context, *EntityLookup, *bpb.GetBootstrapDataResponse) error
+}
+
+// WithCommitter commits the bootstrap data responses with the provided committer.
+func WithCommitter(c Committer) Option {
+	return func(s *Service) {
+		s.committer = c
+	}
+}
+
 // Service represents the server and entity manager.
 type Service struct {
 	bpb.UnimplementedBootstrapServer
 	em EntityManager
+
+	discoverer       Discoverer
+	committer        Committer
+	auditor          Auditor
+	noncePolicy      NoncePolicy
+	events           *events.Bus
+	sessionTimeout   time.Duration
+	sessionRetention time.Duration
+	now              func() time.Time
+
+	mu           sync.Mutex
+	sessions     map[sessionKey]*Session
+	sessionsByID map[string]*Session
+	// sessionsByPeer maps the address of a device to its latest session.
+	sessionsByPeer map[string]*Session
+	// nonces maps each chassis to the time it first used each of its recent nonces.
+	nonces map[EntityLookup]map[string]time.Time
 }
 
 func (s *Service) GetBootstrapData(ctx context.Context, req *bpb.GetBootstrapDataRequest) (*bpb.GetBootstrapDataResponse, error) {
+	s.publish(ctx, apb.EventType_EVENT_TYPE_BOOTSTRAP_REQUESTED, req, "")
+	resp, err := s.getBootstrapData(ctx, req)
+	if err != nil {
+		s.publish(ctx, apb.EventType_EVENT_TYPE_REQUEST_FAILED, req, err.Error())
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Service) getBootstrapData(ctx context.Context, req *bpb.GetBootstrapDataRequest) (_ *bpb.GetBootstrapDataResponse, err error) {
 	log.Infof("=============================================================================")
 	log.Infof("==================== Received request for bootstrap data ====================")
 	log.Infof("=============================================================================")
-	fixedChasis := true
-	ccSerial := ""
+	if err := validateBootstrapDataRequest(req); err != nil {
+		return nil, err
+	}
 	chassisDesc := req.GetChassisDescriptor()
-	if len(chassisDesc.GetControlCards()) >= 1 {
-		fixedChasis = false
-		ccSerial = chassisDesc.GetControlCards()[0].GetSerialNumber()
+	fixedChasis := len(chassisDesc.GetControlCards()) == 0
+	// The chassis is resolved by the active control card, which sent the request.
+	ccSerial := ""
+	if !fixedChasis {
+		ccSerial = req.GetControlCardState().GetSerialNumber()
 	}
 	log.Infof("Requesting for %v chassis %v", chassisDesc.GetManufacturer(), chassisDesc.GetSerialNumber())
+	if err := verifyIDevID(ctx, req.GetControlCardState().GetSerialNumber(), chassisDesc.GetSerialNumber()); err != nil {
+		return nil, err
+	}
 	lookup := &EntityLookup{
 		Manufacturer: chassisDesc.GetManufacturer(),
 		SerialNumber: chassisDesc.GetSerialNumber(),
 	}
 	// Validate the chassis can be serviced
 	chassis, err := s.em.ResolveChassis(ctx, lookup, ccSerial)
+	if err != nil {
+		if status.Code(err) == codes.NotFound && s.discoverer != nil && s.discoverer.Discover(ctx, req) {
+			chassis, err = s.em.ResolveChassis(ctx, lookup, ccSerial)
+		}
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to resolve chassis to inventory %+v, err: %v", chassisDesc, err)
 	}
 	log.Infof("Verified server can resolve chassis")
+	// A device may only describe the chassis its active control card belongs to.
+	if chassis.SerialNumber != "" && lookup.SerialNumber != "" && lookup.SerialNumber != chassis.SerialNumber {
+		owner := &EntityLookup{Manufacturer: chassis.Manufacturer, SerialNumber: chassis.SerialNumber}
+		s.audit(ctx, owner, req, "request claiming chassis %v rejected", lookup.SerialNumber)
+		return nil, status.Errorf(codes.PermissionDenied, "control card %v belongs to chassis %v, not %v", ccSerial, chassis.SerialNumber, lookup.SerialNumber)
+	}
 
 	// If chassis can only be booted into secure mode then return error
 	if chassis.BootMode == bpb.BootMode_BOOT_MODE_SECURE && req.GetNonce() == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "chassis requires secure boot only")
 	}
 
+	if nonce := req.GetNonce(); nonce != "" {
+		fp := nonceFingerprint(nonce)
+		if err := s.noncePolicy.checkNonceFormat(nonce); err != nil {
+			s.audit(ctx, lookup, req, "nonce %v rejected: %v", fp, status.Convert(err).Message())
+			return nil, err
+		}
+		key := nonceKey(chassis, lookup, req.GetControlCardState().GetSerialNumber())
+		if err := s.reserveNonce(key, nonce); err != nil {
+			s.audit(ctx, lookup, req, "nonce %v rejected: replayed within %v", fp, s.noncePolicy.ReplayWindow)
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				s.releaseNonce(key, nonce)
+			}
+		}()
+		s.audit(ctx, lookup, req, "nonce %v accepted", fp)
+	}
+
+	// Device secrets, such as issued certificates, are only served to authenticated requests.
+	if PeerCertificate(ctx) != nil || req.GetNonce() != "" {
+		ctx = withAuthenticated(ctx)
+	}
+
 	// Iterate over the control cards and fetch data for each card.
 	var errs errlist.List
 
@@ -170,15 +282,52 @@ func (s *Service) GetBootstrapData(ctx context.Context, req *bpb.GetBootstrapDat
 		}
 		log.Infof("Signed with nonce")
 	}
+	if s.committer != nil {
+		if err := s.committer.Commit(ctx, lookup, resp); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.startSession(ctx, lookup, nonce, req.GetControlCardState().GetSerialNumber(), resp); err != nil {
+		return nil, err
+	}
 	log.Infof("Returning response")
 	return resp, nil
 }
 
 func (s *Service) ReportStatus(ctx context.Context, req *bpb.ReportStatusRequest) (*bpb.EmptyResponse, error) {
+	resp, err := s.reportStatus(ctx, req)
+	if err != nil && s.events != nil {
+		e := &apb.Event{
+			Type:            apb.EventType_EVENT_TYPE_REQUEST_FAILED,
+			Source:          peerHost(ctx),
+			Message:         err.Error(),
+			BootstrapStatus: req.GetStatus(),
+			States:          req.GetStates(),
+		}
+		for _, st := range req.GetStates() {
+			e.ControlCardSerials = append(e.ControlCardSerials, st.GetSerialNumber())
+		}
+		s.events.Publish(e)
+	}
+	return resp, err
+}
+
+func (s *Service) reportStatus(ctx context.Context, req *bpb.ReportStatusRequest) (*bpb.EmptyResponse, error) {
 	log.Infof("=============================================================================")
 	log.Infof("========================== Status report received ===========================")
 	log.Infof("=============================================================================")
-	return &bpb.EmptyResponse{}, s.em.SetStatus(ctx, req)
+	if err := validateReportStatusRequest(req); err != nil {
+		return nil, err
+	}
+	c, err := s.identifyCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.em.SetStatus(ctx, c.lookup, c.ccSerial, req); err != nil {
+		return nil, err
+	}
+	s.updateSession(ctx, c, req)
+	return &bpb.EmptyResponse{}, nil
 }
 
 // SetDeviceConfiguration is a public API for allowing the device configuration to be set for each device the
@@ -187,9 +336,38 @@ func (s *Service) SetDeviceConfiguration(ctx context.Context) error {
 	return status.Errorf(codes.Unimplemented, "Unimplemented")
 }
 
+// publish sends an event about a bootstrap data request to the events bus, if any.
+func (s *Service) publish(ctx context.Context, t apb.EventType, req *bpb.GetBootstrapDataRequest, msg string) {
+	if s.events == nil {
+		return
+	}
+	e := &apb.Event{
+		Type:          t,
+		Manufacturer:  req.GetChassisDescriptor().GetManufacturer(),
+		ChassisSerial: req.GetChassisDescriptor().GetSerialNumber(),
+		Source:        peerHost(ctx),
+		Message:       msg,
+	}
+	for _, cc := range req.GetChassisDescriptor().GetControlCards() {
+		e.ControlCardSerials = append(e.ControlCardSerials, cc.GetSerialNumber())
+	}
+	s.events.Publish(e)
+}
+
 // New creates a new service.
-func New(em EntityManager) *Service {
-	return &Service{
-		em: em,
+func New(em EntityManager, opts ...Option) *Service {
+	s := &Service{
+		em:               em,
+		sessionTimeout:   DefaultSessionTimeout,
+		sessionRetention: DefaultSessionRetention,
+		now:              time.Now,
+		sessions:         map[sessionKey]*Session{},
+		sessionsByID:     map[string]*Session{},
+		sessionsByPeer:   map[string]*Session{},
+		nonces:           map[EntityLookup]map[string]time.Time{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
@@ -0,0 +1,312 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/openconfig/bootz/server/events"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	log "github.com/golang/glog"
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+// SessionIDHeader is the gRPC metadata key carrying the session ID. The server sends it in the
+// GetBootstrapData response headers and devices may echo it in ReportStatus to correlate the report.
+const SessionIDHeader = "bootz-session-id"
+
+const (
+	// DefaultSessionTimeout is how long a device has to report success after fetching bootstrap data.
+	DefaultSessionTimeout = 30 * time.Minute
+	// DefaultSessionRetention is how long sessions are kept after they were last updated.
+	DefaultSessionRetention = 24 * time.Hour
+)
+
+// SessionState is the outcome of a bootstrap session.
+type SessionState string
+
+const (
+	// SessionPending means the device fetched bootstrap data and has not reported a final status yet.
+	SessionPending SessionState = "PENDING"
+	// SessionSucceeded means the device reported BOOTSTRAP_STATUS_SUCCESS.
+	SessionSucceeded SessionState = "SUCCEEDED"
+	// SessionFailed means the device reported BOOTSTRAP_STATUS_FAILURE.
+	SessionFailed SessionState = "FAILED"
+	// SessionTimedOut means the device did not report success before the session deadline.
+	SessionTimedOut SessionState = "TIMED_OUT"
+)
+
+// Session links a GetBootstrapData call to the status reports that follow it.
+type Session struct {
+	// ID is the correlation ID sent to the device.
+	ID string
+	// Chassis and Nonce are the key of the session.
+	Chassis EntityLookup
+	Nonce   string
+	// ActiveSerial is the serial of the control card which fetched the data.
+	ActiveSerial string
+	// Peer is the address of the device.
+	Peer string
+	// DataVersion is the hex encoded SHA-256 hash of the serialized bootstrap data served.
+	DataVersion string
+	State       SessionState
+	// StatusMessage is the message of the last status report.
+	StatusMessage string
+	Created       time.Time
+	Updated       time.Time
+	// Completed is set once the session reaches a final state.
+	Completed time.Time
+}
+
+// Duration returns the time from fetching the bootstrap data to the final outcome, or zero if the session is pending.
+func (s *Session) Duration() time.Duration {
+	if s.Completed.IsZero() {
+		return 0
+	}
+	return s.Completed.Sub(s.Created)
+}
+
+// sessionKey identifies a session by chassis and nonce.
+type sessionKey struct {
+	chassis EntityLookup
+	nonce   string
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithSessionTimeout sets how long a device has to report success before its session times out.
+func WithSessionTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		s.sessionTimeout = d
+	}
+}
+
+// WithSessionRetention sets how long sessions are kept after they were last updated.
+func WithSessionRetention(d time.Duration) Option {
+	return func(s *Service) {
+		s.sessionRetention = d
+	}
+}
+
+// WithEvents publishes bootstrap request, data served and session timeout events to the bus.
+func WithEvents(bus *events.Bus) Option {
+	return func(s *Service) {
+		s.events = bus
+	}
+}
+
+// WithClock sets the clock used to timestamp sessions. Intended for tests.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
+	}
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// startSession records a session for bootstrap data served to the caller and sends its ID in the response headers.
+func (s *Service) startSession(ctx context.Context, lookup *EntityLookup, nonce, activeSerial string, resp *bpb.GetBootstrapDataResponse) error {
+	id, err := newSessionID()
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to generate session ID: %v", err)
+	}
+	hash := sha256.Sum256(resp.GetSerializedBootstrapData())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expireSessions(now)
+	sess := &Session{
+		ID:           id,
+		Chassis:      *lookup,
+		Nonce:        nonce,
+		ActiveSerial: activeSerial,
+		Peer:         peerHost(ctx),
+		DataVersion:  hex.EncodeToString(hash[:]),
+		State:        SessionPending,
+		Created:      now,
+		Updated:      now,
+	}
+	key := sessionKey{chassis: *lookup, nonce: nonce}
+	if old, ok := s.sessions[key]; ok {
+		delete(s.sessionsByID, old.ID)
+	}
+	s.sessions[key] = sess
+	s.sessionsByID[id] = sess
+	if sess.Peer != "" {
+		s.sessionsByPeer[sess.Peer] = sess
+	}
+	log.Infof("Started bootstrap session %v for %v chassis %v (control card %v)", id, lookup.Manufacturer, lookup.SerialNumber, activeSerial)
+	s.events.Publish(sess.event(apb.EventType_EVENT_TYPE_DATA_SERVED, "data version "+sess.DataVersion))
+	if err := grpc.SetHeader(ctx, metadata.Pairs(SessionIDHeader, id)); err != nil {
+		log.Warningf("Unable to send session ID %v to the device: %v", id, err)
+	}
+	return nil
+}
+
+// event returns an event about the session.
+func (sess *Session) event(t apb.EventType, msg string) *apb.Event {
+	e := &apb.Event{
+		Type:          t,
+		Manufacturer:  sess.Chassis.Manufacturer,
+		ChassisSerial: sess.Chassis.SerialNumber,
+		SessionId:     sess.ID,
+		Source:        sess.Peer,
+		Message:       msg,
+	}
+	if sess.ActiveSerial != "" {
+		e.ControlCardSerials = []string{sess.ActiveSerial}
+	}
+	return e
+}
+
+// ExpireSessions marks the pending sessions past their deadline as timed out.
+// It is called on every session access, and may be called periodically so that
+// timeouts are noticed without any device activity.
+func (s *Service) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireSessions(s.now())
+}
+
+// sessionID returns the session ID echoed by the device in the request metadata, if any.
+func sessionID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ids := md.Get(SessionIDHeader); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// matches reports whether the session belongs to the caller. Callers identified by their IDevID
+// have no manufacturer and match sessions of any manufacturer.
+func (sess *Session) matches(c *caller) bool {
+	if c.lookup.Manufacturer != "" && sess.Chassis.Manufacturer != c.lookup.Manufacturer {
+		return false
+	}
+	return (c.ccSerial != "" && c.ccSerial == sess.ActiveSerial) || (c.lookup.SerialNumber != "" && c.lookup.SerialNumber == sess.Chassis.SerialNumber)
+}
+
+// updateSession records the status report of the caller on its session. The session is the one named by
+// the session ID in the request metadata or, if none is provided, the most recent session of the caller.
+func (s *Service) updateSession(ctx context.Context, c *caller, req *bpb.ReportStatusRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expireSessions(now)
+	var sess *Session
+	if id := sessionID(ctx); id != "" {
+		if v, ok := s.sessionsByID[id]; ok && v.matches(c) {
+			sess = v
+		} else {
+			log.Warningf("Ignoring unknown session ID %v in status report", id)
+		}
+	}
+	if sess == nil {
+		for _, v := range s.sessionsByID {
+			if v.matches(c) && (sess == nil || v.Created.After(sess.Created)) {
+				sess = v
+			}
+		}
+	}
+	if sess == nil {
+		log.Warningf("No bootstrap session found for status report from %v", c.lookup)
+		return
+	}
+	sess.Updated = now
+	sess.StatusMessage = req.GetStatusMessage()
+	if sess.State != SessionPending {
+		return
+	}
+	switch req.GetStatus() {
+	case bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS:
+		sess.State = SessionSucceeded
+	case bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE:
+		sess.State = SessionFailed
+	default:
+		return
+	}
+	sess.Completed = now
+	log.Infof("Bootstrap session %v %v after %v", sess.ID, sess.State, sess.Duration())
+}
+
+// expireSessions marks pending sessions past their deadline as timed out and drops sessions past retention.
+// The caller must hold s.mu.
+func (s *Service) expireSessions(now time.Time) {
+	for key, sess := range s.sessions {
+		if sess.State == SessionPending && now.Sub(sess.Created) > s.sessionTimeout {
+			sess.State = SessionTimedOut
+			sess.Completed = now
+			sess.Updated = now
+			log.Warningf("Bootstrap session %v for %v chassis %v timed out without a successful status report", sess.ID, sess.Chassis.Manufacturer, sess.Chassis.SerialNumber)
+			s.events.Publish(sess.event(apb.EventType_EVENT_TYPE_SESSION_TIMED_OUT, "no successful status report within "+s.sessionTimeout.String()))
+		}
+		if now.Sub(sess.Updated) > s.sessionRetention {
+			delete(s.sessions, key)
+			delete(s.sessionsByID, sess.ID)
+			if s.sessionsByPeer[sess.Peer] == sess {
+				delete(s.sessionsByPeer, sess.Peer)
+			}
+		}
+	}
+}
+
+// Sessions returns a copy of the known bootstrap sessions, oldest first.
+func (s *Service) Sessions() []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireSessions(s.now())
+	var sessions []*Session
+	for _, sess := range s.sessions {
+		c := *sess
+		sessions = append(sessions, &c)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Created.Before(sessions[j].Created)
+	})
+	return sessions
+}
+
+// Session returns a copy of the session with the provided ID.
+func (s *Service) Session(id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireSessions(s.now())
+	sess, ok := s.sessionsByID[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "session %v not found", id)
+	}
+	c := *sess
+	return &c, nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/bootz/server/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+// fakeEntityManager serves empty bootstrap data for any chassis.
+type fakeEntityManager struct{}
+
+func (fakeEntityManager) ResolveChassis(context.Context, *EntityLookup, string) (*ChassisEntity, error) {
+	return &ChassisEntity{}, nil
+}
+
+func (fakeEntityManager) GetBootstrapData(_ context.Context, _ *EntityLookup, cc *bpb.ControlCard) (*bpb.BootstrapDataResponse, error) {
+	return &bpb.BootstrapDataResponse{SerialNum: cc.GetSerialNumber()}, nil
+}
+
+func (fakeEntityManager) SetStatus(context.Context, *EntityLookup, string, *bpb.ReportStatusRequest) error {
+	return nil
+}
+
+func (fakeEntityManager) Sign(context.Context, *bpb.GetBootstrapDataResponse, *EntityLookup, string) error {
+	return nil
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func peerContext(addr string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+}
+
+func bootstrap(t *testing.T, s *Service, ctx context.Context, serial string) {
+	t.Helper()
+	_, err := s.GetBootstrapData(ctx, &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			PartNumber:   "PN",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: serial, PartNumber: "PN-A"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: serial},
+		Nonce:            "nonce-" + serial,
+	})
+	if err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+}
+
+func report(s *Service, ctx context.Context, serial string, st bpb.ReportStatusRequest_BootstrapStatus) error {
+	_, err := s.ReportStatus(ctx, &bpb.ReportStatusRequest{
+		Status:        st,
+		StatusMessage: st.String(),
+		States:        []*bpb.ControlCardState{{SerialNumber: serial}},
+	})
+	return err
+}
+
+func TestSessions(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	bus := events.NewBus(events.DefaultBufferSize)
+	start := bus.Cursor()
+	s := New(fakeEntityManager{}, WithSessionTimeout(10*time.Minute), WithClock(clock.now), WithEvents(bus))
+	devA, devB, devC := peerContext("192.0.2.1"), peerContext("192.0.2.2"), peerContext("192.0.2.3")
+
+	if err := report(s, devA, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ReportStatus() without a session err = %v, want PermissionDenied", err)
+	}
+
+	bootstrap(t, s, devA, "A")
+	bootstrap(t, s, devB, "B")
+	bootstrap(t, s, devC, "C")
+	clock.t = clock.t.Add(time.Minute)
+	if err := report(s, devA, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_INITIATED); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	clock.t = clock.t.Add(4 * time.Minute)
+	if err := report(s, devA, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	if err := report(s, devB, "B", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	clock.t = clock.t.Add(6 * time.Minute)
+
+	want := map[string]struct {
+		state    SessionState
+		duration time.Duration
+	}{
+		"A": {SessionSucceeded, 5 * time.Minute},
+		"B": {SessionFailed, 5 * time.Minute},
+		"C": {SessionTimedOut, 11 * time.Minute},
+	}
+	sessions := s.Sessions()
+	if len(sessions) != len(want) {
+		t.Fatalf("Sessions() returned %d sessions, want %d", len(sessions), len(want))
+	}
+	for _, sess := range sessions {
+		w := want[sess.ActiveSerial]
+		if sess.State != w.state || sess.Duration() != w.duration {
+			t.Errorf("session of %v is %v after %v, want %v after %v", sess.ActiveSerial, sess.State, sess.Duration(), w.state, w.duration)
+		}
+		if sess.Nonce != "nonce-"+sess.ActiveSerial || sess.Peer == "" || sess.DataVersion == "" {
+			t.Errorf("session of %v = %+v, want nonce, peer and data version set", sess.ActiveSerial, sess)
+		}
+	}
+
+	published, _, err := bus.Since(start)
+	if err != nil {
+		t.Fatalf("Since() err = %v", err)
+	}
+	counts := map[apb.EventType]int{}
+	for _, e := range published {
+		counts[e.GetType()]++
+	}
+	wantCounts := map[apb.EventType]int{
+		apb.EventType_EVENT_TYPE_BOOTSTRAP_REQUESTED: 3,
+		apb.EventType_EVENT_TYPE_DATA_SERVED:         3,
+		apb.EventType_EVENT_TYPE_REQUEST_FAILED:      1,
+		apb.EventType_EVENT_TYPE_SESSION_TIMED_OUT:   1,
+	}
+	if !cmp.Equal(counts, wantCounts) {
+		t.Errorf("published events = %v, want %v", counts, wantCounts)
+	}
+
+	// A late success does not revive a timed out session.
+	if err := report(s, devC, "C", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	var timedOut string
+	for _, sess := range sessions {
+		if sess.ActiveSerial == "C" {
+			timedOut = sess.ID
+		}
+	}
+	sess, err := s.Session(timedOut)
+	if err != nil {
+		t.Fatalf("Session() err = %v", err)
+	}
+	if sess.State != SessionTimedOut || sess.StatusMessage != "BOOTSTRAP_STATUS_SUCCESS" {
+		t.Errorf("Session() = %v %q, want %v with the late status message", sess.State, sess.StatusMessage, SessionTimedOut)
+	}
+
+	clock.t = clock.t.Add(DefaultSessionRetention + time.Minute)
+	if got := s.Sessions(); len(got) != 0 {
+		t.Errorf("Sessions() after retention = %d sessions, want none", len(got))
+	}
+}
+
+func TestSessionIDHeader(t *testing.T) {
+	s := New(fakeEntityManager{})
+	dev := peerContext("192.0.2.1")
+	bootstrap(t, s, dev, "A")
+	first := s.Sessions()[0]
+	// A second fetch with another nonce starts another session.
+	_, err := s.GetBootstrapData(dev, &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{Manufacturer: "Cisco", PartNumber: "PN", ControlCards: []*bpb.ControlCard{{SerialNumber: "A", PartNumber: "PN-A"}}},
+		ControlCardState:  &bpb.ControlCardState{SerialNumber: "A"},
+		Nonce:             "other",
+	})
+	if err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	ctx := metadata.NewIncomingContext(dev, metadata.Pairs(SessionIDHeader, first.ID))
+	if err := report(s, ctx, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	for _, sess := range s.Sessions() {
+		want := SessionPending
+		if sess.ID == first.ID {
+			want = SessionSucceeded
+		}
+		if sess.State != want {
+			t.Errorf("session with nonce %q is %v, want %v", sess.Nonce, sess.State, want)
+		}
+	}
+}
+
+// TestSharedPeerAddress tests that devices behind the same address report the status of their
+// own session when they echo its ID, and are refused without it while their sessions are pending.
+func TestSharedPeerAddress(t *testing.T) {
+	s := New(fakeEntityManager{})
+	nat := peerContext("192.0.2.1")
+	bootstrap(t, s, nat, "A")
+	bootstrap(t, s, nat, "B")
+	ids := map[string]string{}
+	for _, sess := range s.Sessions() {
+		ids[sess.ActiveSerial] = sess.ID
+	}
+	if err := report(s, nat, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ReportStatus() without a session ID err = %v, want PermissionDenied", err)
+	}
+	ctx := metadata.NewIncomingContext(nat, metadata.Pairs(SessionIDHeader, ids["A"]))
+	if err := report(s, ctx, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	// Without a session ID, the caller is the only device with a pending session at the address.
+	if err := report(s, nat, "B", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_FAILURE); err != nil {
+		t.Fatalf("ReportStatus() err = %v", err)
+	}
+	want := map[string]SessionState{"A": SessionSucceeded, "B": SessionFailed}
+	for _, sess := range s.Sessions() {
+		if sess.State != want[sess.ActiveSerial] {
+			t.Errorf("session of %v is %v, want %v", sess.ActiveSerial, sess.State, want[sess.ActiveSerial])
+		}
+	}
+	ctx = metadata.NewIncomingContext(nat, metadata.Pairs(SessionIDHeader, "unknown"))
+	if err := report(s, ctx, "A", bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ReportStatus() with an unknown session ID err = %v, want PermissionDenied", err)
+	}
+}
+
+// committingEntityManager records whether bootstrap data was fetched for an authenticated
+// request, and the responses committed, and fails to sign if signErr is set.
+type committingEntityManager struct {
+	fakeEntityManager
+	signErr       error
+	authenticated bool
+	committed     []*bpb.GetBootstrapDataResponse
+}
+
+func (em *committingEntityManager) GetBootstrapData(ctx context.Context, lookup *EntityLookup, cc *bpb.ControlCard) (*bpb.BootstrapDataResponse, error) {
+	em.authenticated = Authenticated(ctx)
+	return em.fakeEntityManager.GetBootstrapData(ctx, lookup, cc)
+}
+
+func (em *committingEntityManager) Sign(context.Context, *bpb.GetBootstrapDataResponse, *EntityLookup, string) error {
+	return em.signErr
+}
+
+func (em *committingEntityManager) Commit(_ context.Context, _ *EntityLookup, resp *bpb.GetBootstrapDataResponse) error {
+	em.committed = append(em.committed, resp)
+	return nil
+}
+
+func TestCommit(t *testing.T) {
+	em := &committingEntityManager{signErr: status.Errorf(codes.Internal, "no key")}
+	s := New(em, WithCommitter(em))
+	req := &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			PartNumber:   "PN",
+			ControlCards: []*bpb.ControlCard{{SerialNumber: "A", PartNumber: "PN-A"}},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "A"},
+	}
+	if _, err := s.GetBootstrapData(peerContext("192.0.2.1"), req); err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	if em.authenticated {
+		t.Errorf("GetBootstrapData() without IDevID or nonce is authenticated")
+	}
+	if len(em.committed) != 1 {
+		t.Errorf("GetBootstrapData() committed %d responses, want 1", len(em.committed))
+	}
+
+	req.Nonce = "nonce-A"
+	if _, err := s.GetBootstrapData(peerContext("192.0.2.1"), req); status.Code(err) != codes.Internal {
+		t.Fatalf("GetBootstrapData() with a failing signature err = %v, want Internal", err)
+	}
+	if !em.authenticated {
+		t.Errorf("GetBootstrapData() with a nonce is not authenticated")
+	}
+	if len(em.committed) != 1 {
+		t.Errorf("GetBootstrapData() with a failing signature committed its response")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+// violations collects the invalid fields of a request.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns an InvalidArgument error carrying a BadRequest detail with the violations, or nil
+// if there are none.
+func (v violations) err(request string) error {
+	if len(v) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(v))
+	for _, fv := range v {
+		msgs = append(msgs, fv.GetField()+": "+fv.GetDescription())
+	}
+	st := status.Newf(codes.InvalidArgument, "invalid %s: %s", request, strings.Join(msgs, "; "))
+	if ds, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = ds
+	}
+	return st.Err()
+}
+
+// validateBootstrapDataRequest checks the chassis descriptor and control card state of a
+// GetBootstrapData request. A modular chassis must describe each of its control cards in a
+// distinct slot and name the active one in the control card state, and a fixed form factor
+// chassis must have a serial number.
+func validateBootstrapDataRequest(req *bpb.GetBootstrapDataRequest) error {
+	var v violations
+	desc := req.GetChassisDescriptor()
+	if desc == nil {
+		v.add("chassis_descriptor", "is required")
+		return v.err("GetBootstrapDataRequest")
+	}
+	if desc.GetManufacturer() == "" {
+		v.add("chassis_descriptor.manufacturer", "is required")
+	}
+	if desc.GetPartNumber() == "" {
+		v.add("chassis_descriptor.part_number", "is required")
+	}
+	cards := desc.GetControlCards()
+	if len(cards) == 0 && desc.GetSerialNumber() == "" {
+		v.add("chassis_descriptor.serial_number", "is required for a fixed form factor chassis")
+	}
+	serials := map[string]int{}
+	slots := map[int32]int{}
+	for i, cc := range cards {
+		field := fmt.Sprintf("chassis_descriptor.control_cards[%d]", i)
+		if cc.GetSerialNumber() == "" {
+			v.add(field+".serial_number", "is required")
+		} else if j, ok := serials[cc.GetSerialNumber()]; ok {
+			v.add(field+".serial_number", "%q is also the serial number of control_cards[%d]", cc.GetSerialNumber(), j)
+		} else {
+			serials[cc.GetSerialNumber()] = i
+		}
+		if cc.GetPartNumber() == "" {
+			v.add(field+".part_number", "is required")
+		}
+		if j, ok := slots[cc.GetSlot()]; ok {
+			v.add(field+".slot", "slot %d is already populated by control_cards[%d]", cc.GetSlot(), j)
+		} else {
+			slots[cc.GetSlot()] = i
+		}
+	}
+	active := req.GetControlCardState().GetSerialNumber()
+	switch {
+	case len(cards) == 0:
+		if active != "" && active != desc.GetSerialNumber() {
+			v.add("control_card_state.serial_number", "%q is not the serial number of the fixed form factor chassis", active)
+		}
+	case active == "":
+		v.add("control_card_state.serial_number", "is required for a modular chassis")
+	default:
+		if _, ok := serials[active]; !ok {
+			v.add("control_card_state.serial_number", "control card %q is not in the chassis descriptor", active)
+		}
+	}
+	return v.err("GetBootstrapDataRequest")
+}
+
+// validateReportStatusRequest checks that each state of a ReportStatus request names a distinct
+// control card or fixed form factor chassis.
+func validateReportStatusRequest(req *bpb.ReportStatusRequest) error {
+	var v violations
+	if len(req.GetStates()) == 0 {
+		v.add("states", "no control card or fixed chassis states provided")
+	}
+	serials := map[string]int{}
+	for i, st := range req.GetStates() {
+		field := fmt.Sprintf("states[%d].serial_number", i)
+		if st.GetSerialNumber() == "" {
+			v.add(field, "is required")
+		} else if j, ok := serials[st.GetSerialNumber()]; ok {
+			v.add(field, "%q is also the serial number of states[%d]", st.GetSerialNumber(), j)
+		} else {
+			serials[st.GetSerialNumber()] = i
+		}
+	}
+	return v.err("ReportStatusRequest")
+}
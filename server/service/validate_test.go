@@ -0,0 +1,211 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+)
+
+// fieldViolations returns the fields of the BadRequest detail of err.
+func fieldViolations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got error %v, want InvalidArgument", err)
+	}
+	var fields []string
+	for _, d := range st.Details() {
+		br, ok := d.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, fv := range br.GetFieldViolations() {
+			fields = append(fields, fv.GetField())
+		}
+	}
+	return fields
+}
+
+func TestValidateBootstrapDataRequest(t *testing.T) {
+	modular := func() *bpb.GetBootstrapDataRequest {
+		return &bpb.GetBootstrapDataRequest{
+			ChassisDescriptor: &bpb.ChassisDescriptor{
+				Manufacturer: "Cisco",
+				PartNumber:   "123",
+				ControlCards: []*bpb.ControlCard{
+					{SerialNumber: "123A", PartNumber: "123A", Slot: 1},
+					{SerialNumber: "123B", PartNumber: "123B", Slot: 2},
+				},
+			},
+			ControlCardState: &bpb.ControlCardState{SerialNumber: "123B"},
+		}
+	}
+	fixed := func() *bpb.GetBootstrapDataRequest {
+		return &bpb.GetBootstrapDataRequest{
+			ChassisDescriptor: &bpb.ChassisDescriptor{
+				Manufacturer: "Cisco",
+				PartNumber:   "456",
+				SerialNumber: "456",
+			},
+		}
+	}
+	tests := []struct {
+		desc   string
+		req    *bpb.GetBootstrapDataRequest
+		modify func(*bpb.GetBootstrapDataRequest)
+		want   []string
+	}{{
+		desc: "valid modular chassis",
+		req:  modular(),
+	}, {
+		desc: "valid fixed form factor chassis",
+		req:  fixed(),
+	}, {
+		desc: "fixed form factor chassis with its state",
+		req:  fixed(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ControlCardState = &bpb.ControlCardState{SerialNumber: "456"}
+		},
+	}, {
+		desc: "missing chassis descriptor",
+		req:  &bpb.GetBootstrapDataRequest{},
+		want: []string{"chassis_descriptor"},
+	}, {
+		desc: "missing chassis fields",
+		req:  fixed(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ChassisDescriptor = &bpb.ChassisDescriptor{}
+		},
+		want: []string{"chassis_descriptor.manufacturer", "chassis_descriptor.part_number", "chassis_descriptor.serial_number"},
+	}, {
+		desc: "state of another fixed form factor chassis",
+		req:  fixed(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ControlCardState = &bpb.ControlCardState{SerialNumber: "789"}
+		},
+		want: []string{"control_card_state.serial_number"},
+	}, {
+		desc: "missing control card fields",
+		req:  modular(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ChassisDescriptor.ControlCards[0] = &bpb.ControlCard{Slot: 1}
+		},
+		want: []string{"chassis_descriptor.control_cards[0].serial_number", "chassis_descriptor.control_cards[0].part_number"},
+	}, {
+		desc: "duplicate slots and serials",
+		req:  modular(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ChassisDescriptor.ControlCards[1].SerialNumber = "123A"
+			r.ChassisDescriptor.ControlCards[1].Slot = 1
+			r.ControlCardState.SerialNumber = "123A"
+		},
+		want: []string{"chassis_descriptor.control_cards[1].serial_number", "chassis_descriptor.control_cards[1].slot"},
+	}, {
+		desc: "missing active control card",
+		req:  modular(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ControlCardState = nil
+		},
+		want: []string{"control_card_state.serial_number"},
+	}, {
+		desc: "active control card not in descriptor",
+		req:  modular(),
+		modify: func(r *bpb.GetBootstrapDataRequest) {
+			r.ControlCardState.SerialNumber = "123C"
+		},
+		want: []string{"control_card_state.serial_number"},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.modify != nil {
+				test.modify(test.req)
+			}
+			got := fieldViolations(t, validateBootstrapDataRequest(test.req))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("validateBootstrapDataRequest() field violations diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateReportStatusRequest(t *testing.T) {
+	tests := []struct {
+		desc   string
+		states []*bpb.ControlCardState
+		want   []string
+	}{{
+		desc:   "valid",
+		states: []*bpb.ControlCardState{{SerialNumber: "123A"}, {SerialNumber: "123B"}},
+	}, {
+		desc: "no states",
+		want: []string{"states"},
+	}, {
+		desc:   "missing and duplicate serials",
+		states: []*bpb.ControlCardState{{SerialNumber: "123A"}, {}, {SerialNumber: "123A"}},
+		want:   []string{"states[1].serial_number", "states[2].serial_number"},
+	}}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := fieldViolations(t, validateReportStatusRequest(&bpb.ReportStatusRequest{States: test.states}))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("validateReportStatusRequest() field violations diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// resolvingEntityManager records the control card serial chassis are resolved by.
+type resolvingEntityManager struct {
+	fakeEntityManager
+	ccSerial string
+}
+
+func (m *resolvingEntityManager) ResolveChassis(_ context.Context, _ *EntityLookup, ccSerial string) (*ChassisEntity, error) {
+	m.ccSerial = ccSerial
+	return &ChassisEntity{}, nil
+}
+
+func TestResolveByActiveControlCard(t *testing.T) {
+	em := &resolvingEntityManager{}
+	s := New(em)
+	_, err := s.GetBootstrapData(peerContext("192.0.2.1"), &bpb.GetBootstrapDataRequest{
+		ChassisDescriptor: &bpb.ChassisDescriptor{
+			Manufacturer: "Cisco",
+			PartNumber:   "123",
+			ControlCards: []*bpb.ControlCard{
+				{SerialNumber: "123A", PartNumber: "123A", Slot: 1},
+				{SerialNumber: "123B", PartNumber: "123B", Slot: 2},
+			},
+		},
+		ControlCardState: &bpb.ControlCardState{SerialNumber: "123B"},
+	})
+	if err != nil {
+		t.Fatalf("GetBootstrapData() err = %v", err)
+	}
+	if em.ccSerial != "123B" {
+		t.Errorf("GetBootstrapData() resolved the chassis by control card %q, want the active card 123B", em.ccSerial)
+	}
+}
@@ -0,0 +1,579 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook posts bootstrap lifecycle events to external HTTP endpoints.
+//
+// Events are taken from the events bus, serialized as JSON and signed with an
+// HMAC-SHA256 of the body in the X-Bootz-Signature header. Deliveries are retried
+// with exponential backoff and, if a queue directory is configured, persisted so
+// that pending deliveries survive restarts.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/bootz/server/atomicfile"
+	"github.com/openconfig/bootz/server/events"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	log "github.com/golang/glog"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+const (
+	// SignatureHeader holds "sha256=" followed by the hex encoded HMAC-SHA256 of the body.
+	SignatureHeader = "X-Bootz-Signature"
+	// EventIDHeader holds the ID of the event, which receivers may use to drop duplicates.
+	EventIDHeader = "X-Bootz-Event-Id"
+	// GapType is the type of the event delivered when events were dropped from the events bus
+	// before the dispatcher could queue them, or from the queue before they were delivered.
+	GapType = "events_dropped"
+)
+
+// Event is the JSON payload posted to webhooks.
+type Event struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	Manufacturer  string    `json:"manufacturer,omitempty"`
+	ChassisSerial string    `json:"chassis_serial,omitempty"`
+	Serials       []string  `json:"serials,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Source        string    `json:"source,omitempty"`
+	SessionID     string    `json:"session_id,omitempty"`
+}
+
+// delivery is a pending POST of an event to a URL.
+type delivery struct {
+	// ID names the delivery, and its file in the queue directory.
+	ID          string          `json:"id"`
+	URL         string          `json:"url"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	// Dropped is the number of deliveries to the URL a gap delivery reports as dropped from the
+	// queue, from the event FirstDropped to the event LastDropped. It is zero for other deliveries.
+	Dropped      int    `json:"dropped,omitempty"`
+	FirstDropped string `json:"first_dropped,omitempty"`
+	LastDropped  string `json:"last_dropped,omitempty"`
+}
+
+// Config configures a Dispatcher.
+type Config struct {
+	// URLs are the endpoints every event is posted to.
+	URLs []string
+	// Secret is the HMAC key used to sign the payloads. It is required if URLs are set.
+	Secret []byte
+	// QueueDir is the directory pending deliveries are persisted to. If empty, they are only kept in memory.
+	QueueDir string
+	// InitialBackoff and MaxBackoff bound the delay between attempts. The delay doubles after each failure.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is the number of attempts after which a delivery is dropped. Zero means no limit.
+	MaxAttempts int
+	// MaxPending is the number of deliveries kept pending per URL, beyond which the oldest is
+	// dropped. Zero means no limit.
+	MaxPending int
+	// Client is the HTTP client used for deliveries. Defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// Dispatcher delivers events from the events bus to the configured webhooks.
+type Dispatcher struct {
+	cfg Config
+
+	mu      sync.Mutex
+	pending []*delivery
+	// last maps a chassis to the last bootstrap status delivered for it, so that repeated reports
+	// without a change of state are not delivered.
+	last map[string]string
+	// wake is signalled when a delivery is queued.
+	wake chan struct{}
+	seq  uint64
+	// sending is the delivery being posted, which gap deliveries are not merged into.
+	sending *delivery
+}
+
+// New returns a dispatcher for the provided config, loading any deliveries left in the queue directory.
+func New(cfg Config) (*Dispatcher, error) {
+	if len(cfg.URLs) > 0 && len(cfg.Secret) == 0 {
+		return nil, errors.New("a secret is required to sign webhook payloads")
+	}
+	if cfg.InitialBackoff == 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	d := &Dispatcher{
+		cfg:  cfg,
+		last: map[string]string{},
+		wake: make(chan struct{}, 1),
+	}
+	if cfg.QueueDir != "" {
+		if err := os.MkdirAll(cfg.QueueDir, 0700); err != nil {
+			return nil, err
+		}
+		if err := d.load(); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// load reads the deliveries persisted in the queue directory. Deliveries to URLs which are no longer configured are dropped.
+func (d *Dispatcher) load() error {
+	files, err := filepath.Glob(filepath.Join(d.cfg.QueueDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	urls := map[string]bool{}
+	for _, u := range d.cfg.URLs {
+		urls[u] = true
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		dl := &delivery{}
+		if err := json.Unmarshal(b, dl); err != nil {
+			log.Errorf("Dropping malformed webhook delivery %v: %v", f, err)
+			os.Remove(f)
+			continue
+		}
+		if !urls[dl.URL] {
+			log.Warningf("Dropping webhook delivery %v to unconfigured URL %v", dl.ID, dl.URL)
+			os.Remove(f)
+			continue
+		}
+		d.pending = append(d.pending, dl)
+	}
+	if len(d.pending) > 0 {
+		log.Infof("Loaded %d pending webhook deliveries from %v", len(d.pending), d.cfg.QueueDir)
+	}
+	return nil
+}
+
+// persist writes the delivery to the queue directory, if any.
+func (d *Dispatcher) persist(dl *delivery) error {
+	if d.cfg.QueueDir == "" {
+		return nil
+	}
+	b, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	// Replace the file atomically so that a crash never leaves a partial delivery behind.
+	return atomicfile.WriteFile(filepath.Join(d.cfg.QueueDir, dl.ID+".json"), b, 0600)
+}
+
+func (d *Dispatcher) remove(dl *delivery) {
+	if d.cfg.QueueDir == "" {
+		return
+	}
+	if err := os.Remove(filepath.Join(d.cfg.QueueDir, dl.ID+".json")); err != nil && !os.IsNotExist(err) {
+		log.Errorf("Unable to remove webhook delivery %v: %v", dl.ID, err)
+	}
+}
+
+// deliverable reports whether the event is delivered to webhooks: rejected requests, timeouts and
+// status reports which change the bootstrap status of the chassis. Serving bootstrap data starts
+// a new attempt, after which the next status report is always delivered.
+func (d *Dispatcher) deliverable(e *apb.Event) bool {
+	key := e.GetManufacturer() + "/" + e.GetChassisSerial()
+	switch e.GetType() {
+	case apb.EventType_EVENT_TYPE_DATA_SERVED:
+		d.mu.Lock()
+		delete(d.last, key)
+		d.mu.Unlock()
+	case apb.EventType_EVENT_TYPE_REQUEST_FAILED, apb.EventType_EVENT_TYPE_SESSION_TIMED_OUT:
+		return true
+	case apb.EventType_EVENT_TYPE_STATUS_REPORTED, apb.EventType_EVENT_TYPE_BOOTSTRAP_FAILED:
+		st := e.GetBootstrapStatus().String()
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.last[key] == st {
+			return false
+		}
+		d.last[key] = st
+		return true
+	}
+	return false
+}
+
+// payload converts a bus event to its JSON payload.
+func payload(e *apb.Event) ([]byte, error) {
+	ev := &Event{
+		ID:            e.GetCursor(),
+		Type:          strings.ToLower(strings.TrimPrefix(e.GetType().String(), "EVENT_TYPE_")),
+		Time:          e.GetTimestamp().AsTime(),
+		Manufacturer:  e.GetManufacturer(),
+		ChassisSerial: e.GetChassisSerial(),
+		Serials:       e.GetControlCardSerials(),
+		Message:       e.GetMessage(),
+		Source:        e.GetSource(),
+		SessionID:     e.GetSessionId(),
+	}
+	if e.GetBootstrapStatus() != 0 {
+		ev.Status = e.GetBootstrapStatus().String()
+	}
+	return json.Marshal(ev)
+}
+
+// Enqueue queues the delivery of the event to every configured URL.
+func (d *Dispatcher) Enqueue(e *apb.Event) error {
+	b, err := payload(e)
+	if err != nil {
+		return err
+	}
+	return d.enqueue(b)
+}
+
+// enqueueGap queues the delivery of a gap event, telling receivers that the events after the
+// cursor up to and including the resume cursor were not delivered. The status of every chassis
+// is forgotten, so that its next status report is delivered.
+func (d *Dispatcher) enqueueGap(cursor, resume string) error {
+	d.mu.Lock()
+	d.last = map[string]string{}
+	d.mu.Unlock()
+	b, err := json.Marshal(&Event{
+		ID:      resume + "-gap",
+		Type:    GapType,
+		Time:    time.Now(),
+		Message: fmt.Sprintf("events after %v up to %v were dropped before delivery", cursor, resume),
+	})
+	if err != nil {
+		return err
+	}
+	return d.enqueue(b)
+}
+
+// enqueue queues the delivery of the payload to every configured URL.
+func (d *Dispatcher) enqueue(b []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	for _, u := range d.cfg.URLs {
+		dl := &delivery{
+			ID:          d.newID(now),
+			URL:         u,
+			Payload:     b,
+			NextAttempt: now,
+		}
+		if err := d.persist(dl); err != nil {
+			return fmt.Errorf("unable to persist webhook delivery: %v", err)
+		}
+		d.pending = append(d.pending, dl)
+		d.trim(u)
+	}
+	d.signal()
+	return nil
+}
+
+// newID returns the ID of a delivery queued at the provided time. The caller must hold d.mu.
+func (d *Dispatcher) newID(now time.Time) string {
+	d.seq++
+	return fmt.Sprintf("%020d-%04d", now.UnixNano(), d.seq%10000)
+}
+
+// signal wakes the delivery loop up.
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// trim drops the oldest deliveries to the URL while more than MaxPending are pending. Gap
+// deliveries are not counted, as there are at most two per URL. The caller must hold d.mu.
+func (d *Dispatcher) trim(url string) {
+	if d.cfg.MaxPending <= 0 {
+		return
+	}
+	for {
+		var oldest *delivery
+		n := 0
+		for _, dl := range d.pending {
+			if dl.URL != url || dl.Dropped > 0 {
+				continue
+			}
+			n++
+			if dl != d.sending && (oldest == nil || dl.ID < oldest.ID) {
+				oldest = dl
+			}
+		}
+		if n <= d.cfg.MaxPending || oldest == nil {
+			return
+		}
+		log.Errorf("Dropping webhook delivery %v to %v, more than %d deliveries are pending", oldest.ID, oldest.URL, d.cfg.MaxPending)
+		d.discard(oldest)
+	}
+}
+
+// discard drops a delivery which was not delivered, and reports it in a gap delivery to its URL.
+// A pending gap delivery which is not being posted is updated to cover the delivery, so that a
+// failing endpoint gets a single gap event for all the events it missed. The caller must hold d.mu.
+func (d *Dispatcher) discard(dl *delivery) {
+	d.drop(dl)
+	var gap *delivery
+	for _, p := range d.pending {
+		if p.URL == dl.URL && p.Dropped > 0 && p != d.sending {
+			gap = p
+			break
+		}
+	}
+	now := time.Now()
+	if gap == nil {
+		gap = &delivery{ID: d.newID(now), URL: dl.URL, NextAttempt: now}
+		d.pending = append(d.pending, gap)
+	}
+	first, last, n := dl.FirstDropped, dl.LastDropped, dl.Dropped
+	if n == 0 {
+		ev := &Event{}
+		if err := json.Unmarshal(dl.Payload, ev); err != nil {
+			log.Errorf("Unable to read the event of webhook delivery %v: %v", dl.ID, err)
+		}
+		first, last, n = ev.ID, ev.ID, 1
+	}
+	if gap.Dropped == 0 {
+		gap.FirstDropped = first
+	}
+	gap.LastDropped = last
+	gap.Dropped += n
+	b, err := json.Marshal(&Event{
+		ID:      gap.FirstDropped + "-dropped",
+		Type:    GapType,
+		Time:    now,
+		Message: fmt.Sprintf("%d events from %v to %v were dropped before delivery", gap.Dropped, gap.FirstDropped, gap.LastDropped),
+	})
+	if err != nil {
+		log.Errorf("Unable to queue webhook delivery of the gap after %v: %v", dl.ID, err)
+		return
+	}
+	gap.Payload = b
+	if err := d.persist(gap); err != nil {
+		log.Errorf("Unable to persist webhook delivery %v: %v", gap.ID, err)
+	}
+	d.signal()
+}
+
+// Pending returns the number of deliveries which have not succeeded yet.
+func (d *Dispatcher) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// Start watches the bus for events to deliver and delivers them in the background until the context is done.
+// Only events published after Start are delivered. If bus is nil, only the deliveries queued with Enqueue are.
+func (d *Dispatcher) Start(ctx context.Context, bus *events.Bus) {
+	if bus != nil {
+		go d.watch(ctx, bus, bus.Cursor())
+	}
+	go d.run(ctx)
+}
+
+// watch queues the deliverable events after the cursor until the context is done. If the
+// dispatcher falls behind and events are dropped from the bus before they are queued, it
+// resumes from the oldest buffered event and queues a gap event.
+func (d *Dispatcher) watch(ctx context.Context, bus *events.Bus, cursor string) {
+	for {
+		err := bus.Watch(ctx, cursor, &events.Filter{}, func(e *apb.Event) error {
+			cursor = e.GetCursor()
+			if !d.deliverable(e) {
+				return nil
+			}
+			if err := d.Enqueue(e); err != nil {
+				log.Errorf("Unable to queue webhook delivery of event %v: %v", e.GetCursor(), err)
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) != codes.OutOfRange {
+			log.Errorf("Webhook dispatcher stopped watching events: %v", err)
+			return
+		}
+		resume := bus.OldestCursor()
+		log.Warningf("Webhook dispatcher fell behind the events bus, resuming from %v: %v", resume, err)
+		if err := d.enqueueGap(cursor, resume); err != nil {
+			log.Errorf("Unable to queue webhook delivery of the gap before %v: %v", resume, err)
+		}
+		cursor = resume
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(d.deliverDue(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// deliverDue attempts the deliveries which are due, oldest first, and returns the time until the next one is.
+func (d *Dispatcher) deliverDue(ctx context.Context) time.Duration {
+	d.mu.Lock()
+	now := time.Now()
+	var due []*delivery
+	for _, dl := range d.pending {
+		if !dl.NextAttempt.After(now) {
+			due = append(due, dl)
+		}
+	}
+	d.mu.Unlock()
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+
+	for _, dl := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		d.mu.Lock()
+		if !d.isPending(dl) {
+			// Dropped from the queue since it was found due.
+			d.mu.Unlock()
+			continue
+		}
+		d.sending = dl
+		d.mu.Unlock()
+		err := d.post(ctx, dl)
+		d.mu.Lock()
+		d.sending = nil
+		if err == nil {
+			d.drop(dl)
+			d.mu.Unlock()
+			continue
+		}
+		dl.Attempts++
+		// Gap deliveries are retried until they succeed, as dropping them would lose the count
+		// of events the receiver missed.
+		if d.cfg.MaxAttempts > 0 && dl.Attempts >= d.cfg.MaxAttempts && dl.Dropped == 0 {
+			log.Errorf("Dropping webhook delivery %v to %v after %d attempts: %v", dl.ID, dl.URL, dl.Attempts, err)
+			d.discard(dl)
+			d.mu.Unlock()
+			continue
+		}
+		backoff := d.backoff(dl.Attempts)
+		dl.NextAttempt = time.Now().Add(backoff)
+		log.Warningf("Webhook delivery %v to %v failed (attempt %d), retrying in %v: %v", dl.ID, dl.URL, dl.Attempts, backoff, err)
+		if err := d.persist(dl); err != nil {
+			log.Errorf("Unable to persist webhook delivery %v: %v", dl.ID, err)
+		}
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	wait := d.cfg.MaxBackoff
+	now = time.Now()
+	for _, dl := range d.pending {
+		if w := dl.NextAttempt.Sub(now); w < wait {
+			wait = w
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// isPending reports whether the delivery is still queued. The caller must hold d.mu.
+func (d *Dispatcher) isPending(dl *delivery) bool {
+	for _, p := range d.pending {
+		if p == dl {
+			return true
+		}
+	}
+	return false
+}
+
+// drop removes a delivery from the queue. The caller must hold d.mu.
+func (d *Dispatcher) drop(dl *delivery) {
+	for i, p := range d.pending {
+		if p == dl {
+			d.pending = append(d.pending[:i], d.pending[i+1:]...)
+			break
+		}
+	}
+	d.remove(dl)
+}
+
+// backoff returns the delay before the next attempt after the provided number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.cfg.InitialBackoff
+	for i := 1; i < attempts && b < d.cfg.MaxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.cfg.MaxBackoff {
+		b = d.cfg.MaxBackoff
+	}
+	return b
+}
+
+// Sign returns the value of the signature header for the body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// post sends the delivery and returns an error unless the endpoint answers with a 2xx status.
+func (d *Dispatcher) post(ctx context.Context, dl *delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.cfg.Secret, dl.Payload))
+	ev := &Event{}
+	if err := json.Unmarshal(dl.Payload, ev); err == nil {
+		req.Header.Set(EventIDHeader, ev.ID)
+	}
+	resp, err := d.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/bootz/server/events"
+
+	bpb "github.com/openconfig/bootz/proto/bootz"
+	apb "github.com/openconfig/bootz/server/admin/proto/admin"
+)
+
+var secret = []byte("s3cret")
+
+// receiver is a webhook endpoint which fails the first failures requests and records the events it accepts.
+type receiver struct {
+	t        *testing.T
+	mu       sync.Mutex
+	failures int
+	requests int
+	events   []*Event
+	received chan struct{}
+}
+
+func newReceiver(t *testing.T, failures int) (*receiver, *httptest.Server) {
+	r := &receiver{t: t, failures: failures, received: make(chan struct{}, 100)}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return r, srv
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("ReadAll() err = %v", err)
+	}
+	if got, want := req.Header.Get(SignatureHeader), Sign(secret, body); got != want {
+		r.t.Errorf("%v = %q, want %q", SignatureHeader, got, want)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if r.requests <= r.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	ev := &Event{}
+	if err := json.Unmarshal(body, ev); err != nil {
+		r.t.Errorf("Unmarshal() err = %v", err)
+	}
+	r.events = append(r.events, ev)
+	r.received <- struct{}{}
+}
+
+func (r *receiver) wait(t *testing.T, n int) []*Event {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for event %d of %d", i+1, n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Event(nil), r.events...)
+}
+
+func eventTypes(evs []*Event) []string {
+	var out []string
+	for _, e := range evs {
+		out = append(out, e.Type+":"+e.Status)
+	}
+	return out
+}
+
+func TestDispatcher(t *testing.T) {
+	r, srv := newReceiver(t, 2)
+	d, err := New(Config{
+		URLs:           []string{srv.URL},
+		Secret:         secret,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	bus := events.NewBus(16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx, bus)
+
+	status := func(st bpb.ReportStatusRequest_BootstrapStatus) *apb.Event {
+		return &apb.Event{
+			Type:               apb.EventType_EVENT_TYPE_STATUS_REPORTED,
+			Manufacturer:       "Cisco",
+			ChassisSerial:      "123",
+			ControlCardSerials: []string{"123A"},
+			BootstrapStatus:    st,
+		}
+	}
+	bus.Publish(status(bpb.ReportStatusRequest_BOOTSTRAP_STATUS_INITIATED))
+	// A repeated status is not delivered.
+	bus.Publish(status(bpb.ReportStatusRequest_BOOTSTRAP_STATUS_INITIATED))
+	// Requests and served data are not delivered.
+	bus.Publish(&apb.Event{Type: apb.EventType_EVENT_TYPE_BOOTSTRAP_REQUESTED, ChassisSerial: "123"})
+	bus.Publish(status(bpb.ReportStatusRequest_BOOTSTRAP_STATUS_SUCCESS))
+	bus.Publish(&apb.Event{Type: apb.EventType_EVENT_TYPE_REQUEST_FAILED, ChassisSerial: "123", Message: "denied"})
+
+	// Failed deliveries are retried independently, so events may arrive out of order.
+	got := r.wait(t, 3)
+	sort.Slice(got, func(i, j int) bool { return got[i].ID < got[j].ID })
+	want := []string{
+		"status_reported:BOOTSTRAP_STATUS_INITIATED",
+		"status_reported:BOOTSTRAP_STATUS_SUCCESS",
+		"request_failed:",
+	}
+	if diff := cmp.Diff(want, eventTypes(got)); diff != "" {
+		t.Errorf("delivered events differ (-want +got):\n%s", diff)
+	}
+	if got[0].ChassisSerial != "123" || got[0].ID == "" || got[0].Time.IsZero() {
+		t.Errorf("delivered event %+v is missing fields", got[0])
+	}
+	if got[2].Message != "denied" {
+		t.Errorf("delivered message = %q, want %q", got[2].Message, "denied")
+	}
+	r.mu.Lock()
+	if r.requests != 5 {
+		t.Errorf("receiver got %d requests, want 5 including 2 failures", r.requests)
+	}
+	r.mu.Unlock()
+}
+
+func TestGap(t *testing.T) {
+	r, srv := newReceiver(t, 0)
+	d, err := New(Config{URLs: []string{srv.URL}, Secret: secret})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	// The events after the cursor are dropped from the bus before the dispatcher watches it.
+	bus := events.NewBus(2)
+	cursor := bus.Cursor()
+	for _, serial := range []string{"1", "2", "3", "4"} {
+		bus.Publish(&apb.Event{Type: apb.EventType_EVENT_TYPE_REQUEST_FAILED, ChassisSerial: serial})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.watch(ctx, bus, cursor)
+	go d.run(ctx)
+
+	var got []string
+	for _, e := range r.wait(t, 3) {
+		got = append(got, e.Type+":"+e.ChassisSerial)
+	}
+	sort.Strings(got)
+	want := []string{GapType + ":", "request_failed:3", "request_failed:4"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("delivered events differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestSecretRequired(t *testing.T) {
+	if _, err := New(Config{URLs: []string{"http://localhost:1/hook"}}); err == nil {
+		t.Errorf("New() without a secret succeeded, want error")
+	}
+}
+
+func TestMaxAttempts(t *testing.T) {
+	r, srv := newReceiver(t, 1000)
+	d, err := New(Config{
+		URLs:           []string{srv.URL},
+		Secret:         secret,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		MaxAttempts:    3,
+	})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if err := d.Enqueue(&apb.Event{Type: apb.EventType_EVENT_TYPE_REQUEST_FAILED, Cursor: "e1"}); err != nil {
+		t.Fatalf("Enqueue() err = %v", err)
+	}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * time.Millisecond)
+		d.deliverDue(ctx)
+	}
+	// The dropped delivery is replaced with a gap delivery, which is retried without limit.
+	d.mu.Lock()
+	var gaps []string
+	for _, dl := range d.pending {
+		gaps = append(gaps, fmt.Sprintf("%d:%v-%v", dl.Dropped, dl.FirstDropped, dl.LastDropped))
+	}
+	d.mu.Unlock()
+	if diff := cmp.Diff([]string{"1:e1-e1"}, gaps); diff != "" {
+		t.Errorf("pending deliveries after max attempts differ (-want +got):\n%s", diff)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.requests != 3 {
+		t.Errorf("receiver got %d requests, want 3", r.requests)
+	}
+}
+
+// TestMaxPending tests that the queue stays bounded while the endpoint fails, and that the
+// dropped deliveries are reported in a single gap event once it recovers.
+func TestMaxPending(t *testing.T) {
+	r, srv := newReceiver(t, 1000)
+	d, err := New(Config{
+		URLs:           []string{srv.URL},
+		Secret:         secret,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		MaxPending:     3,
+	})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	ctx := context.Background()
+	for i := 1; i <= 10; i++ {
+		if err := d.Enqueue(&apb.Event{Type: apb.EventType_EVENT_TYPE_REQUEST_FAILED, Cursor: fmt.Sprintf("e%02d", i)}); err != nil {
+			t.Fatalf("Enqueue() err = %v", err)
+		}
+		d.deliverDue(ctx)
+		if got := d.Pending(); got > 5 {
+			t.Fatalf("Pending() = %d after %d events, want at most 5", got, i)
+		}
+	}
+
+	r.mu.Lock()
+	r.failures = 0
+	r.mu.Unlock()
+	time.Sleep(2 * time.Millisecond)
+	d.deliverDue(ctx)
+	got := r.wait(t, 4)
+	var ids []string
+	for _, e := range got {
+		ids = append(ids, e.ID)
+		if e.Type == GapType {
+			if want := "7 events from e01 to e07 were dropped before delivery"; e.Message != want {
+				t.Errorf("gap event message = %q, want %q", e.Message, want)
+			}
+		}
+	}
+	sort.Strings(ids)
+	if diff := cmp.Diff([]string{"e01-dropped", "e08", "e09", "e10"}, ids); diff != "" {
+		t.Errorf("delivered events differ (-want +got):\n%s", diff)
+	}
+	if got := d.Pending(); got != 0 {
+		t.Errorf("Pending() = %d after the endpoint recovered, want 0", got)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	dir := t.TempDir()
+	r, srv := newReceiver(t, 1)
+	cfg := Config{URLs: []string{srv.URL}, Secret: secret, QueueDir: dir, InitialBackoff: time.Millisecond}
+
+	// The first attempt fails and the dispatcher is stopped with the delivery pending.
+	d, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if err := d.Enqueue(&apb.Event{Type: apb.EventType_EVENT_TYPE_SESSION_TIMED_OUT, ChassisSerial: "123", Cursor: "e1"}); err != nil {
+		t.Fatalf("Enqueue() err = %v", err)
+	}
+	d.deliverDue(context.Background())
+	if got := d.Pending(); got != 1 {
+		t.Fatalf("Pending() = %d, want 1", got)
+	}
+
+	// The delivery is sent after a restart, and removed from the queue.
+	d, err = New(cfg)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if got := d.Pending(); got != 1 {
+		t.Fatalf("Pending() = %d after restart, want 1", got)
+	}
+	time.Sleep(5 * time.Millisecond)
+	d.deliverDue(context.Background())
+	got := r.wait(t, 1)
+	if got[0].ID != "e1" || got[0].ChassisSerial != "123" {
+		t.Errorf("delivered event = %+v, want ID e1 for chassis 123", got[0])
+	}
+	d, err = New(cfg)
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if got := d.Pending(); got != 0 {
+		t.Errorf("Pending() = %d after delivery and restart, want 0", got)
+	}
+
+	// Deliveries to URLs which are no longer configured are dropped on restart.
+	if err := d.Enqueue(&apb.Event{Type: apb.EventType_EVENT_TYPE_SESSION_TIMED_OUT}); err != nil {
+		t.Fatalf("Enqueue() err = %v", err)
+	}
+	d, err = New(Config{URLs: []string{"http://localhost:1/other"}, Secret: secret, QueueDir: dir})
+	if err != nil {
+		t.Fatalf("New() err = %v", err)
+	}
+	if got := d.Pending(); got != 0 {
+		t.Errorf("Pending() = %d after changing URLs, want 0", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{cfg: Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	var got []time.Duration
+	for i := 1; i <= 5; i++ {
+		got = append(got, d.backoff(i))
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("backoff() differs (-want +got):\n%s", diff)
+	}
+}
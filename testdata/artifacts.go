@@ -23,13 +23,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/json"
 	"encoding/pem"
 	"math/big"
+	"net"
 	"time"
 
+	"github.com/openconfig/bootz/common/certserial"
+	ownershipvoucher "github.com/openconfig/bootz/common/ownership_voucher"
 	"github.com/openconfig/bootz/server/service"
-	"go.mozilla.org/pkcs7"
 )
 
 const (
@@ -37,23 +38,16 @@ const (
 	caCountry  = "US"
 	caProvince = "CA"
 	caLocality = "Mountain View"
-	ovExpiry   = time.Hour * 24 * 365
 )
 
-// OwnershipVoucher wraps Inner.
-type OwnershipVoucher struct {
-	OV Inner `json:"ietf-voucher:voucher"`
-}
+// DefaultTLSHosts are the hostnames and IPs the TLS serving certificate is valid for by default.
+var DefaultTLSHosts = []string{"localhost", "127.0.0.1", "::1"}
 
-// Inner defines the Ownership Voucher format. See https://www.rfc-editor.org/rfc/rfc8366.html.
-type Inner struct {
-	CreatedOn                  string `json:"created-on"`
-	ExpiresOn                  string `json:"expires-on"`
-	SerialNumber               string `json:"serial-number"`
-	Assertion                  string `json:"assertion"`
-	PinnedDomainCert           []byte `json:"pinned-domain-cert"`
-	DomainCertRevocationChecks bool   `json:"domain-cert-revocation-checks"`
-}
+// OwnershipVoucher is kept as an alias for callers of this package, see ownershipvoucher.OwnershipVoucher.
+type OwnershipVoucher = ownershipvoucher.OwnershipVoucher
+
+// Inner is kept as an alias for callers of this package, see ownershipvoucher.Inner.
+type Inner = ownershipvoucher.Inner
 
 // NewCertificateAuthority creates a new self-signed CA for the chosen organization.
 func NewCertificateAuthority(commonName, org, serverName string) (*x509.Certificate, *rsa.PrivateKey, error) {
@@ -129,6 +123,122 @@ func NewSignedCertificate(commonName, org, serverName string, ca *x509.Certifica
 	return cert, privateKey, nil
 }
 
+// NewIntermediateCertificateAuthority creates a new CA signed by the provided parent CA.
+func NewIntermediateCertificateAuthority(commonName, org string, parent *x509.Certificate, parentPrivateKey crypto.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	serial, err := certserial.Random()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{org},
+			Country:      []string{caCountry},
+			Province:     []string{caProvince},
+			Locality:     []string{caLocality},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(5, 0, 0),
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	return signCertificate(template, parent, parentPrivateKey)
+}
+
+// NewServingCertificate creates a new TLS server leaf certificate signed by the provided CA.
+// Each host is added to the certificate as an IP SAN if it is an IP address, and as a DNS SAN otherwise.
+func NewServingCertificate(commonName, org string, hosts []string, ca *x509.Certificate, caPrivateKey crypto.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	serial, err := certserial.Random()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{org},
+			Country:      []string{caCountry},
+			Province:     []string{caProvince},
+			Locality:     []string{caLocality},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+	return signCertificate(template, ca, caPrivateKey)
+}
+
+// NewIDevID creates a new IDevID certificate for the control card or chassis with the provided serial number,
+// signed by the vendor's CA. The serial number is stored in the subject's serialNumber attribute.
+func NewIDevID(serial, vendorOrg string, vendorCA *x509.Certificate, vendorCAPrivateKey crypto.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	certSerial, err := certserial.Random()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: certSerial,
+		Subject: pkix.Name{
+			CommonName:   serial,
+			SerialNumber: serial,
+			Organization: []string{vendorOrg},
+			Country:      []string{caCountry},
+			Province:     []string{caProvince},
+			Locality:     []string{caLocality},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(20, 0, 0),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	return signCertificate(template, vendorCA, vendorCAPrivateKey)
+}
+
+// CertificatePEM returns the PEM encoding of the certificate.
+func CertificatePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: cert.Raw,
+	})
+}
+
+// PrivateKeyPEM returns the PEM encoding of the RSA private key.
+func PrivateKeyPEM(privateKey *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+}
+
+// signCertificate generates a key pair and signs the template with the provided CA.
+func signCertificate(template, ca *x509.Certificate, caPrivateKey crypto.PrivateKey) (*x509.Certificate, *rsa.PrivateKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, ca, &privateKey.PublicKey, caPrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, privateKey, nil
+}
+
 // TLSCertificate creates a new TLS trust anchor for use with the server's gRPC connection.
 func TLSCertificate(cert *x509.Certificate, privateKey *rsa.PrivateKey) (*tls.Certificate, error) {
 	certPEM := new(bytes.Buffer)
@@ -154,40 +264,36 @@ func TLSCertificate(cert *x509.Certificate, privateKey *rsa.PrivateKey) (*tls.Ce
 	return &tlsCert, nil
 }
 
-// NewOwnershipVoucher generates an Ownership Voucher which is signed by the vendor's CA.
-func NewOwnershipVoucher(serial string, pdc, vendorCACert *x509.Certificate, vendorCAPriv crypto.PrivateKey) ([]byte, error) {
-	currentTime := time.Now()
-	ov := OwnershipVoucher{
-		OV: Inner{
-			CreatedOn:        currentTime.Format(time.RFC3339),
-			ExpiresOn:        currentTime.Add(ovExpiry).Format(time.RFC3339),
-			SerialNumber:     serial,
-			PinnedDomainCert: pdc.Raw,
-		},
-	}
-
-	ovBytes, err := json.Marshal(ov)
+// TLSCertificateChain creates a TLS certificate for the leaf certificate which also presents
+// the provided intermediate certificates during the handshake.
+func TLSCertificateChain(leaf *x509.Certificate, privateKey *rsa.PrivateKey, intermediates ...*x509.Certificate) (*tls.Certificate, error) {
+	tlsCert, err := TLSCertificate(leaf, privateKey)
 	if err != nil {
 		return nil, err
 	}
-
-	signedMessage, err := pkcs7.NewSignedData(ovBytes)
-	if err != nil {
-		return nil, err
+	for _, c := range intermediates {
+		tlsCert.Certificate = append(tlsCert.Certificate, c.Raw)
 	}
-	signedMessage.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
-	signedMessage.SetEncryptionAlgorithm(pkcs7.OIDEncryptionAlgorithmRSA)
+	return tlsCert, nil
+}
 
-	err = signedMessage.AddSigner(vendorCACert, vendorCAPriv, pkcs7.SignerInfoConfig{})
-	if err != nil {
-		return nil, err
-	}
+// NewOwnershipVoucher generates an Ownership Voucher which is signed by the vendor's CA.
+func NewOwnershipVoucher(serial string, pdc, vendorCACert *x509.Certificate, vendorCAPriv crypto.PrivateKey) ([]byte, error) {
+	return ownershipvoucher.New(serial, pdc, vendorCACert, vendorCAPriv)
+}
 
-	return signedMessage.Finish()
+// NewOwnershipVoucherWithValidity generates an Ownership Voucher which is signed by the vendor's CA
+// and is valid between the provided times.
+func NewOwnershipVoucherWithValidity(serial string, pdc, vendorCACert *x509.Certificate, vendorCAPriv crypto.PrivateKey, createdOn, expiresOn time.Time) ([]byte, error) {
+	return ownershipvoucher.NewWithValidity(serial, pdc, vendorCACert, vendorCAPriv, createdOn, expiresOn)
 }
 
 // GenerateSecurityArtifacts generates security artifacts.
-func GenerateSecurityArtifacts(controlCardSerials []string, ownerOrg string, vendorOrg string) (*service.SecurityArtifacts, error) {
+// The TLS serving certificate is valid for the provided hosts, or DefaultTLSHosts if none are provided.
+func GenerateSecurityArtifacts(controlCardSerials []string, ownerOrg string, vendorOrg string, tlsHosts ...string) (*service.SecurityArtifacts, error) {
+	if len(tlsHosts) == 0 {
+		tlsHosts = DefaultTLSHosts
+	}
 	pdc, pdcPrivateKey, err := NewCertificateAuthority("Pinned Domain Cert", ownerOrg, "localhost")
 	if err != nil {
 		return nil, err
@@ -212,21 +318,31 @@ func GenerateSecurityArtifacts(controlCardSerials []string, ownerOrg string, ven
 		}
 		ovs[serial] = ov
 	}
-	tlsTrustAnchor, err := TLSCertificate(trustAnchor, trustAnchorPrivatekey)
+	tlsIntermediate, tlsIntermediatePrivateKey, err := NewIntermediateCertificateAuthority("TLS Intermediate CA", ownerOrg, trustAnchor, trustAnchorPrivatekey)
+	if err != nil {
+		return nil, err
+	}
+	leaf, leafPrivateKey, err := NewServingCertificate("Bootz Server", ownerOrg, tlsHosts, tlsIntermediate, tlsIntermediatePrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsKeypair, err := TLSCertificateChain(leaf, leafPrivateKey, tlsIntermediate)
 	if err != nil {
 		return nil, err
 	}
 
 	return &service.SecurityArtifacts{
-		TrustAnchor:           trustAnchor,
-		TrustAnchorPrivateKey: trustAnchorPrivatekey,
-		OwnerCert:             oc,
-		OwnerCertPrivateKey:   ocPrivateKey,
-		PDC:                   pdc,
-		PDCPrivateKey:         pdcPrivateKey,
-		VendorCA:              vendorCA,
-		VendorCAPrivateKey:    vendorCAPrivateKey,
-		OV:                    ovs,
-		TLSKeypair:            tlsTrustAnchor,
+		TrustAnchor:               trustAnchor,
+		TrustAnchorPrivateKey:     trustAnchorPrivatekey,
+		TLSIntermediate:           tlsIntermediate,
+		TLSIntermediatePrivateKey: tlsIntermediatePrivateKey,
+		OwnerCert:                 oc,
+		OwnerCertPrivateKey:       ocPrivateKey,
+		PDC:                       pdc,
+		PDCPrivateKey:             pdcPrivateKey,
+		VendorCA:                  vendorCA,
+		VendorCAPrivateKey:        vendorCAPrivateKey,
+		OV:                        ovs,
+		TLSKeypair:                tlsKeypair,
 	}, nil
 }